@@ -19,12 +19,15 @@ import (
 	"github.com/qdm12/gluetun/internal/command"
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/configuration/sources/files"
+	"github.com/qdm12/gluetun/internal/configuration/sources/prefixed"
 	"github.com/qdm12/gluetun/internal/configuration/sources/secrets"
 	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/dns"
+	"github.com/qdm12/gluetun/internal/events"
 	"github.com/qdm12/gluetun/internal/firewall"
 	"github.com/qdm12/gluetun/internal/healthcheck"
 	"github.com/qdm12/gluetun/internal/httpproxy"
+	"github.com/qdm12/gluetun/internal/metrics"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/netlink"
 	"github.com/qdm12/gluetun/internal/openvpn"
@@ -37,8 +40,10 @@ import (
 	"github.com/qdm12/gluetun/internal/routing"
 	"github.com/qdm12/gluetun/internal/server"
 	"github.com/qdm12/gluetun/internal/shadowsocks"
+	"github.com/qdm12/gluetun/internal/splittunnel"
 	"github.com/qdm12/gluetun/internal/storage"
 	"github.com/qdm12/gluetun/internal/tun"
+	"github.com/qdm12/gluetun/internal/tunnel"
 	updater "github.com/qdm12/gluetun/internal/updater/loop"
 	"github.com/qdm12/gluetun/internal/updater/resolver"
 	"github.com/qdm12/gluetun/internal/updater/unzip"
@@ -81,21 +86,30 @@ func main() {
 	cli := cli.New()
 	cmder := command.New()
 
+	sources := []reader.Source{
+		secrets.New(logger),
+		files.New(logger),
+		env.New(env.Settings{}),
+	}
+	handleDeprecatedKey := func(source, deprecatedKey, currentKey string) {
+		logger.Warn("You are using the old " + source + " " + deprecatedKey +
+			", please consider changing it to " + currentKey)
+	}
+	newPrefixedReader := func(prefix string) *reader.Reader {
+		return reader.New(reader.Settings{
+			Sources:             prefixed.Wrap(prefix, sources),
+			HandleDeprecatedKey: handleDeprecatedKey,
+		})
+	}
 	reader := reader.New(reader.Settings{
-		Sources: []reader.Source{
-			secrets.New(logger),
-			files.New(logger),
-			env.New(env.Settings{}),
-		},
-		HandleDeprecatedKey: func(source, deprecatedKey, currentKey string) {
-			logger.Warn("You are using the old " + source + " " + deprecatedKey +
-				", please consider changing it to " + currentKey)
-		},
+		Sources:             sources,
+		HandleDeprecatedKey: handleDeprecatedKey,
 	})
 
 	errorCh := make(chan error)
 	go func() {
-		errorCh <- _main(ctx, buildInfo, args, logger, reader, tun, netLinker, cmder, cli)
+		errorCh <- _main(ctx, buildInfo, args, logger, reader, newPrefixedReader,
+			tun, netLinker, cmder, cli)
 	}()
 
 	var err error
@@ -146,6 +160,7 @@ var (
 //nolint:gocognit,gocyclo,maintidx
 func _main(ctx context.Context, buildInfo models.BuildInformation,
 	args []string, logger log.LoggerInterface, reader *reader.Reader,
+	newPrefixedReader func(prefix string) *reader.Reader,
 	tun Tun, netLinker netLinker, cmder RunStarter,
 	cli clier) error {
 	if len(args) > 1 { // cli operation
@@ -191,6 +206,17 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	if err != nil {
 		return err
 	}
+	err = allSettings.VPN.Fallback.ReadVPN(newPrefixedReader("FALLBACK_"))
+	if err != nil {
+		return fmt.Errorf("reading fallback VPN settings: %w", err)
+	}
+	for i := range allSettings.VPN.Tunnels {
+		vpnTunnel := &allSettings.VPN.Tunnels[i]
+		err = vpnTunnel.ReadVPN(newPrefixedReader(vpnTunnel.ReaderPrefix()))
+		if err != nil {
+			return fmt.Errorf("reading VPN tunnel %s settings: %w", vpnTunnel.Name, err)
+		}
+	}
 	allSettings.SetDefaults()
 
 	// Note: no need to validate minimal settings for the firewall:
@@ -375,17 +401,37 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 		<-pprofReady
 	}
 
+	eventsBroker := events.New()
+
+	metricsLogger := logger.New(log.SetComponent("metrics"))
+	prometheusMetrics, err := metrics.New(metricsLogger)
+	if err != nil {
+		return fmt.Errorf("creating metrics: %w", err)
+	}
+	metricsHandler, metricsCtx, metricsDone := goshutdown.NewGoRoutineHandler(
+		"metrics", goroutine.OptionTimeout(defaultShutdownTimeout))
+	metricsEvents, unsubscribeMetrics := eventsBroker.Subscribe()
+	go prometheusMetrics.Run(metricsCtx, metricsDone, metricsEvents, unsubscribeMetrics)
+	otherGroupHandler.Add(metricsHandler)
+
 	portForwardLogger := logger.New(log.SetComponent("port forwarding"))
 	portForwardLooper := portforward.NewLoop(allSettings.VPN.Provider.PortForwarding,
-		routingConf, httpClient, firewallConf, portForwardLogger, puid, pgid)
+		routingConf, httpClient, firewallConf, eventsBroker, portForwardLogger, puid, pgid)
 	portForwardRunError, err := portForwardLooper.Start(ctx)
 	if err != nil {
 		return fmt.Errorf("starting port forwarding loop: %w", err)
 	}
 
+	splitTunnel := splittunnel.New(allSettings.Firewall.OutboundDomains,
+		routingConf, firewallConf, logger.New(log.SetComponent("split tunnel")))
+	splitTunnelHandler, splitTunnelCtx, splitTunnelDone := goshutdown.NewGoRoutineHandler(
+		"split tunnel", goroutine.OptionTimeout(defaultShutdownTimeout))
+	go splitTunnel.Run(splitTunnelCtx, splitTunnelDone)
+	otherGroupHandler.Add(splitTunnelHandler)
+
 	dnsLogger := logger.New(log.SetComponent("dns"))
 	dnsLooper, err := dns.NewLoop(allSettings.DNS, httpClient,
-		dnsLogger)
+		prometheusMetrics.DNSFilter, splitTunnel, eventsBroker, dnsLogger)
 	if err != nil {
 		return fmt.Errorf("creating DNS loop: %w", err)
 	}
@@ -406,7 +452,7 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	if err != nil {
 		return fmt.Errorf("creating public IP API client: %w", err)
 	}
-	publicIPLooper := publicip.NewLoop(ipFetcher,
+	publicIPLooper := publicip.NewLoop(ipFetcher, eventsBroker,
 		logger.New(log.SetComponent("ip getter")),
 		allSettings.PublicIP, puid, pgid)
 	publicIPRunError, err := publicIPLooper.Start(ctx)
@@ -425,14 +471,30 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	vpnLogger := logger.New(log.SetComponent("vpn"))
 	vpnLooper := vpn.NewLoop(allSettings.VPN, ipv6Supported, allSettings.Firewall.VPNInputPorts,
 		providers, storage, ovpnConf, netLinker, firewallConf, routingConf, portForwardLooper,
-		cmder, publicIPLooper, dnsLooper, vpnLogger, httpClient,
+		cmder, publicIPLooper, dnsLooper, eventsBroker, vpnLogger, httpClient,
 		buildInfo, *allSettings.Version.Enabled)
 	vpnHandler, vpnCtx, vpnDone := goshutdown.NewGoRoutineHandler(
 		"vpn", goroutine.OptionTimeout(time.Second))
 	go vpnLooper.Run(vpnCtx, vpnDone)
 
+	tunnelsGroupHandler := goshutdown.NewGroupHandler("tunnels", defaultGroupOptions...)
+	for i, tunnelSettings := range allSettings.VPN.Tunnels {
+		tunnelLogger := logger.New(log.SetComponent("tunnel " + tunnelSettings.Name))
+		vpnTunnel := tunnel.New(tunnelSettings, i, ipv6Supported, providers,
+			netLinker, firewallConf, routingConf, tunnelLogger)
+		tunnelHandler, tunnelCtx, tunnelDone := goshutdown.NewGoRoutineHandler(
+			"tunnel "+tunnelSettings.Name, goroutine.OptionTimeout(time.Second))
+		go vpnTunnel.Run(tunnelCtx, tunnelDone)
+		tunnelsGroupHandler.Add(tunnelHandler)
+	}
+
+	err = prometheusMetrics.RegisterTunnelCollector(vpnLooper)
+	if err != nil {
+		return fmt.Errorf("registering tunnel metrics: %w", err)
+	}
+
 	updaterLooper := updater.NewLoop(allSettings.Updater,
-		providers, storage, httpClient, updaterLogger)
+		providers, storage, httpClient, eventsBroker, updaterLogger)
 	updaterHandler, updaterCtx, updaterDone := goshutdown.NewGoRoutineHandler(
 		"updater", goroutine.OptionTimeout(defaultShutdownTimeout))
 	// wait for updaterLooper.Restart() or its ticket launched with RunRestartTicker
@@ -444,9 +506,14 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	go updaterLooper.RunRestartTicker(updaterTickerCtx, updaterTickerDone)
 	controlGroupHandler.Add(updaterTickerHandler)
 
+	updaterLoadsHandler, updaterLoadsCtx, updaterLoadsDone := goshutdown.NewGoRoutineHandler(
+		"updater loads ticker", goroutine.OptionTimeout(defaultShutdownTimeout))
+	go updaterLooper.RunLoadTicker(updaterLoadsCtx, updaterLoadsDone)
+	controlGroupHandler.Add(updaterLoadsHandler)
+
 	httpProxyLooper := httpproxy.NewLoop(
 		logger.New(log.SetComponent("http proxy")),
-		allSettings.HTTPProxy)
+		allSettings.HTTPProxy, tunnel.HTTPProxyUsers(allSettings.VPN.Tunnels))
 	httpProxyHandler, httpProxyCtx, httpProxyDone := goshutdown.NewGoRoutineHandler(
 		"http proxy", goroutine.OptionTimeout(defaultShutdownTimeout))
 	go httpProxyLooper.Run(httpProxyCtx, httpProxyDone)
@@ -459,14 +526,23 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	go shadowsocksLooper.Run(shadowsocksCtx, shadowsocksDone)
 	otherGroupHandler.Add(shadowsocksHandler)
 
+	healthLogger := logger.New(log.SetComponent("healthcheck"))
+	var healthFirewall healthcheck.Firewall // nil if the firewall is disabled
+	if *allSettings.Firewall.Enabled {
+		healthFirewall = firewallConf
+	}
+	healthcheckServer := healthcheck.NewServer(allSettings.Health, healthLogger,
+		vpnLooper, dnsLooper, healthFirewall, portForwardLooper, prometheusMetrics, eventsBroker)
+
 	controlServerAddress := *allSettings.ControlServer.Address
 	controlServerLogging := *allSettings.ControlServer.Log
 	httpServerHandler, httpServerCtx, httpServerDone := goshutdown.NewGoRoutineHandler(
 		"http server", goroutine.OptionTimeout(defaultShutdownTimeout))
 	httpServer, err := server.New(httpServerCtx, controlServerAddress, controlServerLogging,
 		logger.New(log.SetComponent("http server")),
-		allSettings.ControlServer.Auth,
+		allSettings,
 		buildInfo, vpnLooper, portForwardLooper, dnsLooper, updaterLooper, publicIPLooper,
+		httpProxyLooper, shadowsocksLooper, healthcheckServer, eventsBroker, firewallConf, routingConf,
 		storage, ipv6Supported)
 	if err != nil {
 		return fmt.Errorf("setting up control server: %w", err)
@@ -476,8 +552,19 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	<-httpServerReady
 	controlGroupHandler.Add(httpServerHandler)
 
-	healthLogger := logger.New(log.SetComponent("healthcheck"))
-	healthcheckServer := healthcheck.NewServer(allSettings.Health, healthLogger, vpnLooper)
+	if *allSettings.Metrics.Enabled {
+		metricsServer, err := prometheusMetrics.NewServer(allSettings.Metrics.Address, metricsLogger)
+		if err != nil {
+			return fmt.Errorf("setting up metrics server: %w", err)
+		}
+		metricsServerHandler, metricsServerCtx, metricsServerDone := goshutdown.NewGoRoutineHandler(
+			"metrics server", goroutine.OptionTimeout(defaultShutdownTimeout))
+		metricsServerReady := make(chan struct{})
+		go metricsServer.Run(metricsServerCtx, metricsServerReady, metricsServerDone)
+		<-metricsServerReady
+		controlGroupHandler.Add(metricsServerHandler)
+	}
+
 	healthServerHandler, healthServerCtx, healthServerDone := goshutdown.NewGoRoutineHandler(
 		"HTTP health server", goroutine.OptionTimeout(defaultShutdownTimeout))
 	go healthcheckServer.Run(healthServerCtx, healthServerDone)
@@ -487,7 +574,7 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 		order.OptionOnSuccess(defaultShutdownOnSuccess),
 		order.OptionOnFailure(defaultShutdownOnFailure))
 	orderHandler.Append(controlGroupHandler, tickersGroupHandler, healthServerHandler,
-		vpnHandler, otherGroupHandler)
+		vpnHandler, tunnelsGroupHandler, otherGroupHandler)
 
 	// Start VPN for the first time in a blocking call
 	// until the VPN is launched
@@ -16,7 +16,7 @@ type DNS struct {
 	// It defaults to '127.0.0.1' to be used with the
 	// DoT server. It cannot be the zero value in the internal
 	// state.
-	ServerAddress netip.Addr
+	ServerAddress netip.Addr `json:"server_address"`
 	// KeepNameserver is true if the existing DNS server
 	// found in /etc/resolv.conf should be used
 	// Note setting this to true will likely DNS traffic
@@ -27,10 +27,10 @@ type DNS struct {
 	// `ServerAddress` field will be ignored.
 	// It defaults to false and cannot be nil in the
 	// internal state.
-	KeepNameserver *bool
+	KeepNameserver *bool `json:"keep_nameserver"`
 	// DOT contains settings to configure the DoT
 	// server.
-	DoT DoT
+	DoT DoT `json:"dot"`
 }
 
 func (d DNS) validate() (err error) {
@@ -15,13 +15,13 @@ import (
 
 // DNSBlacklist is settings for the DNS blacklist building.
 type DNSBlacklist struct {
-	BlockMalicious       *bool
-	BlockAds             *bool
-	BlockSurveillance    *bool
-	AllowedHosts         []string
-	AddBlockedHosts      []string
-	AddBlockedIPs        []netip.Addr
-	AddBlockedIPPrefixes []netip.Prefix
+	BlockMalicious       *bool          `json:"block_malicious"`
+	BlockAds             *bool          `json:"block_ads"`
+	BlockSurveillance    *bool          `json:"block_surveillance"`
+	AllowedHosts         []string       `json:"allowed_hosts"`
+	AddBlockedHosts      []string       `json:"add_blocked_hosts"`
+	AddBlockedIPs        []netip.Addr   `json:"add_blocked_ips"`
+	AddBlockedIPPrefixes []netip.Prefix `json:"add_blocked_ip_prefixes"`
 }
 
 func (b *DNSBlacklist) setDefaults() {
@@ -0,0 +1,48 @@
+package settings
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/constants"
+)
+
+// DNSUpstream is an upstream DNS provider with the protocol
+// to use to reach it.
+type DNSUpstream struct {
+	// Protocol is the protocol to reach the provider with,
+	// and can be "dot" or "doh".
+	Protocol string
+	// Provider is the DNS provider name.
+	Provider string
+}
+
+func (d DNSUpstream) String() string {
+	return d.Protocol + ":" + d.Provider
+}
+
+// parseDNSUpstream parses a DNS provider string in the format
+// [protocol:]provider, where the protocol is "dot" or "doh"
+// and defaults to "dot" if omitted.
+func parseDNSUpstream(s string) (upstream DNSUpstream, err error) {
+	protocol, provider, found := strings.Cut(s, ":")
+	if !found {
+		return DNSUpstream{
+			Protocol: constants.DNSOverTLS,
+			Provider: s,
+		}, nil
+	}
+
+	protocol = strings.ToLower(protocol)
+	switch protocol {
+	case constants.DNSOverTLS, constants.DNSOverHTTPS:
+	default:
+		return DNSUpstream{}, fmt.Errorf("%w: %q for %q",
+			ErrDNSUpstreamProtocolNotValid, protocol, s)
+	}
+
+	return DNSUpstream{
+		Protocol: protocol,
+		Provider: provider,
+	}, nil
+}
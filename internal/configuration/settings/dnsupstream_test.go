@@ -0,0 +1,51 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseDNSUpstream(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		s          string
+		upstream   DNSUpstream
+		errWrapped error
+		errMessage string
+	}{
+		"default_protocol": {
+			s:        "cloudflare",
+			upstream: DNSUpstream{Protocol: "dot", Provider: "cloudflare"},
+		},
+		"dot": {
+			s:        "dot:quad9",
+			upstream: DNSUpstream{Protocol: "dot", Provider: "quad9"},
+		},
+		"doh_uppercase": {
+			s:        "DoH:google",
+			upstream: DNSUpstream{Protocol: "doh", Provider: "google"},
+		},
+		"protocol_not_valid": {
+			s:          "dns:cloudflare",
+			errWrapped: ErrDNSUpstreamProtocolNotValid,
+			errMessage: `DNS upstream protocol is not valid: "dns" for "dns:cloudflare"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			upstream, err := parseDNSUpstream(testCase.s)
+
+			assert.Equal(t, testCase.upstream, upstream)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/qdm12/dns/v2/pkg/provider"
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gosettings"
 	"github.com/qdm12/gosettings/reader"
 	"github.com/qdm12/gotree"
@@ -17,13 +18,17 @@ type DoT struct {
 	// Enabled is true if the DoT server should be running
 	// and used. It defaults to true, and cannot be nil
 	// in the internal state.
-	Enabled *bool
+	Enabled *bool `json:"enabled"`
 	// UpdatePeriod is the period to update DNS block lists.
 	// It can be set to 0 to disable the update.
 	// It defaults to 24h and cannot be nil in
 	// the internal state.
-	UpdatePeriod *time.Duration
-	// Providers is a list of DNS over TLS providers
+	UpdatePeriod *time.Duration `json:"update_period"`
+	// Providers is a list of DNS providers, each in the format
+	// [protocol:]provider where the protocol is "dot" for DNS over TLS
+	// or "doh" for DNS over HTTPS, and defaults to "dot" if omitted.
+	// If both protocols are used, the DNS server fails over from
+	// one protocol to the other, in the order of the list.
 	Providers []string `json:"providers"`
 	// Caching is true if the DoT server should cache
 	// DNS responses.
@@ -32,7 +37,7 @@ type DoT struct {
 	IPv6 *bool `json:"ipv6"`
 	// Blacklist contains settings to configure the filter
 	// block lists.
-	Blacklist DNSBlacklist
+	Blacklist DNSBlacklist `json:"blacklist"`
 }
 
 var (
@@ -47,11 +52,23 @@ func (d DoT) validate() (err error) {
 	}
 
 	providers := provider.NewProviders()
-	for _, providerName := range d.Providers {
-		_, err := providers.Get(providerName)
+	for _, s := range d.Providers {
+		upstream, err := parseDNSUpstream(s)
 		if err != nil {
 			return err
 		}
+
+		provider, err := providers.Get(upstream.Provider)
+		if err != nil {
+			return err
+		}
+
+		if upstream.Protocol == constants.DNSOverHTTPS {
+			err = provider.ValidateForDoH(*d.IPv6)
+			if err != nil {
+				return fmt.Errorf("DNS over HTTPS provider %s: %w", provider.Name, err)
+			}
+		}
 	}
 
 	err = d.Blacklist.validate()
@@ -97,9 +114,25 @@ func (d *DoT) setDefaults() {
 	d.Blacklist.setDefaults()
 }
 
+// GetUpstreams returns the DNS upstreams parsed from the providers.
+// Settings must be validated before calling this method.
+func (d DoT) GetUpstreams() (upstreams []DNSUpstream) {
+	upstreams = make([]DNSUpstream, len(d.Providers))
+	for i, s := range d.Providers {
+		upstream, err := parseDNSUpstream(s)
+		if err != nil {
+			// Settings should be validated before calling this function,
+			// so an error happening here is a programming error.
+			panic(err)
+		}
+		upstreams[i] = upstream
+	}
+	return upstreams
+}
+
 func (d DoT) GetFirstPlaintextIPv4() (ipv4 netip.Addr) {
 	providers := provider.NewProviders()
-	provider, err := providers.Get(d.Providers[0])
+	provider, err := providers.Get(d.GetUpstreams()[0].Provider)
 	if err != nil {
 		// Settings should be validated before calling this function,
 		// so an error happening here is a programming error.
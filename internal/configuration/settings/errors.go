@@ -6,13 +6,26 @@ var (
 	ErrValueUnknown                    = errors.New("value is unknown")
 	ErrCityNotValid                    = errors.New("the city specified is not valid")
 	ErrControlServerPrivilegedPort     = errors.New("cannot use privileged port without running as root")
+	ErrControlServerMTLSNoClientCA     = errors.New("mtls authentication requires a client certificate authority")
 	ErrCategoryNotValid                = errors.New("the category specified is not valid")
 	ErrCountryNotValid                 = errors.New("the country specified is not valid")
+	ErrDNSUpstreamProtocolNotValid     = errors.New("DNS upstream protocol is not valid")
+	ErrFailoverCooldownNotPositive     = errors.New("VPN failover cooldown is not positive")
+	ErrFallbackFailuresZero            = errors.New("VPN fallback failures count cannot be zero")
+	ErrFallbackSwitchBackTooSmall      = errors.New("VPN fallback switch back duration is too small")
 	ErrFilepathMissing                 = errors.New("filepath is missing")
 	ErrFirewallZeroPort                = errors.New("cannot have a zero port")
 	ErrFirewallPublicOutboundSubnet    = errors.New("outbound subnet has an unspecified address")
+	ErrFirewallOutboundDomainNotValid  = errors.New("outbound domain is not valid")
+	ErrHealthProbeFormatNotValid       = errors.New("health probe format is not valid")
+	ErrHealthProbeHTTPStatusNotValid   = errors.New("health probe HTTP status code is not valid")
+	ErrHealthProbeTargetNotValid       = errors.New("health probe target is not valid")
+	ErrHealthProbeTimeoutNegative      = errors.New("health probe timeout is negative")
+	ErrHealthProbeTypeNotValid         = errors.New("health probe type is not valid")
+	ErrHealthProbesQuorumNotValid      = errors.New("health probes quorum is not valid")
 	ErrHostnameNotValid                = errors.New("the hostname specified is not valid")
 	ErrISPNotValid                     = errors.New("the ISP specified is not valid")
+	ErrLatencyCandidatesZero           = errors.New("number of latency candidates cannot be zero")
 	ErrMinRatioNotValid                = errors.New("minimum ratio is not valid")
 	ErrMissingValue                    = errors.New("missing value")
 	ErrNameNotValid                    = errors.New("the server name specified is not valid")
@@ -31,13 +44,25 @@ var (
 	ErrPortForwardingUserEmpty         = errors.New("port forwarding username is empty")
 	ErrPortForwardingPasswordEmpty     = errors.New("port forwarding password is empty")
 	ErrPublicIPPeriodTooShort          = errors.New("public IP address check period is too short")
+	ErrRotationPeriodTooSmall          = errors.New("VPN rotation period is too small")
+	ErrRotationPeriodAndCronSet        = errors.New("VPN rotation period and cron expression are both set")
 	ErrRegionNotValid                  = errors.New("the region specified is not valid")
+	ErrSelectionLatencyOpenVPNUDP      = errors.New("latency selection strategy is not supported for OpenVPN over UDP")
+	ErrSelectionStrategyNotValid       = errors.New("server selection strategy is not valid")
 	ErrServerAddressNotValid           = errors.New("server listening address is not valid")
 	ErrSystemPGIDNotValid              = errors.New("process group id is not valid")
 	ErrSystemPUIDNotValid              = errors.New("process user id is not valid")
 	ErrSystemTimezoneNotValid          = errors.New("timezone is not valid")
+	ErrUnixSocketModeNotValid          = errors.New("unix socket file mode is not valid")
+	ErrUnixSocketPathNotAbsolute       = errors.New("unix socket path is not absolute")
 	ErrUpdaterPeriodTooSmall           = errors.New("VPN server data updater period is too small")
+	ErrUpdaterLoadPeriodTooSmall       = errors.New("VPN server load updater period is too small")
 	ErrVPNProviderNameNotValid         = errors.New("VPN provider name is not valid")
+	ErrVPNTunnelHTTPProxyUserDuplicate = errors.New("VPN tunnel HTTP proxy user is duplicated")
+	ErrVPNTunnelHTTPProxyUserMain      = errors.New("VPN tunnel HTTP proxy user is the main HTTP proxy user")
+	ErrVPNTunnelInterfaceDuplicate     = errors.New("VPN tunnel interface is already used")
+	ErrVPNTunnelNameDuplicate          = errors.New("VPN tunnel name is duplicated")
+	ErrVPNTunnelNameNotValid           = errors.New("VPN tunnel name is not valid")
 	ErrVPNTypeNotValid                 = errors.New("VPN type is not valid")
 	ErrWireguardAllowedIPNotSet        = errors.New("allowed IP is not set")
 	ErrWireguardAllowedIPsNotSet       = errors.New("allowed IPs is not set")
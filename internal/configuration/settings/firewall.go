@@ -3,6 +3,7 @@ package settings
 import (
 	"fmt"
 	"net/netip"
+	"strings"
 
 	"github.com/qdm12/gosettings"
 	"github.com/qdm12/gosettings/reader"
@@ -11,11 +12,17 @@ import (
 
 // Firewall contains settings to customize the firewall operation.
 type Firewall struct {
-	VPNInputPorts   []uint16
-	InputPorts      []uint16
-	OutboundSubnets []netip.Prefix
-	Enabled         *bool
-	Debug           *bool
+	VPNInputPorts   []uint16       `json:"vpn_input_ports"`
+	InputPorts      []uint16       `json:"input_ports"`
+	OutboundSubnets []netip.Prefix `json:"outbound_subnets"`
+	// OutboundDomains are domain names whose traffic goes out
+	// through the default route instead of the VPN. A domain can
+	// start with "*." to match all its subdomains. The IP addresses
+	// are learned from the answers of the DNS server, and are
+	// allowed until their DNS record TTL expires.
+	OutboundDomains []string `json:"outbound_domains"`
+	Enabled         *bool    `json:"enabled"`
+	Debug           *bool    `json:"debug"`
 }
 
 func (f Firewall) validate() (err error) {
@@ -33,6 +40,13 @@ func (f Firewall) validate() (err error) {
 		}
 	}
 
+	for _, domain := range f.OutboundDomains {
+		host := strings.TrimPrefix(domain, "*.")
+		if !hostRegex.MatchString(host) {
+			return fmt.Errorf("%w: %s", ErrFirewallOutboundDomainNotValid, domain)
+		}
+	}
+
 	return nil
 }
 
@@ -50,6 +64,7 @@ func (f *Firewall) copy() (copied Firewall) {
 		VPNInputPorts:   gosettings.CopySlice(f.VPNInputPorts),
 		InputPorts:      gosettings.CopySlice(f.InputPorts),
 		OutboundSubnets: gosettings.CopySlice(f.OutboundSubnets),
+		OutboundDomains: gosettings.CopySlice(f.OutboundDomains),
 		Enabled:         gosettings.CopyPointer(f.Enabled),
 		Debug:           gosettings.CopyPointer(f.Debug),
 	}
@@ -62,6 +77,7 @@ func (f *Firewall) overrideWith(other Firewall) {
 	f.VPNInputPorts = gosettings.OverrideWithSlice(f.VPNInputPorts, other.VPNInputPorts)
 	f.InputPorts = gosettings.OverrideWithSlice(f.InputPorts, other.InputPorts)
 	f.OutboundSubnets = gosettings.OverrideWithSlice(f.OutboundSubnets, other.OutboundSubnets)
+	f.OutboundDomains = gosettings.OverrideWithSlice(f.OutboundDomains, other.OutboundDomains)
 	f.Enabled = gosettings.OverrideWithPointer(f.Enabled, other.Enabled)
 	f.Debug = gosettings.OverrideWithPointer(f.Debug, other.Debug)
 }
@@ -109,6 +125,13 @@ func (f Firewall) toLinesNode() (node *gotree.Node) {
 		}
 	}
 
+	if len(f.OutboundDomains) > 0 {
+		outboundDomains := node.Appendf("Outbound domains:")
+		for _, domain := range f.OutboundDomains {
+			outboundDomains.Appendf("%s", domain)
+		}
+	}
+
 	return node
 }
 
@@ -129,6 +152,8 @@ func (f *Firewall) read(r *reader.Reader) (err error) {
 		return err
 	}
 
+	f.OutboundDomains = r.CSV("FIREWALL_OUTBOUND_DOMAINS")
+
 	f.Enabled, err = r.BoolPtr("FIREWALL_ENABLED_DISABLING_IT_SHOOTS_YOU_IN_YOUR_FOOT")
 	if err != nil {
 		return err
@@ -46,6 +46,13 @@ func Test_Firewall_validate(t *testing.T) {
 				},
 			},
 		},
+		"invalid_outbound_domain": {
+			firewall: Firewall{
+				OutboundDomains: []string{"*.example.com", "exa mple.com"},
+			},
+			errWrapped: ErrFirewallOutboundDomainNotValid,
+			errMessage: "outbound domain is not valid: exa mple.com",
+		},
 		"valid_settings": {
 			firewall: Firewall{
 				VPNInputPorts: []uint16{100, 101},
@@ -54,6 +61,7 @@ func Test_Firewall_validate(t *testing.T) {
 					netip.MustParsePrefix("192.168.1.0/24"),
 					netip.MustParsePrefix("10.10.1.1/32"),
 				},
+				OutboundDomains: []string{"*.example.com", "mirror.example.org"},
 			},
 		},
 	}
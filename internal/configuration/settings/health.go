@@ -5,6 +5,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gosettings"
 	"github.com/qdm12/gosettings/reader"
 	"github.com/qdm12/gosettings/validate"
@@ -16,24 +17,49 @@ type Health struct {
 	// ServerAddress is the listening address
 	// for the health check server.
 	// It cannot be the empty string in the internal state.
-	ServerAddress string
+	ServerAddress string `json:"server_address"`
 	// ReadHeaderTimeout is the HTTP server header read timeout
 	// duration of the HTTP server. It defaults to 100 milliseconds.
-	ReadHeaderTimeout time.Duration
+	ReadHeaderTimeout time.Duration `json:"read_header_timeout"`
 	// ReadTimeout is the HTTP read timeout duration of the
 	// HTTP server. It defaults to 500 milliseconds.
-	ReadTimeout time.Duration
+	ReadTimeout time.Duration `json:"read_timeout"`
 	// TargetAddress is the address (host or host:port)
-	// to TCP dial to periodically for the health check.
+	// to TCP dial to periodically for the health check,
+	// used as the only probe if Probes is not set.
 	// It cannot be the empty string in the internal state.
-	TargetAddress string
+	TargetAddress string `json:"target_address"`
+	// Probes are the probes to run for each health check.
+	// It defaults to a single TCP probe to TargetAddress,
+	// and cannot be empty in the internal state.
+	Probes []HealthProbe `json:"probes"`
+	// Quorum is the number of probes which must succeed
+	// for the health check to succeed. It defaults to the
+	// majority of the probes, cannot be nil in the internal
+	// state and must be between 1 and the number of probes.
+	Quorum *uint `json:"quorum"`
 	// SuccessWait is the duration to wait to re-run the
 	// healthcheck after a successful healthcheck.
 	// It defaults to 5 seconds and cannot be zero in
 	// the internal state.
-	SuccessWait time.Duration
+	SuccessWait time.Duration `json:"success_wait"`
+	// FlappingWindow is the time window in which healthy and
+	// unhealthy transitions are counted to detect flapping.
+	// It defaults to 10 minutes and cannot be zero in the
+	// internal state.
+	FlappingWindow time.Duration `json:"flapping_window"`
+	// FlappingTransitions is the number of healthy and unhealthy
+	// transitions within FlappingWindow from which the health is
+	// considered flapping, in which case VPN restarts are held off.
+	// It defaults to 6 and cannot be nil in the internal state.
+	// It is set to 0 to disable the flapping detection.
+	FlappingTransitions *uint `json:"flapping_transitions"`
+	// ReadyPortForwarded is true if a port must be forwarded
+	// for the program to be ready on the /readyz endpoint.
+	// It cannot be nil in the internal state.
+	ReadyPortForwarded *bool `json:"ready_port_forwarded"`
 	// VPN has health settings specific to the VPN loop.
-	VPN HealthyWait
+	VPN HealthyWait `json:"vpn"`
 }
 
 func (h Health) Validate() (err error) {
@@ -42,6 +68,18 @@ func (h Health) Validate() (err error) {
 		return fmt.Errorf("server listening address is not valid: %w", err)
 	}
 
+	for i, probe := range h.Probes {
+		err = probe.validate()
+		if err != nil {
+			return fmt.Errorf("health probe %d of %d: %w", i+1, len(h.Probes), err)
+		}
+	}
+
+	if *h.Quorum == 0 || *h.Quorum > uint(len(h.Probes)) {
+		return fmt.Errorf("%w: %d must be between 1 and the number of probes %d",
+			ErrHealthProbesQuorumNotValid, *h.Quorum, len(h.Probes))
+	}
+
 	err = h.VPN.validate()
 	if err != nil {
 		return fmt.Errorf("health VPN settings: %w", err)
@@ -52,12 +90,17 @@ func (h Health) Validate() (err error) {
 
 func (h *Health) copy() (copied Health) {
 	return Health{
-		ServerAddress:     h.ServerAddress,
-		ReadHeaderTimeout: h.ReadHeaderTimeout,
-		ReadTimeout:       h.ReadTimeout,
-		TargetAddress:     h.TargetAddress,
-		SuccessWait:       h.SuccessWait,
-		VPN:               h.VPN.copy(),
+		ServerAddress:       h.ServerAddress,
+		ReadHeaderTimeout:   h.ReadHeaderTimeout,
+		ReadTimeout:         h.ReadTimeout,
+		TargetAddress:       h.TargetAddress,
+		Probes:              gosettings.CopySlice(h.Probes),
+		Quorum:              gosettings.CopyPointer(h.Quorum),
+		SuccessWait:         h.SuccessWait,
+		FlappingWindow:      h.FlappingWindow,
+		FlappingTransitions: gosettings.CopyPointer(h.FlappingTransitions),
+		ReadyPortForwarded:  gosettings.CopyPointer(h.ReadyPortForwarded),
+		VPN:                 h.VPN.copy(),
 	}
 }
 
@@ -69,7 +112,12 @@ func (h *Health) OverrideWith(other Health) {
 	h.ReadHeaderTimeout = gosettings.OverrideWithComparable(h.ReadHeaderTimeout, other.ReadHeaderTimeout)
 	h.ReadTimeout = gosettings.OverrideWithComparable(h.ReadTimeout, other.ReadTimeout)
 	h.TargetAddress = gosettings.OverrideWithComparable(h.TargetAddress, other.TargetAddress)
+	h.Probes = gosettings.OverrideWithSlice(h.Probes, other.Probes)
+	h.Quorum = gosettings.OverrideWithPointer(h.Quorum, other.Quorum)
 	h.SuccessWait = gosettings.OverrideWithComparable(h.SuccessWait, other.SuccessWait)
+	h.FlappingWindow = gosettings.OverrideWithComparable(h.FlappingWindow, other.FlappingWindow)
+	h.FlappingTransitions = gosettings.OverrideWithPointer(h.FlappingTransitions, other.FlappingTransitions)
+	h.ReadyPortForwarded = gosettings.OverrideWithPointer(h.ReadyPortForwarded, other.ReadyPortForwarded)
 	h.VPN.overrideWith(other.VPN)
 }
 
@@ -80,8 +128,22 @@ func (h *Health) SetDefaults() {
 	const defaultReadTimeout = 500 * time.Millisecond
 	h.ReadTimeout = gosettings.DefaultComparable(h.ReadTimeout, defaultReadTimeout)
 	h.TargetAddress = gosettings.DefaultComparable(h.TargetAddress, "cloudflare.com:443")
+	h.Probes = gosettings.DefaultSlice(h.Probes, []HealthProbe{{
+		Type:   constants.HealthProbeTCP,
+		Target: h.TargetAddress,
+	}})
+	for i := range h.Probes {
+		h.Probes[i].setDefaults()
+	}
+	majority := uint(len(h.Probes)/2 + 1)
+	h.Quorum = gosettings.DefaultPointer(h.Quorum, majority)
 	const defaultSuccessWait = 5 * time.Second
 	h.SuccessWait = gosettings.DefaultComparable(h.SuccessWait, defaultSuccessWait)
+	const defaultFlappingWindow = 10 * time.Minute
+	h.FlappingWindow = gosettings.DefaultComparable(h.FlappingWindow, defaultFlappingWindow)
+	const defaultFlappingTransitions = 6
+	h.FlappingTransitions = gosettings.DefaultPointer(h.FlappingTransitions, defaultFlappingTransitions)
+	h.ReadyPortForwarded = gosettings.DefaultPointer(h.ReadyPortForwarded, false)
 	h.VPN.setDefaults()
 }
 
@@ -92,10 +154,20 @@ func (h Health) String() string {
 func (h Health) toLinesNode() (node *gotree.Node) {
 	node = gotree.New("Health settings:")
 	node.Appendf("Server listening address: %s", h.ServerAddress)
-	node.Appendf("Target address: %s", h.TargetAddress)
+	probesNode := node.Appendf("Probes (%d of %d must succeed):", *h.Quorum, len(h.Probes))
+	for _, probe := range h.Probes {
+		probesNode.Appendf("%s", probe)
+	}
 	node.Appendf("Duration to wait after success: %s", h.SuccessWait)
 	node.Appendf("Read header timeout: %s", h.ReadHeaderTimeout)
 	node.Appendf("Read timeout: %s", h.ReadTimeout)
+	if *h.FlappingTransitions == 0 {
+		node.Appendf("Flapping detection: disabled")
+	} else {
+		node.Appendf("Flapping detection: %d transitions in %s",
+			*h.FlappingTransitions, h.FlappingWindow)
+	}
+	node.Appendf("Ready only with a forwarded port: %s", gosettings.BoolToYesNo(h.ReadyPortForwarded))
 	node.AppendNode(h.VPN.toLinesNode("VPN"))
 	return node
 }
@@ -105,11 +177,42 @@ func (h *Health) Read(r *reader.Reader) (err error) {
 	h.TargetAddress = r.String("HEALTH_TARGET_ADDRESS",
 		reader.RetroKeys("HEALTH_ADDRESS_TO_PING"))
 
+	probes := r.CSV("HEALTH_PROBES", reader.ForceLowercase(false))
+	if len(probes) > 0 {
+		h.Probes = make([]HealthProbe, len(probes))
+		for i, probe := range probes {
+			h.Probes[i], err = parseHealthProbe(probe)
+			if err != nil {
+				return fmt.Errorf("health probe %d of %d: %w", i+1, len(probes), err)
+			}
+		}
+	}
+
+	h.Quorum, err = r.UintPtr("HEALTH_PROBES_QUORUM")
+	if err != nil {
+		return err
+	}
+
 	h.SuccessWait, err = r.Duration("HEALTH_SUCCESS_WAIT_DURATION")
 	if err != nil {
 		return err
 	}
 
+	h.FlappingWindow, err = r.Duration("HEALTH_FLAPPING_WINDOW")
+	if err != nil {
+		return err
+	}
+
+	h.FlappingTransitions, err = r.UintPtr("HEALTH_FLAPPING_TRANSITIONS")
+	if err != nil {
+		return err
+	}
+
+	h.ReadyPortForwarded, err = r.BoolPtr("HEALTH_READY_PORT_FORWARDED")
+	if err != nil {
+		return err
+	}
+
 	err = h.VPN.read(r)
 	if err != nil {
 		return fmt.Errorf("VPN health settings: %w", err)
@@ -0,0 +1,142 @@
+package settings
+
+import (
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gosettings/validate"
+)
+
+// HealthProbe contains settings for a single health probe.
+type HealthProbe struct {
+	// Type is the probe type, which can be 'tcp', 'http',
+	// 'dns' or 'icmp'. It cannot be the empty string.
+	Type string `json:"type"`
+	// Target is the probe target, which is a host or host:port
+	// for the tcp type, an http or https URL for the http type,
+	// a hostname for the dns type and an IP address for the
+	// icmp type. It cannot be the empty string.
+	Target string `json:"target"`
+	// Timeout is the timeout duration for the probe. The zero
+	// value means only the health check timeout applies.
+	Timeout time.Duration `json:"timeout"`
+	// Status is the expected HTTP response status code for
+	// the http type. It defaults to 200 for the http type.
+	Status int `json:"status"`
+	// Body is a string the HTTP response body must contain
+	// for the http type. The empty string disables the check.
+	Body string `json:"body"`
+}
+
+func (p HealthProbe) validate() (err error) {
+	err = validate.IsOneOf(p.Type, constants.HealthProbeTCP,
+		constants.HealthProbeHTTP, constants.HealthProbeDNS,
+		constants.HealthProbeICMP)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrHealthProbeTypeNotValid, err)
+	}
+
+	if p.Timeout < 0 {
+		return fmt.Errorf("%w: %s", ErrHealthProbeTimeoutNegative, p.Timeout)
+	}
+
+	switch p.Type {
+	case constants.HealthProbeTCP, constants.HealthProbeDNS:
+		if p.Target == "" {
+			return fmt.Errorf("%w: target is empty", ErrHealthProbeTargetNotValid)
+		}
+	case constants.HealthProbeHTTP:
+		targetURL, err := url.Parse(p.Target)
+		switch {
+		case err != nil:
+			return fmt.Errorf("%w: %w", ErrHealthProbeTargetNotValid, err)
+		case targetURL.Scheme != "http" && targetURL.Scheme != "https":
+			return fmt.Errorf("%w: URL scheme %q is not http or https",
+				ErrHealthProbeTargetNotValid, targetURL.Scheme)
+		case targetURL.Host == "":
+			return fmt.Errorf("%w: URL host is empty", ErrHealthProbeTargetNotValid)
+		}
+
+		const minStatus, maxStatus = 100, 599
+		if p.Status < minStatus || p.Status > maxStatus {
+			return fmt.Errorf("%w: %d must be between %d and %d",
+				ErrHealthProbeHTTPStatusNotValid, p.Status, minStatus, maxStatus)
+		}
+	case constants.HealthProbeICMP:
+		_, err = netip.ParseAddr(p.Target)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrHealthProbeTargetNotValid, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *HealthProbe) setDefaults() {
+	if p.Type == constants.HealthProbeHTTP && p.Status == 0 {
+		p.Status = http.StatusOK
+	}
+}
+
+func (p HealthProbe) String() string {
+	s := p.Type + " " + p.Target
+	if p.Timeout > 0 {
+		s += " timeout=" + p.Timeout.String()
+	}
+	if p.Type == constants.HealthProbeHTTP {
+		s += " status=" + strconv.Itoa(p.Status)
+		if p.Body != "" {
+			s += " body=" + p.Body
+		}
+	}
+	return s
+}
+
+// parseHealthProbe parses a health probe from a string in the
+// format `<type> <target> [timeout=<duration>] [status=<code>] [body=<text>]`,
+// for example `http https://example.com/ timeout=3s status=200 body=ok`.
+func parseHealthProbe(s string) (probe HealthProbe, err error) {
+	fields := strings.Fields(s)
+	const minFields = 2
+	if len(fields) < minFields {
+		return probe, fmt.Errorf("%w: %q must have at least a type and a target",
+			ErrHealthProbeFormatNotValid, s)
+	}
+
+	probe.Type = strings.ToLower(fields[0])
+	probe.Target = fields[1]
+
+	for _, field := range fields[minFields:] {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return probe, fmt.Errorf("%w: option %q is not in the format key=value",
+				ErrHealthProbeFormatNotValid, field)
+		}
+
+		switch strings.ToLower(key) {
+		case "timeout":
+			probe.Timeout, err = time.ParseDuration(value)
+			if err != nil {
+				return probe, fmt.Errorf("parsing timeout: %w", err)
+			}
+		case "status":
+			probe.Status, err = strconv.Atoi(value)
+			if err != nil {
+				return probe, fmt.Errorf("parsing status: %w", err)
+			}
+		case "body":
+			probe.Body = value
+		default:
+			return probe, fmt.Errorf("%w: option key %q is unknown",
+				ErrHealthProbeFormatNotValid, key)
+		}
+	}
+
+	return probe, nil
+}
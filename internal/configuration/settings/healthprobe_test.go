@@ -0,0 +1,76 @@
+package settings
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseHealthProbe(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		s          string
+		probe      HealthProbe
+		errWrapped error
+		errMessage string
+	}{
+		"missing_target": {
+			s:          "tcp",
+			probe:      HealthProbe{},
+			errWrapped: ErrHealthProbeFormatNotValid,
+			errMessage: `health probe format is not valid: "tcp" must have at least a type and a target`,
+		},
+		"tcp": {
+			s: "TCP cloudflare.com:443",
+			probe: HealthProbe{
+				Type:   "tcp",
+				Target: "cloudflare.com:443",
+			},
+		},
+		"http_with_options": {
+			s: "http https://example.com/Status timeout=3s status=204 body=OK",
+			probe: HealthProbe{
+				Type:    "http",
+				Target:  "https://example.com/Status",
+				Timeout: 3 * time.Second,
+				Status:  204,
+				Body:    "OK",
+			},
+		},
+		"option_without_value": {
+			s: "icmp 1.1.1.1 timeout",
+			probe: HealthProbe{
+				Type:   "icmp",
+				Target: "1.1.1.1",
+			},
+			errWrapped: ErrHealthProbeFormatNotValid,
+			errMessage: `health probe format is not valid: option "timeout" is not in the format key=value`,
+		},
+		"unknown_option": {
+			s: "dns github.com retries=2",
+			probe: HealthProbe{
+				Type:   "dns",
+				Target: "github.com",
+			},
+			errWrapped: ErrHealthProbeFormatNotValid,
+			errMessage: `health probe format is not valid: option key "retries" is unknown`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			probe, err := parseHealthProbe(testCase.s)
+
+			assert.Equal(t, testCase.probe, probe)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
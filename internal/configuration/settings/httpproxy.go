@@ -16,32 +16,32 @@ import (
 type HTTPProxy struct {
 	// User is the username to use for the HTTP proxy.
 	// It cannot be nil in the internal state.
-	User *string
+	User *string `json:"user"`
 	// Password is the password to use for the HTTP proxy.
 	// It cannot be nil in the internal state.
-	Password *string
+	Password *string `json:"password"`
 	// ListeningAddress is the listening address
 	// of the HTTP proxy server.
 	// It cannot be the empty string in the internal state.
-	ListeningAddress string
+	ListeningAddress string `json:"listening_address"`
 	// Enabled is true if the HTTP proxy server should run,
 	// and false otherwise. It cannot be nil in the
 	// internal state.
-	Enabled *bool
+	Enabled *bool `json:"enabled"`
 	// Stealth is true if the HTTP proxy server should hide
 	// each request has been proxied to the destination.
 	// It cannot be nil in the internal state.
-	Stealth *bool
+	Stealth *bool `json:"stealth"`
 	// Log is true if the HTTP proxy server should log
 	// each request/response. It cannot be nil in the
 	// internal state.
-	Log *bool
+	Log *bool `json:"log"`
 	// ReadHeaderTimeout is the HTTP header read timeout duration
 	// of the HTTP server. It defaults to 1 second if left unset.
-	ReadHeaderTimeout time.Duration
+	ReadHeaderTimeout time.Duration `json:"read_header_timeout"`
 	// ReadTimeout is the HTTP read timeout duration
 	// of the HTTP server. It defaults to 3 seconds if left unset.
-	ReadTimeout time.Duration
+	ReadTimeout time.Duration `json:"read_timeout"`
 }
 
 func (h HTTPProxy) validate() (err error) {
@@ -13,7 +13,7 @@ import (
 type Log struct {
 	// Level is the log level of the logger.
 	// It cannot be empty in the internal state.
-	Level string
+	Level string `json:"level"`
 }
 
 func (l Log) validate() (err error) {
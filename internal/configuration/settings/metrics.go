@@ -0,0 +1,78 @@
+package settings
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gosettings/validate"
+	"github.com/qdm12/gotree"
+)
+
+// Metrics contains settings to configure the Prometheus metrics server.
+type Metrics struct {
+	// Enabled is true if the metrics server should be run.
+	// It defaults to false and cannot be nil in the internal state.
+	Enabled *bool `json:"enabled"`
+	// Address is the listening address of the metrics server.
+	// It defaults to :9090 and cannot be the empty string
+	// in the internal state.
+	Address string `json:"address"`
+}
+
+func (m Metrics) validate() (err error) {
+	err = validate.ListeningAddress(m.Address, os.Getuid())
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrServerAddressNotValid, m.Address)
+	}
+
+	return nil
+}
+
+func (m *Metrics) copy() (copied Metrics) {
+	return Metrics{
+		Enabled: gosettings.CopyPointer(m.Enabled),
+		Address: m.Address,
+	}
+}
+
+// overrideWith overrides fields of the receiver
+// settings object with any field set in the other
+// settings.
+func (m *Metrics) overrideWith(other Metrics) {
+	m.Enabled = gosettings.OverrideWithPointer(m.Enabled, other.Enabled)
+	m.Address = gosettings.OverrideWithComparable(m.Address, other.Address)
+}
+
+func (m *Metrics) setDefaults() {
+	m.Enabled = gosettings.DefaultPointer(m.Enabled, false)
+	m.Address = gosettings.DefaultComparable(m.Address, ":9090")
+}
+
+func (m Metrics) String() string {
+	return m.toLinesNode().String()
+}
+
+func (m Metrics) toLinesNode() (node *gotree.Node) {
+	node = gotree.New("Metrics settings:")
+	node.Appendf("Enabled: %s", gosettings.BoolToYesNo(m.Enabled))
+	if !*m.Enabled {
+		return node
+	}
+
+	node.Appendf("Listening address: %s", m.Address)
+
+	return node
+}
+
+func (m *Metrics) read(r *reader.Reader) (err error) {
+	m.Enabled, err = r.BoolPtr("METRICS_ENABLED")
+	if err != nil {
+		return err
+	}
+
+	m.Address = r.String("METRICS_SERVER_ADDRESS")
+
+	return nil
+}
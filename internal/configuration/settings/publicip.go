@@ -17,20 +17,20 @@ type PublicIP struct {
 	// It can be set to 0 to disable periodic checking.
 	// It cannot be nil for the internal state.
 	// TODO change to value and add enabled field
-	Period *time.Duration
+	Period *time.Duration `json:"period"`
 	// IPFilepath is the public IP address status file path
 	// to use. It can be the empty string to indicate not
 	// to write to a file. It cannot be nil for the
 	// internal state
-	IPFilepath *string
+	IPFilepath *string `json:"ip_filepath"`
 	// API is the API name to use to fetch public IP information.
 	// It can be ipinfo or ip2location. It defaults to ipinfo.
-	API string
+	API string `json:"api"`
 	// APIToken is the token to use for the IP data service
 	// such as ipinfo.io. It can be the empty string to
 	// indicate not to use a token. It cannot be nil for the
 	// internal state.
-	APIToken *string
+	APIToken *string `json:"api_token"`
 }
 
 // UpdateWith deep copies the receiving settings, overrides the copy with
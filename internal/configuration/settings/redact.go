@@ -0,0 +1,72 @@
+package settings
+
+// RedactedValue replaces secret values in redacted settings.
+const RedactedValue = "[redacted]"
+
+// Redacted returns a deep copy of the settings with all
+// secret values set replaced by RedactedValue.
+func (s Settings) Redacted() (redacted Settings) {
+	redacted = s.copy()
+
+	roles := redacted.ControlServer.Auth.Roles
+	for i := range roles {
+		roles[i].APIKey = redact(roles[i].APIKey)
+		roles[i].Password = redact(roles[i].Password)
+	}
+
+	redactOpenVPN(&redacted.VPN.OpenVPN)
+	redactWireguard(&redacted.VPN.Wireguard)
+	redactPortForwarding(&redacted.VPN.Provider.PortForwarding)
+
+	fallback := &redacted.VPN.Fallback
+	redactOpenVPN(&fallback.OpenVPN)
+	redactWireguard(&fallback.Wireguard)
+	redactPortForwarding(&fallback.Provider.PortForwarding)
+
+	for i := range redacted.VPN.Tunnels {
+		tunnel := &redacted.VPN.Tunnels[i]
+		redactWireguard(&tunnel.Wireguard)
+		tunnel.HTTPProxyPassword = redactPointer(tunnel.HTTPProxyPassword)
+	}
+
+	redacted.HTTPProxy.User = redactPointer(redacted.HTTPProxy.User)
+	redacted.HTTPProxy.Password = redactPointer(redacted.HTTPProxy.Password)
+	redacted.Shadowsocks.Password = redactPointer(redacted.Shadowsocks.Password)
+	redacted.PublicIP.APIToken = redactPointer(redacted.PublicIP.APIToken)
+
+	return redacted
+}
+
+func redactOpenVPN(openvpn *OpenVPN) {
+	openvpn.User = redactPointer(openvpn.User)
+	openvpn.Password = redactPointer(openvpn.Password)
+	openvpn.Cert = redactPointer(openvpn.Cert)
+	openvpn.Key = redactPointer(openvpn.Key)
+	openvpn.EncryptedKey = redactPointer(openvpn.EncryptedKey)
+	openvpn.KeyPassphrase = redactPointer(openvpn.KeyPassphrase)
+}
+
+func redactWireguard(wireguard *Wireguard) {
+	wireguard.PrivateKey = redactPointer(wireguard.PrivateKey)
+	wireguard.PreSharedKey = redactPointer(wireguard.PreSharedKey)
+}
+
+func redactPortForwarding(portForwarding *PortForwarding) {
+	portForwarding.Username = redact(portForwarding.Username)
+	portForwarding.Password = redact(portForwarding.Password)
+}
+
+func redact(value string) (redacted string) {
+	if value == "" {
+		return ""
+	}
+	return RedactedValue
+}
+
+func redactPointer(value *string) (redacted *string) {
+	if value == nil {
+		return nil
+	}
+	redactedValue := redact(*value)
+	return &redactedValue
+}
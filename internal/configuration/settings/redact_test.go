@@ -0,0 +1,73 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/server/middlewares/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Settings_Redacted(t *testing.T) {
+	t.Parallel()
+
+	ptrTo := func(s string) *string { return &s }
+
+	settings := Settings{
+		ControlServer: ControlServer{
+			Auth: auth.Settings{Roles: []auth.Role{
+				{Name: "a", APIKey: "key", Password: ""},
+			}},
+		},
+		VPN: VPN{
+			OpenVPN: OpenVPN{
+				User:     ptrTo("user"),
+				Password: ptrTo(""),
+			},
+			Wireguard: Wireguard{PrivateKey: ptrTo("private")},
+			Fallback: VPNFallback{
+				OpenVPN: OpenVPN{
+					User:          ptrTo("fallback_user"),
+					Password:      ptrTo("fallback_password"),
+					Cert:          ptrTo("cert"),
+					Key:           ptrTo("key"),
+					EncryptedKey:  ptrTo("encrypted_key"),
+					KeyPassphrase: ptrTo("passphrase"),
+				},
+				Wireguard: Wireguard{
+					PrivateKey:   ptrTo("fallback_private"),
+					PreSharedKey: ptrTo("fallback_preshared"),
+				},
+			},
+		},
+		HTTPProxy: HTTPProxy{Password: ptrTo("password")},
+		PublicIP:  PublicIP{APIToken: ptrTo("token")},
+	}
+
+	redacted := settings.Redacted()
+
+	assert.Equal(t, RedactedValue, redacted.ControlServer.Auth.Roles[0].APIKey)
+	assert.Empty(t, redacted.ControlServer.Auth.Roles[0].Password)
+	assert.Equal(t, RedactedValue, *redacted.VPN.OpenVPN.User)
+	assert.Empty(t, *redacted.VPN.OpenVPN.Password)
+	assert.Nil(t, redacted.VPN.OpenVPN.Key)
+	assert.Equal(t, RedactedValue, *redacted.VPN.Wireguard.PrivateKey)
+	fallback := redacted.VPN.Fallback
+	assert.Equal(t, RedactedValue, *fallback.OpenVPN.User)
+	assert.Equal(t, RedactedValue, *fallback.OpenVPN.Password)
+	assert.Equal(t, RedactedValue, *fallback.OpenVPN.Cert)
+	assert.Equal(t, RedactedValue, *fallback.OpenVPN.Key)
+	assert.Equal(t, RedactedValue, *fallback.OpenVPN.EncryptedKey)
+	assert.Equal(t, RedactedValue, *fallback.OpenVPN.KeyPassphrase)
+	assert.Equal(t, RedactedValue, *fallback.Wireguard.PrivateKey)
+	assert.Equal(t, RedactedValue, *fallback.Wireguard.PreSharedKey)
+	assert.Equal(t, RedactedValue, *redacted.HTTPProxy.Password)
+	assert.Nil(t, redacted.HTTPProxy.User)
+	assert.Equal(t, RedactedValue, *redacted.PublicIP.APIToken)
+
+	// Original settings are not modified
+	assert.Equal(t, "key", settings.ControlServer.Auth.Roles[0].APIKey)
+	assert.Equal(t, "user", *settings.VPN.OpenVPN.User)
+	assert.Equal(t, "private", *settings.VPN.Wireguard.PrivateKey)
+	assert.Equal(t, "fallback_user", *settings.VPN.Fallback.OpenVPN.User)
+	assert.Equal(t, "fallback_private", *settings.VPN.Fallback.Wireguard.PrivateKey)
+}
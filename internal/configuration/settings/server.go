@@ -4,35 +4,132 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"path/filepath"
 	"strconv"
 
+	"github.com/qdm12/gluetun/internal/httpserver"
 	"github.com/qdm12/gluetun/internal/server/middlewares/auth"
 	"github.com/qdm12/gosettings"
 	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gosettings/validate"
 	"github.com/qdm12/gotree"
 )
 
 // ControlServer contains settings to customize the control server operation.
 type ControlServer struct {
 	// Address is the listening address to use.
+	// It can be a TCP address such as :8000 or an absolute
+	// unix socket path prefixed with unix://, for example
+	// unix:///tmp/gluetun/control.sock.
 	// It cannot be nil in the internal state.
-	Address *string
+	Address *string `json:"address"`
+	// UnixSocketMode is the file mode to set on the unix socket
+	// file, and is only used if Address is a unix socket.
+	// It cannot be nil in the internal state and defaults to 0660.
+	UnixSocketMode *os.FileMode `json:"unix_socket_mode"`
+	// TLSCertificateFilepath is the path to the PEM encoded TLS
+	// certificate file to serve HTTPS. It defaults to the empty
+	// string, in which case the server serves plaintext HTTP.
+	TLSCertificateFilepath string `json:"tls_certificate_filepath"`
+	// TLSKeyFilepath is the path to the PEM encoded TLS key file,
+	// and must be set if TLSCertificateFilepath is set.
+	TLSKeyFilepath string `json:"tls_key_filepath"`
+	// TLSClientCAFilepath is the path to the PEM encoded certificate
+	// authority file used to verify client certificates, and must be
+	// set if any authentication role uses the 'mtls' method.
+	TLSClientCAFilepath string `json:"tls_client_ca_filepath"`
 	// Log can be true or false to enable logging on requests.
 	// It cannot be nil in the internal state.
-	Log *bool
+	Log *bool `json:"log"`
 	// AuthFilePath is the path to the file containing the authentication
 	// configuration for the middleware.
 	// It cannot be empty in the internal state and defaults to
 	// /gluetun/auth/config.toml.
-	AuthFilePath string
+	AuthFilePath string `json:"auth_file_path"`
+	// AuditFilepath is the path to the rotating audit file recording
+	// state changing requests. An empty string disables the audit log.
+	// It cannot be nil in the internal state and defaults to
+	// /gluetun/audit/audit.log.
+	AuditFilepath *string `json:"audit_filepath"`
 	// Auth contains settings for the authentication middleware.
 	// These are parsed from a configuration file specified by
 	// AuthFilePath.
-	Auth auth.Settings
+	Auth auth.Settings `json:"auth"`
 }
 
 func (c ControlServer) validate() (err error) {
-	_, portStr, err := net.SplitHostPort(*c.Address)
+	socketPath, isUnix := httpserver.UnixSocketPath(*c.Address)
+	if isUnix {
+		err = validateUnixSocket(socketPath, *c.UnixSocketMode)
+		if err != nil {
+			return fmt.Errorf("listening unix socket: %w", err)
+		}
+	} else {
+		err = validateListeningPort(*c.Address)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = c.validateTLS()
+	if err != nil {
+		return fmt.Errorf("TLS: %w", err)
+	}
+
+	if *c.AuditFilepath != "" {
+		_, err = filepath.Abs(*c.AuditFilepath)
+		if err != nil {
+			return fmt.Errorf("audit filepath is not valid: %w", err)
+		}
+	}
+
+	err = c.Auth.Validate()
+	if err != nil {
+		return fmt.Errorf("validating authentication middleware: %w", err)
+	}
+
+	return nil
+}
+
+func (c ControlServer) validateTLS() (err error) {
+	switch {
+	case c.TLSCertificateFilepath == "" && c.TLSKeyFilepath != "":
+		return fmt.Errorf("%w: certificate filepath", ErrFilepathMissing)
+	case c.TLSCertificateFilepath != "" && c.TLSKeyFilepath == "":
+		return fmt.Errorf("%w: key filepath", ErrFilepathMissing)
+	case c.TLSClientCAFilepath != "" && c.TLSCertificateFilepath == "":
+		return fmt.Errorf("%w: certificate filepath", ErrFilepathMissing)
+	case c.Auth.UsesMTLS() && c.TLSClientCAFilepath == "":
+		return fmt.Errorf("%w: client CA filepath", ErrControlServerMTLSNoClientCA)
+	}
+
+	for _, path := range [...]string{c.TLSCertificateFilepath, c.TLSKeyFilepath, c.TLSClientCAFilepath} {
+		if path == "" {
+			continue
+		}
+		err = validate.FileExists(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateUnixSocket(path string, mode os.FileMode) (err error) {
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("%w: %s", ErrUnixSocketPathNotAbsolute, path)
+	}
+
+	if mode&^os.ModePerm != 0 {
+		return fmt.Errorf("%w: %o", ErrUnixSocketModeNotValid, mode)
+	}
+
+	return nil
+}
+
+func validateListeningPort(address string) (err error) {
+	_, portStr, err := net.SplitHostPort(address)
 	if err != nil {
 		return fmt.Errorf("listening address is not valid: %w", err)
 	}
@@ -49,18 +146,20 @@ func (c ControlServer) validate() (err error) {
 			ErrControlServerPrivilegedPort, port, uid)
 	}
 
-	err = c.Auth.Validate()
-	if err != nil {
-		return fmt.Errorf("validating authentication middleware: %w", err)
-	}
-
 	return nil
 }
 
 func (c *ControlServer) copy() (copied ControlServer) {
 	return ControlServer{
-		Address: gosettings.CopyPointer(c.Address),
-		Log:     gosettings.CopyPointer(c.Log),
+		Address:                gosettings.CopyPointer(c.Address),
+		UnixSocketMode:         gosettings.CopyPointer(c.UnixSocketMode),
+		TLSCertificateFilepath: c.TLSCertificateFilepath,
+		TLSKeyFilepath:         c.TLSKeyFilepath,
+		TLSClientCAFilepath:    c.TLSClientCAFilepath,
+		Log:                    gosettings.CopyPointer(c.Log),
+		AuthFilePath:           c.AuthFilePath,
+		AuditFilepath:          gosettings.CopyPointer(c.AuditFilepath),
+		Auth:                   c.Auth.Copy(),
 	}
 }
 
@@ -69,15 +168,24 @@ func (c *ControlServer) copy() (copied ControlServer) {
 // settings.
 func (c *ControlServer) overrideWith(other ControlServer) {
 	c.Address = gosettings.OverrideWithPointer(c.Address, other.Address)
+	c.UnixSocketMode = gosettings.OverrideWithPointer(c.UnixSocketMode, other.UnixSocketMode)
+	c.TLSCertificateFilepath = gosettings.OverrideWithComparable(c.TLSCertificateFilepath,
+		other.TLSCertificateFilepath)
+	c.TLSKeyFilepath = gosettings.OverrideWithComparable(c.TLSKeyFilepath, other.TLSKeyFilepath)
+	c.TLSClientCAFilepath = gosettings.OverrideWithComparable(c.TLSClientCAFilepath, other.TLSClientCAFilepath)
 	c.Log = gosettings.OverrideWithPointer(c.Log, other.Log)
 	c.AuthFilePath = gosettings.OverrideWithComparable(c.AuthFilePath, other.AuthFilePath)
+	c.AuditFilepath = gosettings.OverrideWithPointer(c.AuditFilepath, other.AuditFilepath)
 	c.Auth.OverrideWith(other.Auth)
 }
 
 func (c *ControlServer) setDefaults() {
 	c.Address = gosettings.DefaultPointer(c.Address, ":8000")
+	const defaultUnixSocketMode os.FileMode = 0o660
+	c.UnixSocketMode = gosettings.DefaultPointer(c.UnixSocketMode, defaultUnixSocketMode)
 	c.Log = gosettings.DefaultPointer(c.Log, true)
 	c.AuthFilePath = gosettings.DefaultComparable(c.AuthFilePath, "/gluetun/auth/config.toml")
+	c.AuditFilepath = gosettings.DefaultPointer(c.AuditFilepath, "/gluetun/audit/audit.log")
 	c.Auth.SetDefaults()
 }
 
@@ -88,8 +196,24 @@ func (c ControlServer) String() string {
 func (c ControlServer) toLinesNode() (node *gotree.Node) {
 	node = gotree.New("Control server settings:")
 	node.Appendf("Listening address: %s", *c.Address)
+	if _, isUnix := httpserver.UnixSocketPath(*c.Address); isUnix {
+		node.Appendf("Unix socket file mode: %04o", *c.UnixSocketMode)
+	}
+	if c.TLSCertificateFilepath != "" {
+		tlsNode := node.Appendf("TLS:")
+		tlsNode.Appendf("Certificate filepath: %s", c.TLSCertificateFilepath)
+		tlsNode.Appendf("Key filepath: %s", c.TLSKeyFilepath)
+		if c.TLSClientCAFilepath != "" {
+			tlsNode.Appendf("Client certificate authority filepath: %s", c.TLSClientCAFilepath)
+		}
+	}
 	node.Appendf("Logging: %s", gosettings.BoolToYesNo(c.Log))
 	node.Appendf("Authentication file path: %s", c.AuthFilePath)
+	if *c.AuditFilepath == "" {
+		node.Appendf("Audit log: disabled")
+	} else {
+		node.Appendf("Audit log file path: %s", *c.AuditFilepath)
+	}
 	node.AppendNode(c.Auth.ToLinesNode())
 	return node
 }
@@ -102,6 +226,17 @@ func (c *ControlServer) read(r *reader.Reader) (err error) {
 
 	c.Address = r.Get("HTTP_CONTROL_SERVER_ADDRESS")
 
+	c.UnixSocketMode, err = readUnixSocketMode(r)
+	if err != nil {
+		return err
+	}
+
+	c.TLSCertificateFilepath = r.String("HTTP_CONTROL_SERVER_TLS_CERTIFICATE_FILEPATH")
+	c.TLSKeyFilepath = r.String("HTTP_CONTROL_SERVER_TLS_KEY_FILEPATH")
+	c.TLSClientCAFilepath = r.String("HTTP_CONTROL_SERVER_TLS_CLIENT_CA_FILEPATH")
+
+	c.AuditFilepath = r.Get("HTTP_CONTROL_SERVER_AUDIT_FILEPATH", reader.AcceptEmpty(true))
+
 	c.AuthFilePath = r.String("HTTP_CONTROL_SERVER_AUTH_CONFIG_FILEPATH")
 	if c.AuthFilePath != "" {
 		c.Auth, err = auth.Read(c.AuthFilePath)
@@ -112,3 +247,20 @@ func (c *ControlServer) read(r *reader.Reader) (err error) {
 
 	return nil
 }
+
+func readUnixSocketMode(r *reader.Reader) (mode *os.FileMode, err error) {
+	const envKey = "HTTP_CONTROL_SERVER_UNIX_SOCKET_MODE"
+	s := r.Get(envKey)
+	if s == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	const base, bitSize = 8, 32
+	value, err := strconv.ParseUint(*s, base, bitSize)
+	if err != nil {
+		return nil, fmt.Errorf("environment variable %s: "+
+			"parsing octal file mode: %w", envKey, err)
+	}
+
+	return ptrTo(os.FileMode(value)), nil
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
 	"github.com/qdm12/gluetun/internal/configuration/settings/validation"
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/constants/providers"
 	"github.com/qdm12/gluetun/internal/constants/vpn"
 	"github.com/qdm12/gluetun/internal/models"
@@ -76,6 +77,16 @@ type ServerSelection struct { //nolint:maligned
 	// Wireguard contains settings to select Wireguard servers
 	// and the final connection.
 	Wireguard WireguardSelection `json:"wireguard"`
+	// Strategy is the strategy to pick a connection from the
+	// filtered servers, and can be 'random', 'latency' or
+	// 'least_loaded'. The 'latency' strategy is not supported
+	// for OpenVPN over UDP.
+	// It cannot be nil in the internal state.
+	Strategy *string `json:"strategy"`
+	// LatencyCandidates is the number of randomly sampled
+	// connections to probe when using the 'latency' strategy.
+	// It cannot be nil or zero in the internal state.
+	LatencyCandidates *uint16 `json:"latency_candidates"`
 }
 
 var (
@@ -126,6 +137,23 @@ func (ss *ServerSelection) validate(vpnServiceProvider string,
 		return fmt.Errorf("for VPN service provider %s: %w", vpnServiceProvider, err)
 	}
 
+	err = validate.IsOneOf(*ss.Strategy, constants.SelectionRandom,
+		constants.SelectionLatency, constants.SelectionLeastLoaded)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSelectionStrategyNotValid, err)
+	}
+
+	if *ss.LatencyCandidates == 0 {
+		return fmt.Errorf("%w", ErrLatencyCandidatesZero)
+	}
+
+	// Servers using tls-auth or tls-crypt, which are most of them, drop
+	// the unauthenticated packets an OpenVPN UDP latency probe can send.
+	if *ss.Strategy == constants.SelectionLatency &&
+		ss.VPN == vpn.OpenVPN && ss.OpenVPN.Protocol == constants.UDP {
+		return fmt.Errorf("%w", ErrSelectionLatencyOpenVPNUDP)
+	}
+
 	if ss.VPN == vpn.OpenVPN {
 		err = ss.OpenVPN.validate(vpnServiceProvider)
 		if err != nil {
@@ -292,26 +320,28 @@ func validateFeatureFilters(settings ServerSelection, vpnServiceProvider string)
 
 func (ss *ServerSelection) copy() (copied ServerSelection) {
 	return ServerSelection{
-		VPN:             ss.VPN,
-		TargetIP:        ss.TargetIP,
-		Countries:       gosettings.CopySlice(ss.Countries),
-		Categories:      gosettings.CopySlice(ss.Categories),
-		Regions:         gosettings.CopySlice(ss.Regions),
-		Cities:          gosettings.CopySlice(ss.Cities),
-		ISPs:            gosettings.CopySlice(ss.ISPs),
-		Hostnames:       gosettings.CopySlice(ss.Hostnames),
-		Names:           gosettings.CopySlice(ss.Names),
-		Numbers:         gosettings.CopySlice(ss.Numbers),
-		OwnedOnly:       gosettings.CopyPointer(ss.OwnedOnly),
-		FreeOnly:        gosettings.CopyPointer(ss.FreeOnly),
-		PremiumOnly:     gosettings.CopyPointer(ss.PremiumOnly),
-		StreamOnly:      gosettings.CopyPointer(ss.StreamOnly),
-		SecureCoreOnly:  gosettings.CopyPointer(ss.SecureCoreOnly),
-		TorOnly:         gosettings.CopyPointer(ss.TorOnly),
-		PortForwardOnly: gosettings.CopyPointer(ss.PortForwardOnly),
-		MultiHopOnly:    gosettings.CopyPointer(ss.MultiHopOnly),
-		OpenVPN:         ss.OpenVPN.copy(),
-		Wireguard:       ss.Wireguard.copy(),
+		VPN:               ss.VPN,
+		TargetIP:          ss.TargetIP,
+		Countries:         gosettings.CopySlice(ss.Countries),
+		Categories:        gosettings.CopySlice(ss.Categories),
+		Regions:           gosettings.CopySlice(ss.Regions),
+		Cities:            gosettings.CopySlice(ss.Cities),
+		ISPs:              gosettings.CopySlice(ss.ISPs),
+		Hostnames:         gosettings.CopySlice(ss.Hostnames),
+		Names:             gosettings.CopySlice(ss.Names),
+		Numbers:           gosettings.CopySlice(ss.Numbers),
+		OwnedOnly:         gosettings.CopyPointer(ss.OwnedOnly),
+		FreeOnly:          gosettings.CopyPointer(ss.FreeOnly),
+		PremiumOnly:       gosettings.CopyPointer(ss.PremiumOnly),
+		StreamOnly:        gosettings.CopyPointer(ss.StreamOnly),
+		SecureCoreOnly:    gosettings.CopyPointer(ss.SecureCoreOnly),
+		TorOnly:           gosettings.CopyPointer(ss.TorOnly),
+		PortForwardOnly:   gosettings.CopyPointer(ss.PortForwardOnly),
+		MultiHopOnly:      gosettings.CopyPointer(ss.MultiHopOnly),
+		OpenVPN:           ss.OpenVPN.copy(),
+		Wireguard:         ss.Wireguard.copy(),
+		Strategy:          gosettings.CopyPointer(ss.Strategy),
+		LatencyCandidates: gosettings.CopyPointer(ss.LatencyCandidates),
 	}
 }
 
@@ -334,6 +364,8 @@ func (ss *ServerSelection) overrideWith(other ServerSelection) {
 	ss.TorOnly = gosettings.OverrideWithPointer(ss.TorOnly, other.TorOnly)
 	ss.MultiHopOnly = gosettings.OverrideWithPointer(ss.MultiHopOnly, other.MultiHopOnly)
 	ss.PortForwardOnly = gosettings.OverrideWithPointer(ss.PortForwardOnly, other.PortForwardOnly)
+	ss.Strategy = gosettings.OverrideWithPointer(ss.Strategy, other.Strategy)
+	ss.LatencyCandidates = gosettings.OverrideWithPointer(ss.LatencyCandidates, other.LatencyCandidates)
 	ss.OpenVPN.overrideWith(other.OpenVPN)
 	ss.Wireguard.overrideWith(other.Wireguard)
 }
@@ -354,6 +386,9 @@ func (ss *ServerSelection) setDefaults(vpnProvider string, portForwardingEnabled
 		defaultPortForwardOnly = true
 	}
 	ss.PortForwardOnly = gosettings.DefaultPointer(ss.PortForwardOnly, defaultPortForwardOnly)
+	ss.Strategy = gosettings.DefaultPointer(ss.Strategy, constants.SelectionRandom)
+	const defaultLatencyCandidates = 5
+	ss.LatencyCandidates = gosettings.DefaultPointer(ss.LatencyCandidates, defaultLatencyCandidates)
 	ss.OpenVPN.setDefaults(vpnProvider)
 	ss.Wireguard.setDefaults()
 }
@@ -436,6 +471,13 @@ func (ss ServerSelection) toLinesNode() (node *gotree.Node) {
 		node.Appendf("Port forwarding only servers: yes")
 	}
 
+	switch *ss.Strategy {
+	case constants.SelectionLatency:
+		node.Appendf("Selection strategy: lowest latency of %d candidates", *ss.LatencyCandidates)
+	case constants.SelectionLeastLoaded:
+		node.Appendf("Selection strategy: least loaded")
+	}
+
 	if ss.VPN == vpn.OpenVPN {
 		node.AppendNode(ss.OpenVPN.toLinesNode())
 	} else {
@@ -528,6 +570,13 @@ func (ss *ServerSelection) read(r *reader.Reader,
 		return err
 	}
 
+	ss.Strategy = r.Get("SERVER_SELECTION_STRATEGY")
+
+	ss.LatencyCandidates, err = r.Uint16Ptr("SERVER_LATENCY_CANDIDATES")
+	if err != nil {
+		return err
+	}
+
 	err = ss.OpenVPN.read(r)
 	if err != nil {
 		return err
@@ -14,20 +14,21 @@ import (
 )
 
 type Settings struct {
-	ControlServer ControlServer
-	DNS           DNS
-	Firewall      Firewall
-	Health        Health
-	HTTPProxy     HTTPProxy
-	Log           Log
-	PublicIP      PublicIP
-	Shadowsocks   Shadowsocks
-	Storage       Storage
-	System        System
-	Updater       Updater
-	Version       Version
-	VPN           VPN
-	Pprof         pprof.Settings
+	ControlServer ControlServer  `json:"control_server"`
+	DNS           DNS            `json:"dns"`
+	Firewall      Firewall       `json:"firewall"`
+	Health        Health         `json:"health"`
+	HTTPProxy     HTTPProxy      `json:"http_proxy"`
+	Log           Log            `json:"log"`
+	Metrics       Metrics        `json:"metrics"`
+	PublicIP      PublicIP       `json:"public_ip"`
+	Shadowsocks   Shadowsocks    `json:"shadowsocks"`
+	Storage       Storage        `json:"storage"`
+	System        System         `json:"system"`
+	Updater       Updater        `json:"updater"`
+	Version       Version        `json:"version"`
+	VPN           VPN            `json:"vpn"`
+	Pprof         pprof.Settings `json:"pprof"`
 }
 
 type FilterChoicesGetter interface {
@@ -46,6 +47,7 @@ func (s *Settings) Validate(filterChoicesGetter FilterChoicesGetter, ipv6Support
 		"health":          s.Health.Validate,
 		"http proxy":      s.HTTPProxy.validate,
 		"log":             s.Log.validate,
+		"metrics":         s.Metrics.validate,
 		"public ip check": s.PublicIP.validate,
 		"shadowsocks":     s.Shadowsocks.validate,
 		"storage":         s.Storage.validate,
@@ -65,6 +67,16 @@ func (s *Settings) Validate(filterChoicesGetter FilterChoicesGetter, ipv6Support
 		}
 	}
 
+	err = validateTunnelsHTTPProxyUser(s.VPN.Tunnels, *s.HTTPProxy.User)
+	if err != nil {
+		return fmt.Errorf("VPN settings: %w", err)
+	}
+
+	if len(s.Firewall.OutboundDomains) > 0 && !*s.DNS.DoT.Enabled {
+		warner.Warn("firewall outbound domains are only effective " +
+			"if the DNS over TLS server is enabled")
+	}
+
 	return nil
 }
 
@@ -76,6 +88,7 @@ func (s *Settings) copy() (copied Settings) {
 		Health:        s.Health.copy(),
 		HTTPProxy:     s.HTTPProxy.copy(),
 		Log:           s.Log.copy(),
+		Metrics:       s.Metrics.copy(),
 		PublicIP:      s.PublicIP.copy(),
 		Shadowsocks:   s.Shadowsocks.copy(),
 		Storage:       s.Storage.copy(),
@@ -96,6 +109,7 @@ func (s *Settings) OverrideWith(other Settings,
 	patchedSettings.Health.OverrideWith(other.Health)
 	patchedSettings.HTTPProxy.overrideWith(other.HTTPProxy)
 	patchedSettings.Log.overrideWith(other.Log)
+	patchedSettings.Metrics.overrideWith(other.Metrics)
 	patchedSettings.PublicIP.overrideWith(other.PublicIP)
 	patchedSettings.Shadowsocks.overrideWith(other.Shadowsocks)
 	patchedSettings.Storage.overrideWith(other.Storage)
@@ -119,6 +133,7 @@ func (s *Settings) SetDefaults() {
 	s.Health.SetDefaults()
 	s.HTTPProxy.setDefaults()
 	s.Log.setDefaults()
+	s.Metrics.setDefaults()
 	s.PublicIP.setDefaults()
 	s.Shadowsocks.setDefaults()
 	s.Storage.setDefaults()
@@ -144,6 +159,7 @@ func (s Settings) toLinesNode() (node *gotree.Node) {
 	node.AppendNode(s.Shadowsocks.toLinesNode())
 	node.AppendNode(s.HTTPProxy.toLinesNode())
 	node.AppendNode(s.ControlServer.toLinesNode())
+	node.AppendNode(s.Metrics.toLinesNode())
 	node.AppendNode(s.Storage.toLinesNode())
 	node.AppendNode(s.System.toLinesNode())
 	node.AppendNode(s.PublicIP.toLinesNode())
@@ -197,6 +213,7 @@ func (s *Settings) Read(r *reader.Reader, warner Warner) (err error) {
 		"health":         s.Health.Read,
 		"http proxy":     s.HTTPProxy.read,
 		"log":            s.Log.read,
+		"metrics":        s.Metrics.read,
 		"public ip":      s.PublicIP.read,
 		"shadowsocks":    s.Shadowsocks.read,
 		"storage":        s.Storage.read,
@@ -57,10 +57,13 @@ func Test_Settings_String(t *testing.T) {
 |   └── Log level: INFO
 ├── Health settings:
 |   ├── Server listening address: 127.0.0.1:9999
-|   ├── Target address: cloudflare.com:443
+|   ├── Probes (1 of 1 must succeed):
+|   |   └── tcp cloudflare.com:443
 |   ├── Duration to wait after success: 5s
 |   ├── Read header timeout: 100ms
 |   ├── Read timeout: 500ms
+|   ├── Flapping detection: 6 transitions in 10m0s
+|   ├── Ready only with a forwarded port: no
 |   └── VPN wait durations:
 |       ├── Initial duration: 6s
 |       └── Additional duration: 5s
@@ -72,8 +75,11 @@ func Test_Settings_String(t *testing.T) {
 |   ├── Listening address: :8000
 |   ├── Logging: yes
 |   ├── Authentication file path: /gluetun/auth/config.toml
+|   ├── Audit log file path: /gluetun/audit/audit.log
 |   └── Authentication middleware settings:
 |       └── Roles defined: public
+├── Metrics settings:
+|   └── Enabled: no
 ├── Storage settings:
 |   └── Filepath: /gluetun/servers.json
 ├── OS Alpine settings:
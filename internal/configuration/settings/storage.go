@@ -12,7 +12,7 @@ import (
 // Storage contains settings to configure the storage.
 type Storage struct {
 	// Filepath is the path to the servers.json file. An empty string disables on-disk storage.
-	Filepath *string
+	Filepath *string `json:"filepath"`
 }
 
 func (s Storage) validate() (err error) {
@@ -8,9 +8,9 @@ import (
 
 // System contains settings to configure system related elements.
 type System struct {
-	PUID     *uint32
-	PGID     *uint32
-	Timezone string
+	PUID     *uint32 `json:"puid"`
+	PGID     *uint32 `json:"pgid"`
+	Timezone string  `json:"timezone"`
 }
 
 // Validate validates System settings.
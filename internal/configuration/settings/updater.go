@@ -19,18 +19,23 @@ type Updater struct {
 	// should run. It can be set to 0 to disable the
 	// updater. It cannot be nil in the internal state.
 	// TODO change to value and add Enabled field.
-	Period *time.Duration
+	Period *time.Duration `json:"period"`
+	// LoadPeriod is the period to refresh the server loads
+	// for the providers supporting it, independently from the
+	// full server data update. It can be set to 0 to disable
+	// refreshing server loads. It cannot be nil in the internal state.
+	LoadPeriod *time.Duration `json:"load_period"`
 	// DNSAddress is the DNS server address to use
 	// to resolve VPN server hostnames to IP addresses.
 	// It cannot be the empty string in the internal state.
-	DNSAddress string
+	DNSAddress string `json:"dns_address"`
 	// MinRatio is the minimum ratio of servers to
 	// find per provider, compared to the total current
 	// number of servers. It defaults to 0.8.
-	MinRatio float64
+	MinRatio float64 `json:"min_ratio"`
 	// Providers is the list of VPN service providers
 	// to update server information for.
-	Providers []string
+	Providers []string `json:"providers"`
 }
 
 func (u Updater) Validate() (err error) {
@@ -40,6 +45,11 @@ func (u Updater) Validate() (err error) {
 			ErrUpdaterPeriodTooSmall, *u.Period, minPeriod)
 	}
 
+	if *u.LoadPeriod > 0 && *u.LoadPeriod < minPeriod {
+		return fmt.Errorf("%w: %d must be larger than %s",
+			ErrUpdaterLoadPeriodTooSmall, *u.LoadPeriod, minPeriod)
+	}
+
 	if u.MinRatio <= 0 || u.MinRatio > 1 {
 		return fmt.Errorf("%w: %.2f must be between 0+ and 1",
 			ErrMinRatioNotValid, u.MinRatio)
@@ -59,6 +69,7 @@ func (u Updater) Validate() (err error) {
 func (u *Updater) copy() (copied Updater) {
 	return Updater{
 		Period:     gosettings.CopyPointer(u.Period),
+		LoadPeriod: gosettings.CopyPointer(u.LoadPeriod),
 		DNSAddress: u.DNSAddress,
 		MinRatio:   u.MinRatio,
 		Providers:  gosettings.CopySlice(u.Providers),
@@ -70,6 +81,7 @@ func (u *Updater) copy() (copied Updater) {
 // settings.
 func (u *Updater) overrideWith(other Updater) {
 	u.Period = gosettings.OverrideWithPointer(u.Period, other.Period)
+	u.LoadPeriod = gosettings.OverrideWithPointer(u.LoadPeriod, other.LoadPeriod)
 	u.DNSAddress = gosettings.OverrideWithComparable(u.DNSAddress, other.DNSAddress)
 	u.MinRatio = gosettings.OverrideWithComparable(u.MinRatio, other.MinRatio)
 	u.Providers = gosettings.OverrideWithSlice(u.Providers, other.Providers)
@@ -77,6 +89,7 @@ func (u *Updater) overrideWith(other Updater) {
 
 func (u *Updater) SetDefaults(vpnProvider string) {
 	u.Period = gosettings.DefaultPointer(u.Period, 0)
+	u.LoadPeriod = gosettings.DefaultPointer(u.LoadPeriod, 0)
 	u.DNSAddress = gosettings.DefaultComparable(u.DNSAddress, "1.1.1.1:53")
 
 	if u.MinRatio == 0 {
@@ -94,12 +107,17 @@ func (u Updater) String() string {
 }
 
 func (u Updater) toLinesNode() (node *gotree.Node) {
-	if *u.Period == 0 || len(u.Providers) == 0 {
+	if (*u.Period == 0 && *u.LoadPeriod == 0) || len(u.Providers) == 0 {
 		return nil
 	}
 
 	node = gotree.New("Server data updater settings:")
-	node.Appendf("Update period: %s", *u.Period)
+	if *u.Period > 0 {
+		node.Appendf("Update period: %s", *u.Period)
+	}
+	if *u.LoadPeriod > 0 {
+		node.Appendf("Server loads update period: %s", *u.LoadPeriod)
+	}
 	node.Appendf("DNS address: %s", u.DNSAddress)
 	node.Appendf("Minimum ratio: %.1f", u.MinRatio)
 	node.Appendf("Providers to update: %s", strings.Join(u.Providers, ", "))
@@ -113,6 +131,11 @@ func (u *Updater) read(r *reader.Reader) (err error) {
 		return err
 	}
 
+	u.LoadPeriod, err = r.DurationPtr("UPDATER_LOAD_PERIOD")
+	if err != nil {
+		return err
+	}
+
 	u.DNSAddress, err = readUpdaterDNSAddress()
 	if err != nil {
 		return err
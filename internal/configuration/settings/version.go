@@ -11,7 +11,7 @@ import (
 type Version struct {
 	// Enabled is true if the version information should
 	// be fetched from Github.
-	Enabled *bool
+	Enabled *bool `json:"enabled"`
 }
 
 func (v Version) validate() (err error) {
@@ -14,10 +14,20 @@ type VPN struct {
 	// Type is the VPN type and can only be
 	// 'openvpn' or 'wireguard'. It cannot be the
 	// empty string in the internal state.
-	Type      string    `json:"type"`
-	Provider  Provider  `json:"provider"`
-	OpenVPN   OpenVPN   `json:"openvpn"`
-	Wireguard Wireguard `json:"wireguard"`
+	Type      string      `json:"type"`
+	Provider  Provider    `json:"provider"`
+	OpenVPN   OpenVPN     `json:"openvpn"`
+	Wireguard Wireguard   `json:"wireguard"`
+	Rotation  VPNRotation `json:"rotation"`
+	Failover  VPNFailover `json:"failover"`
+	Fallback  VPNFallback `json:"fallback"`
+	// Escalation contains the escalation ladder
+	// settings used when the VPN is unhealthy.
+	Escalation VPNEscalation `json:"escalation"`
+	// Tunnels are additional Wireguard tunnels running
+	// alongside the main VPN connection.
+	// It cannot be nil in the internal state.
+	Tunnels []VPNTunnel `json:"tunnels"`
 }
 
 // TODO v4 remove pointer for receiver (because of Surfshark).
@@ -45,15 +55,46 @@ func (v *VPN) Validate(filterChoicesGetter FilterChoicesGetter, ipv6Supported bo
 		}
 	}
 
+	err = v.Rotation.validate()
+	if err != nil {
+		return fmt.Errorf("rotation settings: %w", err)
+	}
+
+	err = v.Failover.validate()
+	if err != nil {
+		return fmt.Errorf("failover settings: %w", err)
+	}
+
+	err = v.Fallback.validate(filterChoicesGetter, ipv6Supported, warner)
+	if err != nil {
+		return fmt.Errorf("fallback settings: %w", err)
+	}
+
+	err = v.Escalation.validate(v.Provider.Name, filterChoicesGetter, warner)
+	if err != nil {
+		return fmt.Errorf("escalation settings: %w", err)
+	}
+
+	mainInterfaces := []string{v.OpenVPN.Interface, v.Wireguard.Interface}
+	err = validateTunnels(v.Tunnels, mainInterfaces, filterChoicesGetter, ipv6Supported, warner)
+	if err != nil {
+		return fmt.Errorf("tunnels settings: %w", err)
+	}
+
 	return nil
 }
 
 func (v *VPN) Copy() (copied VPN) {
 	return VPN{
-		Type:      v.Type,
-		Provider:  v.Provider.copy(),
-		OpenVPN:   v.OpenVPN.copy(),
-		Wireguard: v.Wireguard.copy(),
+		Type:       v.Type,
+		Provider:   v.Provider.copy(),
+		OpenVPN:    v.OpenVPN.copy(),
+		Wireguard:  v.Wireguard.copy(),
+		Rotation:   v.Rotation.copy(),
+		Failover:   v.Failover.copy(),
+		Fallback:   v.Fallback.copy(),
+		Escalation: v.Escalation.copy(),
+		Tunnels:    copyTunnels(v.Tunnels),
 	}
 }
 
@@ -62,6 +103,11 @@ func (v *VPN) OverrideWith(other VPN) {
 	v.Provider.overrideWith(other.Provider)
 	v.OpenVPN.overrideWith(other.OpenVPN)
 	v.Wireguard.overrideWith(other.Wireguard)
+	v.Rotation.overrideWith(other.Rotation)
+	v.Failover.overrideWith(other.Failover)
+	v.Fallback.overrideWith(other.Fallback)
+	v.Escalation.overrideWith(other.Escalation)
+	v.Tunnels = gosettings.OverrideWithSlice(v.Tunnels, other.Tunnels)
 }
 
 func (v *VPN) setDefaults() {
@@ -69,6 +115,14 @@ func (v *VPN) setDefaults() {
 	v.Provider.setDefaults()
 	v.OpenVPN.setDefaults(v.Provider.Name)
 	v.Wireguard.setDefaults(v.Provider.Name)
+	v.Rotation.setDefaults()
+	v.Failover.setDefaults()
+	v.Fallback.setDefaults()
+	v.Escalation.setDefaults()
+	v.Tunnels = gosettings.DefaultSlice(v.Tunnels, []VPNTunnel{})
+	for i := range v.Tunnels {
+		v.Tunnels[i].setDefaults(i)
+	}
 }
 
 func (v VPN) String() string {
@@ -86,6 +140,15 @@ func (v VPN) toLinesNode() (node *gotree.Node) {
 		node.AppendNode(v.Wireguard.toLinesNode())
 	}
 
+	node.AppendNode(v.Rotation.toLinesNode())
+	node.AppendNode(v.Failover.toLinesNode())
+	node.AppendNode(v.Fallback.toLinesNode())
+	node.AppendNode(v.Escalation.toLinesNode())
+
+	for _, tunnel := range v.Tunnels {
+		node.AppendNode(tunnel.toLinesNode())
+	}
+
 	return node
 }
 
@@ -107,5 +170,27 @@ func (v *VPN) read(r *reader.Reader) (err error) {
 		return fmt.Errorf("wireguard: %w", err)
 	}
 
+	err = v.Rotation.read(r)
+	if err != nil {
+		return fmt.Errorf("rotation: %w", err)
+	}
+
+	err = v.Failover.read(r)
+	if err != nil {
+		return fmt.Errorf("failover: %w", err)
+	}
+
+	err = v.Fallback.read(r)
+	if err != nil {
+		return fmt.Errorf("fallback: %w", err)
+	}
+
+	err = v.Escalation.read(r)
+	if err != nil {
+		return fmt.Errorf("escalation: %w", err)
+	}
+
+	v.Tunnels = readTunnelNames(r)
+
 	return nil
 }
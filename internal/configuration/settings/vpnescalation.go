@@ -0,0 +1,170 @@
+package settings
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gotree"
+)
+
+// VPNEscalation contains settings for the escalation ladder used
+// when the VPN is unhealthy: restart the same server, then switch
+// to a different server, then switch to a different location and
+// finally stop restarting the VPN.
+type VPNEscalation struct {
+	// Enabled is true if the escalation ladder should be used,
+	// instead of always restarting the VPN the same way.
+	// It cannot be nil in the internal state.
+	Enabled *bool `json:"enabled"`
+	// SameServerRestarts is the number of unhealthy restarts
+	// on the same server before switching to another server.
+	// It cannot be nil in the internal state.
+	SameServerRestarts *uint `json:"same_server_restarts"`
+	// ServerSwitches is the number of unhealthy restarts
+	// switching to another server matching the server selection
+	// before switching to a fallback location.
+	// It cannot be nil in the internal state.
+	ServerSwitches *uint `json:"server_switches"`
+	// Locations are the fallback locations to try in order once
+	// the server switches are exhausted, each replacing the
+	// location filters of the server selection for one restart.
+	// They only apply to the primary VPN provider.
+	// It cannot be nil in the internal state.
+	Locations []EscalationLocation `json:"locations"`
+}
+
+// EscalationLocation is a fallback location for the escalation ladder.
+type EscalationLocation struct {
+	// Country is the country to use. It cannot be the empty string.
+	Country string `json:"country"`
+	// City is the city to use, and can be the empty string
+	// to use any city of the country.
+	City string `json:"city,omitempty"`
+}
+
+func (e EscalationLocation) String() string {
+	if e.City == "" {
+		return e.Country
+	}
+	return e.Country + "/" + e.City
+}
+
+// Apply returns the server selection given with its location
+// filters replaced by the location.
+func (e EscalationLocation) Apply(selection ServerSelection) (applied ServerSelection) {
+	applied = selection.copy()
+	applied.Countries = []string{e.Country}
+	applied.Regions = nil
+	applied.Cities = nil
+	if e.City != "" {
+		applied.Cities = []string{e.City}
+	}
+	applied.Hostnames = nil
+	applied.Names = nil
+	return applied
+}
+
+func (v VPNEscalation) validate(vpnProvider string,
+	filterChoicesGetter FilterChoicesGetter, warner Warner) (err error) {
+	if !*v.Enabled {
+		return nil
+	}
+
+	filterChoices := filterChoicesGetter.GetFilterChoices(vpnProvider)
+	for _, location := range v.Locations {
+		err = atLeastOneIsOneOfCaseInsensitive([]string{location.Country},
+			filterChoices.Countries, warner)
+		if err != nil {
+			return fmt.Errorf("location %s: %w: %w", location, ErrCountryNotValid, err)
+		}
+
+		if location.City == "" {
+			continue
+		}
+		err = atLeastOneIsOneOfCaseInsensitive([]string{location.City},
+			filterChoices.Cities, warner)
+		if err != nil {
+			return fmt.Errorf("location %s: %w: %w", location, ErrCityNotValid, err)
+		}
+	}
+	return nil
+}
+
+func (v *VPNEscalation) copy() (copied VPNEscalation) {
+	return VPNEscalation{
+		Enabled:            gosettings.CopyPointer(v.Enabled),
+		SameServerRestarts: gosettings.CopyPointer(v.SameServerRestarts),
+		ServerSwitches:     gosettings.CopyPointer(v.ServerSwitches),
+		Locations:          gosettings.CopySlice(v.Locations),
+	}
+}
+
+func (v *VPNEscalation) overrideWith(other VPNEscalation) {
+	v.Enabled = gosettings.OverrideWithPointer(v.Enabled, other.Enabled)
+	v.SameServerRestarts = gosettings.OverrideWithPointer(v.SameServerRestarts, other.SameServerRestarts)
+	v.ServerSwitches = gosettings.OverrideWithPointer(v.ServerSwitches, other.ServerSwitches)
+	v.Locations = gosettings.OverrideWithSlice(v.Locations, other.Locations)
+}
+
+func (v *VPNEscalation) setDefaults() {
+	v.Enabled = gosettings.DefaultPointer(v.Enabled, false)
+	const defaultSameServerRestarts = 1
+	v.SameServerRestarts = gosettings.DefaultPointer(v.SameServerRestarts, defaultSameServerRestarts)
+	const defaultServerSwitches = 2
+	v.ServerSwitches = gosettings.DefaultPointer(v.ServerSwitches, defaultServerSwitches)
+	v.Locations = gosettings.DefaultSlice(v.Locations, []EscalationLocation{})
+}
+
+func (v VPNEscalation) String() string {
+	return v.toLinesNode().String()
+}
+
+func (v VPNEscalation) toLinesNode() (node *gotree.Node) {
+	if !*v.Enabled {
+		return nil
+	}
+
+	node = gotree.New("Unhealthy escalation settings:")
+	node.Appendf("Same server restarts: %d", *v.SameServerRestarts)
+	node.Appendf("Server switches: %d", *v.ServerSwitches)
+	if len(v.Locations) > 0 {
+		locationsNode := node.Appendf("Fallback locations:")
+		for _, location := range v.Locations {
+			locationsNode.Appendf("%s", location)
+		}
+	}
+	return node
+}
+
+func (v *VPNEscalation) read(r *reader.Reader) (err error) {
+	v.Enabled, err = r.BoolPtr("VPN_ESCALATION")
+	if err != nil {
+		return err
+	}
+
+	v.SameServerRestarts, err = r.UintPtr("VPN_ESCALATION_SAME_SERVER_RESTARTS")
+	if err != nil {
+		return err
+	}
+
+	v.ServerSwitches, err = r.UintPtr("VPN_ESCALATION_SERVER_SWITCHES")
+	if err != nil {
+		return err
+	}
+
+	locations := r.CSV("VPN_ESCALATION_LOCATIONS", reader.ForceLowercase(false))
+	if len(locations) > 0 {
+		v.Locations = make([]EscalationLocation, len(locations))
+		for i, location := range locations {
+			country, city, _ := strings.Cut(location, "/")
+			v.Locations[i] = EscalationLocation{
+				Country: strings.TrimSpace(country),
+				City:    strings.TrimSpace(city),
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package settings
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gotree"
+)
+
+// VPNFailover contains settings to try the filtered servers
+// in order when the VPN connection fails, instead of picking
+// a random server again.
+type VPNFailover struct {
+	// Enabled is true if the ordered failover should be used.
+	// It cannot be nil in the internal state.
+	Enabled *bool `json:"enabled"`
+	// Cooldown is the duration during which a server endpoint
+	// which failed is skipped when walking the candidate servers.
+	// It cannot be nil in the internal state.
+	Cooldown *time.Duration `json:"cooldown"`
+}
+
+func (v VPNFailover) validate() (err error) {
+	if *v.Cooldown <= 0 {
+		return fmt.Errorf("%w: %s", ErrFailoverCooldownNotPositive, *v.Cooldown)
+	}
+	return nil
+}
+
+func (v *VPNFailover) copy() (copied VPNFailover) {
+	return VPNFailover{
+		Enabled:  gosettings.CopyPointer(v.Enabled),
+		Cooldown: gosettings.CopyPointer(v.Cooldown),
+	}
+}
+
+func (v *VPNFailover) overrideWith(other VPNFailover) {
+	v.Enabled = gosettings.OverrideWithPointer(v.Enabled, other.Enabled)
+	v.Cooldown = gosettings.OverrideWithPointer(v.Cooldown, other.Cooldown)
+}
+
+func (v *VPNFailover) setDefaults() {
+	v.Enabled = gosettings.DefaultPointer(v.Enabled, false)
+	const defaultCooldown = 10 * time.Minute
+	v.Cooldown = gosettings.DefaultPointer(v.Cooldown, defaultCooldown)
+}
+
+func (v VPNFailover) String() string {
+	return v.toLinesNode().String()
+}
+
+func (v VPNFailover) toLinesNode() (node *gotree.Node) {
+	if !*v.Enabled {
+		return nil
+	}
+
+	node = gotree.New("Server failover settings:")
+	node.Appendf("Failed server cooldown: %s", *v.Cooldown)
+	return node
+}
+
+func (v *VPNFailover) read(r *reader.Reader) (err error) {
+	v.Enabled, err = r.BoolPtr("VPN_FAILOVER")
+	if err != nil {
+		return err
+	}
+
+	v.Cooldown, err = r.DurationPtr("VPN_FAILOVER_COOLDOWN")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
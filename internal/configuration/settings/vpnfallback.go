@@ -0,0 +1,188 @@
+package settings
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gosettings/validate"
+	"github.com/qdm12/gotree"
+)
+
+// VPNFallback contains settings to switch to a secondary VPN
+// provider after consecutive failures of the primary VPN provider,
+// and to switch back to the primary VPN provider after a while.
+type VPNFallback struct {
+	// Enabled is true if the fallback VPN provider should be used.
+	// It cannot be nil in the internal state.
+	Enabled *bool `json:"enabled"`
+	// Failures is the number of consecutive connection failures
+	// or unhealthy restarts after which the fallback VPN provider
+	// is used. It cannot be nil or zero in the internal state.
+	Failures *uint `json:"failures"`
+	// SwitchBack is the duration after which the primary VPN
+	// provider is used again once switched to the fallback VPN
+	// provider. It cannot be nil in the internal state.
+	SwitchBack *time.Duration `json:"switch_back"`
+	// Type is the fallback VPN type and can only be
+	// 'openvpn' or 'wireguard'. It cannot be the
+	// empty string in the internal state.
+	Type      string    `json:"type"`
+	Provider  Provider  `json:"provider"`
+	OpenVPN   OpenVPN   `json:"openvpn"`
+	Wireguard Wireguard `json:"wireguard"`
+}
+
+func (v *VPNFallback) validate(filterChoicesGetter FilterChoicesGetter,
+	ipv6Supported bool, warner Warner) (err error) {
+	if !*v.Enabled {
+		return nil
+	}
+
+	if *v.Failures == 0 {
+		return fmt.Errorf("%w", ErrFallbackFailuresZero)
+	}
+
+	const minSwitchBack = time.Minute
+	if *v.SwitchBack < minSwitchBack {
+		return fmt.Errorf("%w: %s must be at least %s",
+			ErrFallbackSwitchBackTooSmall, *v.SwitchBack, minSwitchBack)
+	}
+
+	err = validate.IsOneOf(v.Type, vpn.OpenVPN, vpn.Wireguard)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrVPNTypeNotValid, err)
+	}
+
+	err = v.Provider.validate(v.Type, filterChoicesGetter, warner)
+	if err != nil {
+		return fmt.Errorf("provider settings: %w", err)
+	}
+
+	if v.Type == vpn.OpenVPN {
+		err := v.OpenVPN.validate(v.Provider.Name)
+		if err != nil {
+			return fmt.Errorf("OpenVPN settings: %w", err)
+		}
+	} else {
+		err := v.Wireguard.validate(v.Provider.Name, ipv6Supported)
+		if err != nil {
+			return fmt.Errorf("Wireguard settings: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (v *VPNFallback) copy() (copied VPNFallback) {
+	return VPNFallback{
+		Enabled:    gosettings.CopyPointer(v.Enabled),
+		Failures:   gosettings.CopyPointer(v.Failures),
+		SwitchBack: gosettings.CopyPointer(v.SwitchBack),
+		Type:       v.Type,
+		Provider:   v.Provider.copy(),
+		OpenVPN:    v.OpenVPN.copy(),
+		Wireguard:  v.Wireguard.copy(),
+	}
+}
+
+func (v *VPNFallback) overrideWith(other VPNFallback) {
+	v.Enabled = gosettings.OverrideWithPointer(v.Enabled, other.Enabled)
+	v.Failures = gosettings.OverrideWithPointer(v.Failures, other.Failures)
+	v.SwitchBack = gosettings.OverrideWithPointer(v.SwitchBack, other.SwitchBack)
+	v.Type = gosettings.OverrideWithComparable(v.Type, other.Type)
+	v.Provider.overrideWith(other.Provider)
+	v.OpenVPN.overrideWith(other.OpenVPN)
+	v.Wireguard.overrideWith(other.Wireguard)
+}
+
+func (v *VPNFallback) setDefaults() {
+	v.Enabled = gosettings.DefaultPointer(v.Enabled, false)
+	const defaultFailures = 3
+	v.Failures = gosettings.DefaultPointer(v.Failures, defaultFailures)
+	const defaultSwitchBack = time.Hour
+	v.SwitchBack = gosettings.DefaultPointer(v.SwitchBack, defaultSwitchBack)
+	v.Type = gosettings.DefaultComparable(v.Type, vpn.OpenVPN)
+	v.Provider.setDefaults()
+	v.OpenVPN.setDefaults(v.Provider.Name)
+	v.Wireguard.setDefaults(v.Provider.Name)
+}
+
+// Apply returns the VPN settings given with the VPN type, provider,
+// OpenVPN and Wireguard settings replaced by the fallback ones.
+func (v VPNFallback) Apply(vpnSettings VPN) (fallback VPN) {
+	fallback = vpnSettings
+	fallback.Type = v.Type
+	fallback.Provider = v.Provider.copy()
+	fallback.OpenVPN = v.OpenVPN.copy()
+	fallback.Wireguard = v.Wireguard.copy()
+	return fallback
+}
+
+func (v VPNFallback) String() string {
+	return v.toLinesNode().String()
+}
+
+func (v VPNFallback) toLinesNode() (node *gotree.Node) {
+	if !*v.Enabled {
+		return nil
+	}
+
+	node = gotree.New("Fallback VPN settings:")
+	node.Appendf("Consecutive failures before switching: %d", *v.Failures)
+	node.Appendf("Switch back to primary after: %s", *v.SwitchBack)
+	node.Appendf("VPN type: %s", v.Type)
+	node.AppendNode(v.Provider.toLinesNode())
+	if v.Type == vpn.OpenVPN {
+		node.AppendNode(v.OpenVPN.toLinesNode())
+	} else {
+		node.AppendNode(v.Wireguard.toLinesNode())
+	}
+	return node
+}
+
+func (v *VPNFallback) read(r *reader.Reader) (err error) {
+	v.Enabled, err = r.BoolPtr("VPN_FALLBACK")
+	if err != nil {
+		return err
+	}
+
+	v.Failures, err = r.UintPtr("VPN_FALLBACK_FAILURES")
+	if err != nil {
+		return err
+	}
+
+	v.SwitchBack, err = r.DurationPtr("VPN_FALLBACK_SWITCH_BACK")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ReadVPN reads the fallback VPN type, provider, OpenVPN and
+// Wireguard settings from the reader given, which should look up
+// the same keys as for the primary VPN settings with a prefix,
+// such as FALLBACK_VPN_SERVICE_PROVIDER.
+func (v *VPNFallback) ReadVPN(r *reader.Reader) (err error) {
+	v.Type = r.String("VPN_TYPE")
+
+	err = v.Provider.read(r, v.Type)
+	if err != nil {
+		return fmt.Errorf("VPN provider: %w", err)
+	}
+
+	err = v.OpenVPN.read(r)
+	if err != nil {
+		return fmt.Errorf("OpenVPN: %w", err)
+	}
+
+	err = v.Wireguard.read(r)
+	if err != nil {
+		return fmt.Errorf("wireguard: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package settings
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/cron"
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gotree"
+)
+
+// VPNRotation contains settings to periodically reconnect
+// the VPN to another server from the filtered servers.
+type VPNRotation struct {
+	// Period is the duration after which the VPN connection
+	// is rotated to another server. It can be set to 0 to disable
+	// periodic rotation. It cannot be nil in the internal state.
+	Period *time.Duration `json:"period"`
+	// Cron is a cron expression such as "0 4 * * *" to rotate
+	// the VPN connection on a schedule. It can be set to the empty
+	// string to disable scheduled rotation, and cannot be set together
+	// with a non zero Period. It cannot be nil in the internal state.
+	Cron *string `json:"cron"`
+	// Window is a daily time window in the format "HH:MM-HH:MM"
+	// restricting when a due rotation can happen. A rotation due
+	// outside the window is delayed until the window starts.
+	// It can be the empty string to allow rotations at any time.
+	// It cannot be nil in the internal state.
+	Window *string `json:"window"`
+	// OnlyIdlePortForward is true if a due rotation should be delayed
+	// while connections are established on a forwarded port.
+	// It cannot be nil in the internal state.
+	OnlyIdlePortForward *bool `json:"only_idle_port_forward"`
+}
+
+func (v VPNRotation) validate() (err error) {
+	const minPeriod = time.Minute
+	if *v.Period > 0 && *v.Period < minPeriod {
+		return fmt.Errorf("%w: %s must be at least %s",
+			ErrRotationPeriodTooSmall, *v.Period, minPeriod)
+	}
+
+	if *v.Period > 0 && *v.Cron != "" {
+		return fmt.Errorf("%w", ErrRotationPeriodAndCronSet)
+	}
+
+	if *v.Cron != "" {
+		_, err = cron.Parse(*v.Cron)
+		if err != nil {
+			return fmt.Errorf("cron expression: %w", err)
+		}
+	}
+
+	if *v.Window != "" {
+		_, err = cron.ParseWindow(*v.Window)
+		if err != nil {
+			return fmt.Errorf("window: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Enabled returns true if either the rotation period
+// or the rotation cron expression is set.
+func (v VPNRotation) Enabled() bool {
+	return *v.Period > 0 || *v.Cron != ""
+}
+
+func (v *VPNRotation) copy() (copied VPNRotation) {
+	return VPNRotation{
+		Period:              gosettings.CopyPointer(v.Period),
+		Cron:                gosettings.CopyPointer(v.Cron),
+		Window:              gosettings.CopyPointer(v.Window),
+		OnlyIdlePortForward: gosettings.CopyPointer(v.OnlyIdlePortForward),
+	}
+}
+
+func (v *VPNRotation) overrideWith(other VPNRotation) {
+	v.Period = gosettings.OverrideWithPointer(v.Period, other.Period)
+	v.Cron = gosettings.OverrideWithPointer(v.Cron, other.Cron)
+	v.Window = gosettings.OverrideWithPointer(v.Window, other.Window)
+	v.OnlyIdlePortForward = gosettings.OverrideWithPointer(v.OnlyIdlePortForward, other.OnlyIdlePortForward)
+}
+
+func (v *VPNRotation) setDefaults() {
+	v.Period = gosettings.DefaultPointer(v.Period, 0)
+	v.Cron = gosettings.DefaultPointer(v.Cron, "")
+	v.Window = gosettings.DefaultPointer(v.Window, "")
+	v.OnlyIdlePortForward = gosettings.DefaultPointer(v.OnlyIdlePortForward, false)
+}
+
+func (v VPNRotation) String() string {
+	return v.toLinesNode().String()
+}
+
+func (v VPNRotation) toLinesNode() (node *gotree.Node) {
+	if !v.Enabled() {
+		return nil
+	}
+
+	node = gotree.New("Server rotation settings:")
+
+	if *v.Period > 0 {
+		node.Appendf("Period: %s", *v.Period)
+	} else {
+		node.Appendf("Cron expression: %s", *v.Cron)
+	}
+
+	if *v.Window != "" {
+		node.Appendf("Time window: %s", *v.Window)
+	}
+
+	node.Appendf("Only when forwarded ports are idle: %s",
+		gosettings.BoolToYesNo(v.OnlyIdlePortForward))
+
+	return node
+}
+
+func (v *VPNRotation) read(r *reader.Reader) (err error) {
+	v.Period, err = r.DurationPtr("VPN_ROTATION_PERIOD")
+	if err != nil {
+		return err
+	}
+
+	v.Cron = r.Get("VPN_ROTATION_CRON", reader.AcceptEmpty(true))
+	v.Window = r.Get("VPN_ROTATION_WINDOW", reader.AcceptEmpty(true))
+
+	v.OnlyIdlePortForward, err = r.BoolPtr("VPN_ROTATION_ONLY_IDLE_PORT_FORWARD")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
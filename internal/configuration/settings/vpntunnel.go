@@ -0,0 +1,245 @@
+package settings
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gotree"
+)
+
+// VPNTunnel contains settings for an additional Wireguard tunnel
+// running alongside the main VPN connection. Traffic only goes
+// through the tunnel if it is selected by one of its policies:
+// input ports, source subnets or HTTP proxy user.
+type VPNTunnel struct {
+	// Name is the name of the tunnel, used in the prefix of the keys
+	// to read the tunnel settings from, such as TUNNEL_<NAME>_.
+	// It cannot be the empty string in the internal state.
+	Name      string    `json:"name"`
+	Provider  Provider  `json:"provider"`
+	Wireguard Wireguard `json:"wireguard"`
+	// InputPorts are the ports to allow through the tunnel interface.
+	// Replies to connections on these ports go back through the tunnel.
+	// It cannot be nil in the internal state.
+	InputPorts []uint16 `json:"input_ports"`
+	// SourceSubnets are the subnets whose traffic is routed
+	// through the tunnel, such as the subnet of a Docker network.
+	// Their traffic is forwarded and masqueraded, which requires
+	// IP forwarding to be enabled in the container.
+	// It cannot be nil in the internal state.
+	SourceSubnets []netip.Prefix `json:"source_subnets"`
+	// HTTPProxyUser is the HTTP proxy username for which proxied traffic
+	// goes through the tunnel. It cannot be nil in the internal state,
+	// and the empty string disables this policy.
+	HTTPProxyUser *string `json:"http_proxy_user"`
+	// HTTPProxyPassword is the HTTP proxy password for HTTPProxyUser.
+	// It cannot be nil in the internal state.
+	HTTPProxyPassword *string `json:"http_proxy_password"`
+}
+
+var regexpTunnelName = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+
+func (t *VPNTunnel) validate(filterChoicesGetter FilterChoicesGetter,
+	ipv6Supported bool, warner Warner) (err error) {
+	if !regexpTunnelName.MatchString(t.Name) {
+		return fmt.Errorf("%w: %q does not match regular expression %q",
+			ErrVPNTunnelNameNotValid, t.Name, regexpTunnelName)
+	}
+
+	err = t.Provider.validate(vpn.Wireguard, filterChoicesGetter, warner)
+	if err != nil {
+		return fmt.Errorf("provider settings: %w", err)
+	}
+
+	err = t.Wireguard.validate(t.Provider.Name, ipv6Supported)
+	if err != nil {
+		return fmt.Errorf("Wireguard settings: %w", err)
+	}
+
+	if hasZeroPort(t.InputPorts) {
+		return fmt.Errorf("input ports: %w", ErrFirewallZeroPort)
+	}
+
+	if len(t.InputPorts) == 0 && len(t.SourceSubnets) == 0 && *t.HTTPProxyUser == "" {
+		warner.Warn("VPN tunnel " + t.Name + " has no input port, source subnet " +
+			"or HTTP proxy user, so no traffic goes through it")
+	}
+
+	return nil
+}
+
+// validateTunnels validates the tunnels settings given, and checks their
+// names, interfaces and HTTP proxy users are unique. The interfaces must
+// also differ from the main VPN interfaces given.
+func validateTunnels(tunnels []VPNTunnel, mainInterfaces []string,
+	filterChoicesGetter FilterChoicesGetter, ipv6Supported bool,
+	warner Warner) (err error) {
+	names := make(map[string]struct{}, len(tunnels))
+	interfaces := make(map[string]struct{}, len(tunnels)+len(mainInterfaces))
+	for _, mainInterface := range mainInterfaces {
+		interfaces[mainInterface] = struct{}{}
+	}
+	httpProxyUsers := make(map[string]struct{}, len(tunnels))
+
+	for _, tunnel := range tunnels {
+		err = tunnel.validate(filterChoicesGetter, ipv6Supported, warner)
+		if err != nil {
+			return fmt.Errorf("tunnel %s: %w", tunnel.Name, err)
+		}
+
+		name := strings.ToLower(tunnel.Name)
+		if _, exists := names[name]; exists {
+			return fmt.Errorf("%w: %s", ErrVPNTunnelNameDuplicate, tunnel.Name)
+		}
+		names[name] = struct{}{}
+
+		if _, exists := interfaces[tunnel.Wireguard.Interface]; exists {
+			return fmt.Errorf("tunnel %s: %w: %s", tunnel.Name,
+				ErrVPNTunnelInterfaceDuplicate, tunnel.Wireguard.Interface)
+		}
+		interfaces[tunnel.Wireguard.Interface] = struct{}{}
+
+		if *tunnel.HTTPProxyUser == "" {
+			continue
+		}
+		if _, exists := httpProxyUsers[*tunnel.HTTPProxyUser]; exists {
+			return fmt.Errorf("tunnel %s: %w: %s", tunnel.Name,
+				ErrVPNTunnelHTTPProxyUserDuplicate, *tunnel.HTTPProxyUser)
+		}
+		httpProxyUsers[*tunnel.HTTPProxyUser] = struct{}{}
+	}
+
+	return nil
+}
+
+// validateTunnelsHTTPProxyUser checks none of the tunnels HTTP proxy
+// users is the main HTTP proxy user, since proxied traffic of this user
+// must go through the main VPN tunnel.
+func validateTunnelsHTTPProxyUser(tunnels []VPNTunnel, httpProxyUser string) (err error) {
+	if httpProxyUser == "" {
+		return nil
+	}
+	for _, tunnel := range tunnels {
+		if *tunnel.HTTPProxyUser == httpProxyUser {
+			return fmt.Errorf("tunnel %s: %w: %s", tunnel.Name,
+				ErrVPNTunnelHTTPProxyUserMain, httpProxyUser)
+		}
+	}
+	return nil
+}
+
+func (t *VPNTunnel) copy() (copied VPNTunnel) {
+	return VPNTunnel{
+		Name:              t.Name,
+		Provider:          t.Provider.copy(),
+		Wireguard:         t.Wireguard.copy(),
+		InputPorts:        gosettings.CopySlice(t.InputPorts),
+		SourceSubnets:     gosettings.CopySlice(t.SourceSubnets),
+		HTTPProxyUser:     gosettings.CopyPointer(t.HTTPProxyUser),
+		HTTPProxyPassword: gosettings.CopyPointer(t.HTTPProxyPassword),
+	}
+}
+
+func copyTunnels(tunnels []VPNTunnel) (copied []VPNTunnel) {
+	if tunnels == nil {
+		return nil
+	}
+	copied = make([]VPNTunnel, len(tunnels))
+	for i := range tunnels {
+		copied[i] = tunnels[i].copy()
+	}
+	return copied
+}
+
+// setDefaults sets the defaults for the tunnel at the index given
+// in the tunnels list, where its interface defaults to wg<index+1>.
+func (t *VPNTunnel) setDefaults(index int) {
+	t.Provider.setDefaults()
+	t.Wireguard.Interface = gosettings.DefaultComparable(t.Wireguard.Interface,
+		fmt.Sprintf("wg%d", index+1))
+	t.Wireguard.setDefaults(t.Provider.Name)
+	t.InputPorts = gosettings.DefaultSlice(t.InputPorts, []uint16{})
+	t.SourceSubnets = gosettings.DefaultSlice(t.SourceSubnets, []netip.Prefix{})
+	t.HTTPProxyUser = gosettings.DefaultPointer(t.HTTPProxyUser, "")
+	t.HTTPProxyPassword = gosettings.DefaultPointer(t.HTTPProxyPassword, "")
+}
+
+// ReaderPrefix returns the prefix to use to read the settings of the tunnel,
+// for example TUNNEL_GERMANY_ for a tunnel named germany.
+func (t VPNTunnel) ReaderPrefix() string {
+	return "TUNNEL_" + strings.ToUpper(t.Name) + "_"
+}
+
+func (t VPNTunnel) toLinesNode() (node *gotree.Node) {
+	node = gotree.New("Tunnel %s:", t.Name)
+	node.AppendNode(t.Provider.toLinesNode())
+	node.AppendNode(t.Wireguard.toLinesNode())
+
+	if len(t.InputPorts) > 0 {
+		inputPortsNode := node.Appendf("Input ports:")
+		for _, port := range t.InputPorts {
+			inputPortsNode.Appendf("%d", port)
+		}
+	}
+
+	if len(t.SourceSubnets) > 0 {
+		sourceSubnetsNode := node.Appendf("Source subnets:")
+		for _, subnet := range t.SourceSubnets {
+			sourceSubnetsNode.Appendf("%s", subnet)
+		}
+	}
+
+	if *t.HTTPProxyUser != "" {
+		node.Appendf("HTTP proxy user: %s", *t.HTTPProxyUser)
+		node.Appendf("HTTP proxy password: %s", gosettings.ObfuscateKey(*t.HTTPProxyPassword))
+	}
+
+	return node
+}
+
+func readTunnelNames(r *reader.Reader) (tunnels []VPNTunnel) {
+	names := r.CSV("VPN_TUNNELS", reader.ForceLowercase(false))
+	if len(names) == 0 {
+		return nil
+	}
+	tunnels = make([]VPNTunnel, len(names))
+	for i, name := range names {
+		tunnels[i].Name = strings.TrimSpace(name)
+	}
+	return tunnels
+}
+
+// ReadVPN reads the tunnel provider, Wireguard and policies settings
+// from the reader given, which should look up the keys with the prefix
+// returned by ReaderPrefix, such as TUNNEL_GERMANY_SERVER_COUNTRIES.
+func (t *VPNTunnel) ReadVPN(r *reader.Reader) (err error) {
+	err = t.Provider.read(r, vpn.Wireguard)
+	if err != nil {
+		return fmt.Errorf("VPN provider: %w", err)
+	}
+
+	err = t.Wireguard.read(r)
+	if err != nil {
+		return fmt.Errorf("wireguard: %w", err)
+	}
+
+	t.InputPorts, err = r.CSVUint16("INPUT_PORTS")
+	if err != nil {
+		return err
+	}
+
+	t.SourceSubnets, err = r.CSVNetipPrefixes("SOURCE_SUBNETS")
+	if err != nil {
+		return err
+	}
+
+	t.HTTPProxyUser = r.Get("HTTPPROXY_USER", reader.ForceLowercase(false))
+	t.HTTPProxyPassword = r.Get("HTTPPROXY_PASSWORD", reader.ForceLowercase(false))
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_validateTunnelsHTTPProxyUser(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		tunnels       []VPNTunnel
+		httpProxyUser string
+		errWrapped    error
+		errMessage    string
+	}{
+		"no_main_user": {
+			tunnels: []VPNTunnel{
+				{Name: "a", HTTPProxyUser: ptrTo("")},
+			},
+		},
+		"different_users": {
+			tunnels: []VPNTunnel{
+				{Name: "a", HTTPProxyUser: ptrTo("")},
+				{Name: "b", HTTPProxyUser: ptrTo("userb")},
+			},
+			httpProxyUser: "user",
+		},
+		"main_user": {
+			tunnels: []VPNTunnel{
+				{Name: "a", HTTPProxyUser: ptrTo("usera")},
+				{Name: "b", HTTPProxyUser: ptrTo("user")},
+			},
+			httpProxyUser: "user",
+			errWrapped:    ErrVPNTunnelHTTPProxyUserMain,
+			errMessage: "tunnel b: VPN tunnel HTTP proxy user is " +
+				"the main HTTP proxy user: user",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateTunnelsHTTPProxyUser(testCase.tunnels, testCase.httpProxyUser)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
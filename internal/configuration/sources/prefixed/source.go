@@ -0,0 +1,41 @@
+// Package prefixed wraps a settings source such that
+// each key is looked up with a prefix added to it.
+package prefixed
+
+import (
+	"github.com/qdm12/gosettings/reader"
+)
+
+type Source struct {
+	prefix string
+	source reader.Source
+}
+
+// New returns a source looking up each key in the source given
+// with the prefix given, for example FALLBACK_VPN_TYPE for VPN_TYPE
+// with the prefix FALLBACK_.
+func New(prefix string, source reader.Source) *Source {
+	return &Source{
+		prefix: prefix,
+		source: source,
+	}
+}
+
+// Wrap returns each of the sources given wrapped with the prefix given.
+func Wrap(prefix string, sources []reader.Source) (wrapped []reader.Source) {
+	wrapped = make([]reader.Source, len(sources))
+	for i, source := range sources {
+		wrapped[i] = New(prefix, source)
+	}
+	return wrapped
+}
+
+func (s *Source) String() string { return s.source.String() }
+
+func (s *Source) Get(key string) (value string, isSet bool) {
+	return s.source.Get(key)
+}
+
+func (s *Source) KeyTransform(key string) string {
+	return s.source.KeyTransform(s.prefix + key)
+}
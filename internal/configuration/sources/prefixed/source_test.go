@@ -0,0 +1,26 @@
+package prefixed
+
+import (
+	"testing"
+
+	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gosettings/reader/sources/env"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Source(t *testing.T) {
+	t.Parallel()
+
+	source := env.New(env.Settings{
+		Environ: []string{
+			"VPN_TYPE=openvpn",
+			"FALLBACK_VPN_TYPE=wireguard",
+		},
+	})
+	r := reader.New(reader.Settings{
+		Sources: Wrap("FALLBACK_", []reader.Source{source}),
+	})
+
+	assert.Equal(t, "wireguard", r.String("VPN_TYPE"))
+	assert.Empty(t, r.String("VPN_SERVICE_PROVIDER"))
+}
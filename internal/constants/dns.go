@@ -0,0 +1,8 @@
+package constants
+
+const (
+	// DNSOverTLS is the DNS over TLS upstream protocol, on port 853.
+	DNSOverTLS = "dot"
+	// DNSOverHTTPS is the DNS over HTTPS upstream protocol, on port 443.
+	DNSOverHTTPS = "doh"
+)
@@ -0,0 +1,30 @@
+package constants
+
+const (
+	// HealthProbeTCP dials a TCP connection to a host and port.
+	HealthProbeTCP string = "tcp"
+	// HealthProbeHTTP sends an HTTP(S) GET request to a URL and
+	// checks the response status code and body.
+	HealthProbeHTTP string = "http"
+	// HealthProbeDNS resolves a hostname through the internal DNS server.
+	HealthProbeDNS string = "dns"
+	// HealthProbeICMP sends an ICMP echo request to an IP address
+	// through the VPN interface.
+	HealthProbeICMP string = "icmp"
+)
+
+const (
+	// EscalationNone is the escalation step when the VPN is healthy.
+	EscalationNone string = "none"
+	// EscalationSameServer restarts the VPN on the same server.
+	EscalationSameServer string = "same_server"
+	// EscalationSwitchServer restarts the VPN on another server
+	// matching the server selection.
+	EscalationSwitchServer string = "switch_server"
+	// EscalationSwitchLocation restarts the VPN on a server
+	// from the next fallback location.
+	EscalationSwitchLocation string = "switch_location"
+	// EscalationTerminal is the last escalation step, where the
+	// VPN is no longer restarted since all other steps failed.
+	EscalationTerminal string = "terminal"
+)
@@ -3,4 +3,7 @@ package constants
 const (
 	// ServersData is the server information filepath.
 	ServersData = "/gluetun/servers.json"
+	// LastServer is the filepath of the last VPN server
+	// which reached a healthy state.
+	LastServer = "/gluetun/lastserver.json"
 )
@@ -0,0 +1,13 @@
+package constants
+
+const (
+	// SelectionRandom picks a random connection
+	// from the filtered servers connections.
+	SelectionRandom string = "random"
+	// SelectionLatency picks the connection with the lowest
+	// latency amongst randomly sampled connections.
+	SelectionLatency string = "latency"
+	// SelectionLeastLoaded picks a random connection amongst
+	// the connections of the servers with the lowest load.
+	SelectionLeastLoaded string = "least_loaded"
+)
@@ -0,0 +1,215 @@
+// Package cron parses standard cron expressions and computes
+// their next activation times.
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression.
+type Schedule struct {
+	minutes     uint64
+	hours       uint64
+	daysOfMonth uint64
+	months      uint64
+	daysOfWeek  uint64
+	// daysRestricted is true if both the days of month and
+	// days of week fields are restricted, in which case a day
+	// matches if it matches either of the two fields.
+	daysRestricted bool
+}
+
+var (
+	ErrFieldsCount     = errors.New("expression must have 5 fields")
+	ErrMacroUnknown    = errors.New("macro is unknown")
+	ErrValueNotValid   = errors.New("value is not valid")
+	ErrValueOutOfRange = errors.New("value is out of range")
+	ErrStepNotValid    = errors.New("step is not valid")
+)
+
+type fieldBounds struct {
+	name     string
+	min, max uint
+}
+
+//nolint:gochecknoglobals,gomnd
+var (
+	minuteBounds     = fieldBounds{name: "minute", min: 0, max: 59}
+	hourBounds       = fieldBounds{name: "hour", min: 0, max: 23}
+	dayOfMonthBounds = fieldBounds{name: "day of month", min: 1, max: 31}
+	monthBounds      = fieldBounds{name: "month", min: 1, max: 12}
+	// Sunday can be 0 or 7.
+	dayOfWeekBounds = fieldBounds{name: "day of week", min: 0, max: 7}
+)
+
+// Parse parses a cron expression made of 5 space separated fields:
+// minute, hour, day of month, month and day of week. Each field can
+// be '*', a value, a range 'a-b', a step '*/n' or 'a-b/n', or a comma
+// separated list of these. The macros @yearly, @monthly, @weekly,
+// @daily and @hourly are also supported.
+func Parse(expression string) (schedule Schedule, err error) {
+	expression = strings.TrimSpace(expression)
+	if strings.HasPrefix(expression, "@") {
+		macroExpression, ok := macros()[expression]
+		if !ok {
+			return schedule, fmt.Errorf("%w: %s", ErrMacroUnknown, expression)
+		}
+		expression = macroExpression
+	}
+
+	fields := strings.Fields(expression)
+	const expectedFields = 5
+	if len(fields) != expectedFields {
+		return schedule, fmt.Errorf("%w: %q has %d fields",
+			ErrFieldsCount, expression, len(fields))
+	}
+
+	allBounds := [...]fieldBounds{minuteBounds, hourBounds,
+		dayOfMonthBounds, monthBounds, dayOfWeekBounds}
+	bitsets := [...]*uint64{&schedule.minutes, &schedule.hours,
+		&schedule.daysOfMonth, &schedule.months, &schedule.daysOfWeek}
+	for i, field := range fields {
+		*bitsets[i], err = parseField(field, allBounds[i])
+		if err != nil {
+			return schedule, fmt.Errorf("%s field: %w", allBounds[i].name, err)
+		}
+	}
+
+	const sunday, altSunday = 0, 7
+	if schedule.daysOfWeek&(1<<altSunday) != 0 {
+		schedule.daysOfWeek |= 1 << sunday
+	}
+
+	schedule.daysRestricted = fields[2] != "*" && fields[4] != "*"
+	return schedule, nil
+}
+
+func macros() map[string]string {
+	return map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+}
+
+func parseField(field string, bounds fieldBounds) (bitset uint64, err error) {
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := uint(1)
+		if hasStep {
+			step, err = parseValue(stepPart)
+			if err != nil || step == 0 {
+				return 0, fmt.Errorf("%w: %q", ErrStepNotValid, stepPart)
+			}
+		}
+
+		var start, end uint
+		switch {
+		case rangePart == "*":
+			start, end = bounds.min, bounds.max
+		case strings.Contains(rangePart, "-"):
+			startPart, endPart, _ := strings.Cut(rangePart, "-")
+			start, err = parseBoundedValue(startPart, bounds)
+			if err != nil {
+				return 0, err
+			}
+			end, err = parseBoundedValue(endPart, bounds)
+			if err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("%w: range %q start is after its end",
+					ErrValueNotValid, rangePart)
+			}
+		default:
+			start, err = parseBoundedValue(rangePart, bounds)
+			if err != nil {
+				return 0, err
+			}
+			end = start
+			if hasStep {
+				end = bounds.max
+			}
+		}
+
+		for value := start; value <= end; value += step {
+			bitset |= 1 << value
+		}
+	}
+	return bitset, nil
+}
+
+func parseValue(s string) (value uint, err error) {
+	const base, bitSize = 10, 8
+	parsed, err := strconv.ParseUint(s, base, bitSize)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrValueNotValid, s)
+	}
+	return uint(parsed), nil
+}
+
+func parseBoundedValue(s string, bounds fieldBounds) (value uint, err error) {
+	value, err = parseValue(s)
+	if err != nil {
+		return 0, err
+	}
+	if value < bounds.min || value > bounds.max {
+		return 0, fmt.Errorf("%w: %d must be between %d and %d",
+			ErrValueOutOfRange, value, bounds.min, bounds.max)
+	}
+	return value, nil
+}
+
+// Next returns the first activation time of the schedule strictly
+// after the time given, in the location of the time given. It returns
+// the zero time if no activation time is found within 5 years, which
+// can happen for expressions such as "0 0 31 2 *".
+func (s Schedule) Next(t time.Time) (next time.Time) {
+	const maxYears = 5
+	limit := t.AddDate(maxYears, 0, 0)
+	next = t.Truncate(time.Minute).Add(time.Minute)
+	location := t.Location()
+
+	for next.Before(limit) {
+		if s.months&(1<<uint(next.Month())) == 0 {
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, location)
+			continue
+		}
+
+		if !s.matchesDay(next) {
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, location)
+			continue
+		}
+
+		if s.hours&(1<<uint(next.Hour())) == 0 {
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, location)
+			continue
+		}
+
+		if s.minutes&(1<<uint(next.Minute())) == 0 {
+			next = next.Add(time.Minute)
+			continue
+		}
+
+		return next
+	}
+	return time.Time{}
+}
+
+func (s Schedule) matchesDay(t time.Time) bool {
+	dayOfMonthMatch := s.daysOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeekMatch := s.daysOfWeek&(1<<uint(t.Weekday())) != 0
+	if s.daysRestricted {
+		return dayOfMonthMatch || dayOfWeekMatch
+	}
+	return dayOfMonthMatch && dayOfWeekMatch
+}
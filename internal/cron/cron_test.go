@@ -0,0 +1,154 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Parse(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		expression string
+		errWrapped error
+		errMessage string
+	}{
+		"empty": {
+			errWrapped: ErrFieldsCount,
+			errMessage: `expression must have 5 fields: "" has 0 fields`,
+		},
+		"unknown_macro": {
+			expression: "@often",
+			errWrapped: ErrMacroUnknown,
+			errMessage: "macro is unknown: @often",
+		},
+		"value_not_valid": {
+			expression: "x * * * *",
+			errWrapped: ErrValueNotValid,
+			errMessage: `minute field: value is not valid: "x"`,
+		},
+		"value_out_of_range": {
+			expression: "0 24 * * *",
+			errWrapped: ErrValueOutOfRange,
+			errMessage: "hour field: value is out of range: 24 must be between 0 and 23",
+		},
+		"reversed_range": {
+			expression: "0 0 * 5-2 *",
+			errWrapped: ErrValueNotValid,
+			errMessage: `month field: value is not valid: range "5-2" start is after its end`,
+		},
+		"zero_step": {
+			expression: "*/0 * * * *",
+			errWrapped: ErrStepNotValid,
+			errMessage: `minute field: step is not valid: "0"`,
+		},
+		"valid": {
+			expression: "0,30 */6 1-15 * 1-5",
+		},
+		"valid_macro": {
+			expression: "@daily",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse(testCase.expression)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_Schedule_Next(t *testing.T) {
+	t.Parallel()
+
+	// 2024-01-01 is a Monday
+	parseTime := func(s string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, s)
+		require.NoError(t, err)
+		return parsed
+	}
+
+	testCases := map[string]struct {
+		expression string
+		from       string
+		next       string
+	}{
+		"every_minute": {
+			expression: "* * * * *",
+			from:       "2024-01-01T10:00:30Z",
+			next:       "2024-01-01T10:01:00Z",
+		},
+		"strictly_after": {
+			expression: "0 * * * *",
+			from:       "2024-01-01T10:00:00Z",
+			next:       "2024-01-01T11:00:00Z",
+		},
+		"every_6_hours": {
+			expression: "0 */6 * * *",
+			from:       "2024-01-01T13:15:00Z",
+			next:       "2024-01-01T18:00:00Z",
+		},
+		"list_and_range": {
+			expression: "15,45 2-3 * * *",
+			from:       "2024-01-01T03:50:00Z",
+			next:       "2024-01-02T02:15:00Z",
+		},
+		"day_of_week": {
+			expression: "30 4 * * 6",
+			from:       "2024-01-01T00:00:00Z",
+			next:       "2024-01-06T04:30:00Z",
+		},
+		"sunday_as_7": {
+			expression: "0 0 * * 7",
+			from:       "2024-01-01T00:00:00Z",
+			next:       "2024-01-07T00:00:00Z",
+		},
+		"day_of_month_or_day_of_week": {
+			expression: "0 0 15 * 5",
+			from:       "2024-01-01T00:00:00Z",
+			next:       "2024-01-05T00:00:00Z",
+		},
+		"month_rollover": {
+			expression: "@monthly",
+			from:       "2024-12-15T00:00:00Z",
+			next:       "2025-01-01T00:00:00Z",
+		},
+		"leap_day": {
+			expression: "0 0 29 2 *",
+			from:       "2024-03-01T00:00:00Z",
+			next:       "2028-02-29T00:00:00Z",
+		},
+		"never": {
+			expression: "0 0 31 2 *",
+			from:       "2024-01-01T00:00:00Z",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			schedule, err := Parse(testCase.expression)
+			require.NoError(t, err)
+
+			next := schedule.Next(parseTime(testCase.from))
+
+			var expected time.Time
+			if testCase.next != "" {
+				expected = parseTime(testCase.next)
+			}
+			assert.Equal(t, expected, next)
+		})
+	}
+}
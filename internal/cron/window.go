@@ -0,0 +1,66 @@
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Window is a daily time window, which can wrap around midnight.
+type Window struct {
+	start time.Duration
+	end   time.Duration
+}
+
+var ErrWindowNotValid = errors.New("time window is not valid")
+
+// ParseWindow parses a daily time window in the format "HH:MM-HH:MM",
+// for example "02:00-05:30" or "23:00-01:00" which wraps around midnight.
+func ParseWindow(s string) (window Window, err error) {
+	startString, endString, ok := strings.Cut(s, "-")
+	if !ok {
+		return window, fmt.Errorf("%w: %q must be in the format HH:MM-HH:MM",
+			ErrWindowNotValid, s)
+	}
+
+	start, err := parseTimeOfDay(startString)
+	if err != nil {
+		return window, fmt.Errorf("%w: start: %w", ErrWindowNotValid, err)
+	}
+
+	end, err := parseTimeOfDay(endString)
+	if err != nil {
+		return window, fmt.Errorf("%w: end: %w", ErrWindowNotValid, err)
+	}
+
+	if start == end {
+		return window, fmt.Errorf("%w: %q start and end are equal",
+			ErrWindowNotValid, s)
+	}
+
+	return Window{start: start, end: end}, nil
+}
+
+func parseTimeOfDay(s string) (timeOfDay time.Duration, err error) {
+	parsed, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(parsed.Hour())*time.Hour +
+		time.Duration(parsed.Minute())*time.Minute, nil
+}
+
+// Contains returns true if the time of day of the time
+// given is within the window, with the start of the window
+// included and the end of the window excluded.
+func (w Window) Contains(t time.Time) bool {
+	timeOfDay := time.Duration(t.Hour())*time.Hour +
+		time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second
+	if w.start < w.end {
+		return timeOfDay >= w.start && timeOfDay < w.end
+	}
+	// window wraps around midnight
+	return timeOfDay >= w.start || timeOfDay < w.end
+}
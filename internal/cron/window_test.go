@@ -0,0 +1,106 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseWindow(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		s          string
+		window     Window
+		errWrapped error
+		errMessage string
+	}{
+		"missing_dash": {
+			s:          "02:00",
+			errWrapped: ErrWindowNotValid,
+			errMessage: `time window is not valid: "02:00" must be in the format HH:MM-HH:MM`,
+		},
+		"bad_start": {
+			s:          "25:00-03:00",
+			errWrapped: ErrWindowNotValid,
+			errMessage: `time window is not valid: start: parsing time "25:00": hour out of range`,
+		},
+		"equal_bounds": {
+			s:          "03:00-03:00",
+			errWrapped: ErrWindowNotValid,
+			errMessage: `time window is not valid: "03:00-03:00" start and end are equal`,
+		},
+		"valid": {
+			s:      "02:00-05:30",
+			window: Window{start: 2 * time.Hour, end: 5*time.Hour + 30*time.Minute},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			window, err := ParseWindow(testCase.s)
+
+			assert.Equal(t, testCase.window, window)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_Window_Contains(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		window   string
+		time     time.Time
+		contains bool
+	}{
+		"inside": {
+			window:   "02:00-05:00",
+			time:     time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC),
+			contains: true,
+		},
+		"start_included": {
+			window:   "02:00-05:00",
+			time:     time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC),
+			contains: true,
+		},
+		"end_excluded": {
+			window: "02:00-05:00",
+			time:   time.Date(2024, 1, 1, 5, 0, 0, 0, time.UTC),
+		},
+		"wrapping_before_midnight": {
+			window:   "23:00-01:00",
+			time:     time.Date(2024, 1, 1, 23, 30, 0, 0, time.UTC),
+			contains: true,
+		},
+		"wrapping_after_midnight": {
+			window:   "23:00-01:00",
+			time:     time.Date(2024, 1, 1, 0, 30, 0, 0, time.UTC),
+			contains: true,
+		},
+		"wrapping_outside": {
+			window: "23:00-01:00",
+			time:   time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			window, err := ParseWindow(testCase.window)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.contains, window.Contains(testCase.time))
+		})
+	}
+}
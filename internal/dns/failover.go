@@ -0,0 +1,195 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/miekg/dns"
+	"github.com/qdm12/dns/v2/pkg/doh"
+	"github.com/qdm12/dns/v2/pkg/dot"
+	"github.com/qdm12/gluetun/internal/constants"
+)
+
+// failoverServer is a DNS server forwarding queries to upstream
+// groups using different protocols, failing over to the next
+// group if the current group fails to answer.
+type failoverServer struct {
+	handler     dns.Handler
+	middlewares []dot.Middleware
+	logger      Logger
+	cancel      context.CancelFunc
+
+	// Fields set in the Start method call.
+	dnsServer *dns.Server
+	stop      chan struct{}
+	done      sync.WaitGroup
+}
+
+func newFailoverServer(groups []upstreamGroup, ipVersion string,
+	middlewares []dot.Middleware, logger Logger) (server *failoverServer, err error) {
+	upstreams := make([]failoverUpstream, len(groups))
+	for i, group := range groups {
+		var resolver *net.Resolver
+		var name string
+		switch group.protocol {
+		case constants.DNSOverTLS:
+			name = "DNS over TLS"
+			resolver, err = dot.NewResolver(dot.ResolverSettings{
+				UpstreamResolvers: group.providers,
+				IPVersion:         ipVersion,
+				Warner:            logger,
+			})
+		case constants.DNSOverHTTPS:
+			name = "DNS over HTTPS"
+			resolver, err = doh.NewResolver(doh.ResolverSettings{
+				UpstreamResolvers: group.providers,
+				IPVersion:         ipVersion,
+			})
+		default:
+			panic("DNS upstream protocol not supported: " + group.protocol)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("creating %s resolver: %w", name, err)
+		}
+		upstreams[i] = failoverUpstream{
+			name:     name,
+			exchange: newExchange(name, resolver.Dial, logger),
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var handler dns.Handler = newFailoverHandler(ctx, upstreams, logger)
+	for _, middleware := range middlewares {
+		handler = middleware.Wrap(handler)
+	}
+
+	return &failoverServer{
+		handler:     handler,
+		middlewares: middlewares,
+		logger:      logger,
+		cancel:      cancel,
+	}, nil
+}
+
+func (s *failoverServer) Start() (runError <-chan error, startErr error) {
+	udpListener, err := net.ListenPacket("udp", ":53")
+	if err != nil {
+		return nil, fmt.Errorf("creating UDP listener: %w", err)
+	}
+
+	s.dnsServer = &dns.Server{
+		PacketConn: udpListener,
+		Handler:    s.handler,
+	}
+	s.stop = make(chan struct{})
+
+	runErrorCh := make(chan error)
+	s.done.Add(1)
+	go func() {
+		defer s.done.Done()
+		s.logger.Info("DNS server listening on " + udpListener.LocalAddr().String())
+		err := s.dnsServer.ActivateAndServe()
+		select {
+		case <-s.stop: // discard error
+		case runErrorCh <- err:
+			close(runErrorCh)
+		}
+	}()
+
+	return runErrorCh, nil
+}
+
+func (s *failoverServer) Stop() (err error) {
+	close(s.stop)
+	s.cancel()
+	err = s.dnsServer.Shutdown()
+
+	for _, middleware := range s.middlewares {
+		middlewareErr := middleware.Stop()
+		if middlewareErr != nil {
+			s.logger.Warn(fmt.Sprintf("stopping middleware %s: %s",
+				middleware, middlewareErr))
+		}
+	}
+
+	s.done.Wait()
+	return err
+}
+
+type exchangeFunc func(ctx context.Context, request *dns.Msg) (
+	response *dns.Msg, err error)
+
+func newExchange(name string, dial func(ctx context.Context, network, address string) (net.Conn, error),
+	logger Logger) exchangeFunc {
+	client := &dns.Client{}
+	return func(ctx context.Context, request *dns.Msg) (response *dns.Msg, err error) {
+		netConn, err := dial(ctx, "", "")
+		if err != nil {
+			return nil, fmt.Errorf("dialing %s server: %w", name, err)
+		}
+		dnsConn := &dns.Conn{Conn: netConn}
+
+		response, _, err = client.ExchangeWithConnContext(ctx, request, dnsConn)
+
+		if closeErr := dnsConn.Close(); closeErr != nil {
+			logger.Warn("cannot close " + name + " connection: " + closeErr.Error())
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("exchanging over %s connection: %w", name, err)
+		}
+		return response, nil
+	}
+}
+
+type failoverUpstream struct {
+	name     string
+	exchange exchangeFunc
+}
+
+// failoverHandler forwards each query to the current upstream, and
+// tries the next upstreams in order if it fails. The first upstream
+// answering becomes the current upstream, so a blocked protocol
+// does not slow down every query.
+type failoverHandler struct {
+	ctx       context.Context //nolint:containedctx
+	upstreams []failoverUpstream
+	current   atomic.Int32
+	logger    Logger
+}
+
+func newFailoverHandler(ctx context.Context, upstreams []failoverUpstream,
+	logger Logger) *failoverHandler {
+	return &failoverHandler{
+		ctx:       ctx,
+		upstreams: upstreams,
+		logger:    logger,
+	}
+}
+
+func (h *failoverHandler) ServeDNS(w dns.ResponseWriter, request *dns.Msg) {
+	current := int(h.current.Load())
+	for i := range h.upstreams {
+		index := (current + i) % len(h.upstreams)
+		upstream := h.upstreams[index]
+		response, err := upstream.exchange(h.ctx, request)
+		if err != nil {
+			h.logger.Warn(err.Error())
+			continue
+		}
+
+		if index != current &&
+			h.current.CompareAndSwap(int32(current), int32(index)) { //nolint:gosec
+			h.logger.Info("failed over to " + upstream.name + " upstream")
+		}
+
+		response.SetReply(request)
+		_ = w.WriteMsg(response)
+		return
+	}
+
+	_ = w.WriteMsg(new(dns.Msg).SetRcode(request, dns.RcodeServerFailure))
+}
@@ -0,0 +1,83 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testResponseWriter struct {
+	dns.ResponseWriter
+	written *dns.Msg
+}
+
+func (w *testResponseWriter) WriteMsg(m *dns.Msg) error {
+	w.written = m
+	return nil
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Debug(string) {}
+func (noopLogger) Info(string)  {}
+func (noopLogger) Warn(string)  {}
+func (noopLogger) Error(string) {}
+
+func Test_failoverHandler_ServeDNS(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+	answer := &dns.A{
+		Hdr: dns.RR_Header{Name: "github.com.", Rrtype: dns.TypeA, Class: dns.ClassINET},
+		A:   net.IPv4(1, 2, 3, 4),
+	}
+	calls := map[string]int{}
+	newUpstream := func(name string, fail bool) failoverUpstream {
+		return failoverUpstream{
+			name: name,
+			exchange: func(context.Context, *dns.Msg) (*dns.Msg, error) {
+				calls[name]++
+				if fail {
+					return nil, errTest
+				}
+				response := new(dns.Msg)
+				response.Answer = []dns.RR{answer}
+				return response, nil
+			},
+		}
+	}
+
+	handler := newFailoverHandler(context.Background(), []failoverUpstream{
+		newUpstream("DNS over TLS", true),
+		newUpstream("DNS over HTTPS", false),
+	}, noopLogger{})
+
+	request := new(dns.Msg).SetQuestion("github.com.", dns.TypeA)
+
+	writer := &testResponseWriter{}
+	handler.ServeDNS(writer, request)
+	require.NotNil(t, writer.written)
+	assert.Equal(t, dns.RcodeSuccess, writer.written.Rcode)
+	assert.Equal(t, []dns.RR{answer}, writer.written.Answer)
+	assert.Equal(t, map[string]int{"DNS over TLS": 1, "DNS over HTTPS": 1}, calls)
+
+	// The DNS over HTTPS upstream is now the current upstream.
+	writer = &testResponseWriter{}
+	handler.ServeDNS(writer, request)
+	require.NotNil(t, writer.written)
+	assert.Equal(t, dns.RcodeSuccess, writer.written.Rcode)
+	assert.Equal(t, map[string]int{"DNS over TLS": 1, "DNS over HTTPS": 2}, calls)
+
+	handler = newFailoverHandler(context.Background(), []failoverUpstream{
+		newUpstream("failing", true),
+	}, noopLogger{})
+	writer = &testResponseWriter{}
+	handler.ServeDNS(writer, request)
+	require.NotNil(t, writer.written)
+	assert.Equal(t, dns.RcodeServerFailure, writer.written.Rcode)
+}
@@ -0,0 +1,21 @@
+package dns
+
+import (
+	"context"
+	"net/netip"
+	"time"
+)
+
+type EventPublisher interface {
+	Publish(eventType string, data any)
+}
+
+type SplitTunnel interface {
+	Match(name string) (ok bool)
+	Add(ctx context.Context, addresses []netip.Addr, ttl time.Duration) (err error)
+}
+
+type Server interface {
+	Start() (runError <-chan error, startErr error)
+	Stop() (err error)
+}
@@ -6,11 +6,11 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/qdm12/dns/v2/pkg/dot"
 	"github.com/qdm12/dns/v2/pkg/middlewares/filter/mapfilter"
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/dns/state"
+	"github.com/qdm12/gluetun/internal/events"
 	"github.com/qdm12/gluetun/internal/loopstate"
 	"github.com/qdm12/gluetun/internal/models"
 )
@@ -18,8 +18,9 @@ import (
 type Loop struct {
 	statusManager *loopstate.State
 	state         *state.State
-	server        *dot.Server
+	server        Server
 	filter        *mapfilter.Filter
+	splitTunnel   SplitTunnel
 	resolvConf    string
 	client        *http.Client
 	logger        Logger
@@ -36,18 +37,24 @@ type Loop struct {
 
 const defaultBackoffTime = 10 * time.Second
 
-func NewLoop(settings settings.DNS,
-	client *http.Client, logger Logger) (loop *Loop, err error) {
+func NewLoop(settings settings.DNS, client *http.Client,
+	filterMetrics mapfilter.Metrics, splitTunnel SplitTunnel,
+	eventPublisher EventPublisher, logger Logger) (loop *Loop, err error) {
 	start := make(chan struct{})
 	running := make(chan models.LoopStatus)
 	stop := make(chan struct{})
 	stopped := make(chan struct{})
 	updateTicker := make(chan struct{})
 
-	statusManager := loopstate.New(constants.Stopped, start, running, stop, stopped)
+	onStatusChange := func(status models.LoopStatus) {
+		eventPublisher.Publish(events.DNSStatus, events.Status{Status: status})
+	}
+	statusManager := loopstate.New(constants.Stopped, start, running, stop, stopped, onStatusChange)
 	state := state.New(statusManager, settings, updateTicker)
 
-	filter, err := mapfilter.New(mapfilter.Settings{})
+	filter, err := mapfilter.New(mapfilter.Settings{
+		Metrics: filterMetrics,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("creating map filter: %w", err)
 	}
@@ -57,6 +64,7 @@ func NewLoop(settings settings.DNS,
 		state:         state,
 		server:        nil,
 		filter:        filter,
+		splitTunnel:   splitTunnel,
 		resolvConf:    "/etc/resolv.conf",
 		client:        client,
 		logger:        logger,
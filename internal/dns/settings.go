@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/qdm12/dns/v2/pkg/doh"
 	"github.com/qdm12/dns/v2/pkg/dot"
 	cachemiddleware "github.com/qdm12/dns/v2/pkg/middlewares/cache"
 	"github.com/qdm12/dns/v2/pkg/middlewares/cache/lru"
@@ -11,6 +12,7 @@ import (
 	"github.com/qdm12/dns/v2/pkg/middlewares/filter/mapfilter"
 	"github.com/qdm12/dns/v2/pkg/provider"
 	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
 )
 
 func (l *Loop) GetSettings() (settings settings.DNS) { return l.state.GetSettings() }
@@ -20,21 +22,77 @@ func (l *Loop) SetSettings(ctx context.Context, settings settings.DNS) (
 	return l.state.SetSettings(ctx, settings)
 }
 
-func buildDoTSettings(settings settings.DNS,
-	filter *mapfilter.Filter, logger Logger) (
-	dotSettings dot.ServerSettings, err error) {
-	var middlewares []dot.Middleware
+// buildServer builds the DNS server using the upstream providers
+// from the settings. If only one protocol is used, it is a DNS over
+// TLS or a DNS over HTTPS server, otherwise it is a server failing
+// over between the protocols, in the order of the providers.
+func buildServer(settings settings.DNS,
+	filter *mapfilter.Filter, splitTunnel SplitTunnel, logger Logger) (
+	server Server, err error) {
+	middlewares, err := buildMiddlewares(settings, filter, splitTunnel, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	ipVersion := "ipv4"
+	if *settings.DoT.IPv6 {
+		ipVersion = "ipv6"
+	}
+
+	groups := groupUpstreams(settings.DoT.GetUpstreams())
+	if len(groups) > 1 {
+		return newFailoverServer(groups, ipVersion, middlewares, logger)
+	}
+
+	switch groups[0].protocol {
+	case constants.DNSOverTLS:
+		server, err = dot.NewServer(dot.ServerSettings{
+			Resolver: dot.ResolverSettings{
+				UpstreamResolvers: groups[0].providers,
+				IPVersion:         ipVersion,
+				Warner:            logger,
+			},
+			Middlewares: middlewares,
+			Logger:      logger,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("creating DoT server: %w", err)
+		}
+	case constants.DNSOverHTTPS:
+		dohMiddlewares := make([]doh.Middleware, len(middlewares))
+		for i, middleware := range middlewares {
+			dohMiddlewares[i] = middleware
+		}
+		server, err = doh.NewServer(doh.ServerSettings{
+			Resolver: doh.ResolverSettings{
+				UpstreamResolvers: groups[0].providers,
+				IPVersion:         ipVersion,
+			},
+			Middlewares: dohMiddlewares,
+			Logger:      logger,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("creating DoH server: %w", err)
+		}
+	default:
+		panic("DNS upstream protocol not supported: " + groups[0].protocol)
+	}
+	return server, nil
+}
 
+func buildMiddlewares(settings settings.DNS,
+	filter *mapfilter.Filter, splitTunnel SplitTunnel, logger Logger) (
+	middlewares []dot.Middleware, err error) {
 	if *settings.DoT.Caching {
 		lruCache, err := lru.New(lru.Settings{})
 		if err != nil {
-			return dot.ServerSettings{}, fmt.Errorf("creating LRU cache: %w", err)
+			return nil, fmt.Errorf("creating LRU cache: %w", err)
 		}
 		cacheMiddleware, err := cachemiddleware.New(cachemiddleware.Settings{
 			Cache: lruCache,
 		})
 		if err != nil {
-			return dot.ServerSettings{}, fmt.Errorf("creating cache middleware: %w", err)
+			return nil, fmt.Errorf("creating cache middleware: %w", err)
 		}
 		middlewares = append(middlewares, cacheMiddleware)
 	}
@@ -43,31 +101,44 @@ func buildDoTSettings(settings settings.DNS,
 		Filter: filter,
 	})
 	if err != nil {
-		return dot.ServerSettings{}, fmt.Errorf("creating filter middleware: %w", err)
+		return nil, fmt.Errorf("creating filter middleware: %w", err)
 	}
 	middlewares = append(middlewares, filterMiddleware)
 
+	// The split tunnel middleware is the outermost middleware
+	// to also see the answers coming from the cache.
+	middlewares = append(middlewares, &splitTunnelMiddleware{
+		splitTunnel: splitTunnel,
+		logger:      logger,
+	})
+
+	return middlewares, nil
+}
+
+// upstreamGroup is a group of upstream providers using the same protocol.
+type upstreamGroup struct {
+	protocol  string
+	providers []provider.Provider
+}
+
+// groupUpstreams groups the upstreams given by protocol, with
+// the groups ordered by first appearance of their protocol.
+func groupUpstreams(upstreams []settings.DNSUpstream) (groups []upstreamGroup) {
 	providersData := provider.NewProviders()
-	providers := make([]provider.Provider, len(settings.DoT.Providers))
-	for i := range settings.DoT.Providers {
-		var err error
-		providers[i], err = providersData.Get(settings.DoT.Providers[i])
+	protocolToIndex := make(map[string]int, len(upstreams))
+	for _, upstream := range upstreams {
+		provider, err := providersData.Get(upstream.Provider)
 		if err != nil {
 			panic(err) // this should already had been checked
 		}
-	}
 
-	ipVersion := "ipv4"
-	if *settings.DoT.IPv6 {
-		ipVersion = "ipv6"
+		index, ok := protocolToIndex[upstream.Protocol]
+		if !ok {
+			index = len(groups)
+			protocolToIndex[upstream.Protocol] = index
+			groups = append(groups, upstreamGroup{protocol: upstream.Protocol})
+		}
+		groups[index].providers = append(groups[index].providers, provider)
 	}
-	return dot.ServerSettings{
-		Resolver: dot.ResolverSettings{
-			UpstreamResolvers: providers,
-			IPVersion:         ipVersion,
-			Warner:            logger,
-		},
-		Middlewares: middlewares,
-		Logger:      logger,
-	}, nil
+	return groups
 }
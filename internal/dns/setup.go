@@ -6,7 +6,6 @@ import (
 	"fmt"
 
 	"github.com/qdm12/dns/v2/pkg/check"
-	"github.com/qdm12/dns/v2/pkg/dot"
 	"github.com/qdm12/dns/v2/pkg/nameserver"
 )
 
@@ -20,14 +19,9 @@ func (l *Loop) setupServer(ctx context.Context) (runError <-chan error, err erro
 
 	settings := l.GetSettings()
 
-	dotSettings, err := buildDoTSettings(settings, l.filter, l.logger)
+	server, err := buildServer(settings, l.filter, l.splitTunnel, l.logger)
 	if err != nil {
-		return nil, fmt.Errorf("building DoT settings: %w", err)
-	}
-
-	server, err := dot.NewServer(dotSettings)
-	if err != nil {
-		return nil, fmt.Errorf("creating DoT server: %w", err)
+		return nil, fmt.Errorf("building server: %w", err)
 	}
 
 	runError, err = server.Start()
@@ -0,0 +1,109 @@
+package dns
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/netip"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// splitTunnelMiddleware gives the IP addresses answered for the
+// outbound domains to the split tunnel, before the answer is
+// written back to the client, such that the client connects to
+// these IP addresses through the default route.
+type splitTunnelMiddleware struct {
+	splitTunnel SplitTunnel
+	logger      Logger
+}
+
+func (m *splitTunnelMiddleware) String() string {
+	return "split tunnel"
+}
+
+func (m *splitTunnelMiddleware) Wrap(next dns.Handler) dns.Handler { //nolint:ireturn
+	return &splitTunnelHandler{
+		next:        next,
+		splitTunnel: m.splitTunnel,
+		logger:      m.logger,
+	}
+}
+
+func (m *splitTunnelMiddleware) Stop() (err error) {
+	return nil
+}
+
+type splitTunnelHandler struct {
+	next        dns.Handler
+	splitTunnel SplitTunnel
+	logger      Logger
+}
+
+func (h *splitTunnelHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
+	if len(r.Question) == 0 || !h.splitTunnel.Match(r.Question[0].Name) {
+		h.next.ServeDNS(w, r)
+		return
+	}
+
+	h.next.ServeDNS(&splitTunnelWriter{
+		ResponseWriter: w,
+		splitTunnel:    h.splitTunnel,
+		logger:         h.logger,
+	}, r)
+}
+
+type splitTunnelWriter struct {
+	dns.ResponseWriter
+	splitTunnel SplitTunnel
+	logger      Logger
+}
+
+func (w *splitTunnelWriter) WriteMsg(response *dns.Msg) error {
+	addresses, ttl := extractAddresses(response)
+	if len(addresses) > 0 {
+		const timeout = 5 * time.Second
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		err := w.splitTunnel.Add(ctx, addresses, ttl)
+		if err != nil {
+			w.logger.Error("routing outbound domain IP addresses: " + err.Error())
+		}
+	}
+	return w.ResponseWriter.WriteMsg(response)
+}
+
+// extractAddresses returns the IP addresses of the A and AAAA
+// records of the response answer, and the smallest TTL of these
+// records.
+func extractAddresses(response *dns.Msg) (addresses []netip.Addr, ttl time.Duration) {
+	if response == nil {
+		return nil, 0
+	}
+
+	minTTL := uint32(math.MaxUint32)
+	for _, rr := range response.Answer {
+		var ip net.IP
+		switch record := rr.(type) {
+		case *dns.A:
+			ip = record.A
+		case *dns.AAAA:
+			ip = record.AAAA
+		default:
+			continue
+		}
+
+		address, ok := netip.AddrFromSlice(ip)
+		if !ok {
+			continue
+		}
+		addresses = append(addresses, address.Unmap())
+		minTTL = min(minTTL, rr.Header().Ttl)
+	}
+
+	if len(addresses) == 0 {
+		return nil, 0
+	}
+	return addresses, time.Duration(minTTL) * time.Second
+}
@@ -0,0 +1,65 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Broker fans out published events to all its subscribers.
+// The zero value is not usable, use New to create a Broker.
+type Broker struct {
+	subscribers map[chan Event]struct{}
+	mutex       sync.RWMutex
+	timeNow     func() time.Time
+}
+
+func New() *Broker {
+	return &Broker{
+		subscribers: make(map[chan Event]struct{}),
+		timeNow:     time.Now,
+	}
+}
+
+// Publish sends an event with the given type and data to all
+// the current subscribers. It never blocks, and drops the event
+// for subscribers which are too slow to consume their events.
+// It is safe to call it on a nil Broker, in which case it is a no-op.
+func (b *Broker) Publish(eventType string, data any) {
+	if b == nil {
+		return
+	}
+
+	event := Event{
+		Type: eventType,
+		Time: b.timeNow(),
+		Data: data,
+	}
+
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel receiving all events published
+// from now on, and an unsubscribe function which must be called
+// once the caller is no longer reading from the channel.
+func (b *Broker) Subscribe() (events <-chan Event, unsubscribe func()) {
+	const bufferSize = 16
+	subscriber := make(chan Event, bufferSize)
+
+	b.mutex.Lock()
+	b.subscribers[subscriber] = struct{}{}
+	b.mutex.Unlock()
+
+	unsubscribe = func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+		delete(b.subscribers, subscriber)
+	}
+	return subscriber, unsubscribe
+}
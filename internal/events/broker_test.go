@@ -0,0 +1,49 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Broker(t *testing.T) {
+	t.Parallel()
+
+	broker := New()
+	eventTime := time.Unix(1, 0)
+	broker.timeNow = func() time.Time { return eventTime }
+
+	eventsA, unsubscribeA := broker.Subscribe()
+	eventsB, unsubscribeB := broker.Subscribe()
+
+	broker.Publish(VPNStatus, Status{Status: "running"})
+	expected := Event{
+		Type: VPNStatus,
+		Time: eventTime,
+		Data: Status{Status: "running"},
+	}
+	assert.Equal(t, expected, <-eventsA)
+	assert.Equal(t, expected, <-eventsB)
+
+	unsubscribeB()
+	broker.Publish(PortForwarded, Ports{Ports: []uint16{1000}})
+	expected = Event{
+		Type: PortForwarded,
+		Time: eventTime,
+		Data: Ports{Ports: []uint16{1000}},
+	}
+	assert.Equal(t, expected, <-eventsA)
+	assert.Empty(t, eventsB)
+
+	unsubscribeA()
+}
+
+func Test_Broker_Publish_nil(t *testing.T) {
+	t.Parallel()
+
+	var broker *Broker
+	assert.NotPanics(t, func() {
+		broker.Publish(VPNStatus, nil)
+	})
+}
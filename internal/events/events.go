@@ -0,0 +1,38 @@
+package events
+
+import (
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+const (
+	VPNStatus      = "vpn_status"
+	DNSStatus      = "dns_status"
+	UpdaterStatus  = "updater_status"
+	PortForwarded  = "port_forwarded"
+	PublicIP       = "public_ip"
+	HealthFlapping = "health_flapping"
+)
+
+// Event is a state change notification, with a type
+// being one of the constants defined in this package and
+// a JSON encodable data payload.
+type Event struct {
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+	Data any       `json:"data"`
+}
+
+type Status struct {
+	Status models.LoopStatus `json:"status"`
+}
+
+type Ports struct {
+	Ports []uint16 `json:"ports"`
+}
+
+type Flapping struct {
+	Flapping    bool `json:"flapping"`
+	Transitions int  `json:"transitions"`
+}
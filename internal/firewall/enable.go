@@ -3,6 +3,7 @@ package firewall
 import (
 	"context"
 	"fmt"
+	"net/netip"
 
 	"github.com/qdm12/gluetun/internal/netlink"
 )
@@ -58,6 +59,11 @@ func (c *Config) disable(ctx context.Context) (err error) {
 		return fmt.Errorf("removing port redirections: %w", err)
 	}
 
+	err = c.unmasqueradeTunnels(ctx)
+	if err != nil {
+		return fmt.Errorf("removing tunnels masquerading: %w", err)
+	}
+
 	return nil
 }
 
@@ -106,6 +112,10 @@ func (c *Config) enable(ctx context.Context) (err error) {
 		return err
 	}
 
+	if err = c.allowTunnels(ctx); err != nil {
+		return err
+	}
+
 	for _, network := range c.localNetworks {
 		if err := c.acceptOutputFromIPToSubnet(ctx, network.InterfaceName, network.IP, network.IPNet, remove); err != nil {
 			return err
@@ -160,7 +170,10 @@ func (c *Config) allowVPNIP(ctx context.Context) (err error) {
 }
 
 func (c *Config) allowOutboundSubnets(ctx context.Context) (err error) {
-	for _, subnet := range c.outboundSubnets {
+	subnets := make([]netip.Prefix, 0, len(c.outboundSubnets)+len(c.outboundDomainIPs))
+	subnets = append(subnets, c.outboundSubnets...)
+	subnets = append(subnets, c.outboundDomainIPs...)
+	for _, subnet := range subnets {
 		subnetIsIPv6 := subnet.Addr().Is6()
 		firewallUpdated := false
 		for _, defaultRoute := range c.defaultRoutes {
@@ -26,7 +26,10 @@ type Config struct { //nolint:maligned
 	enabled           bool
 	vpnConnection     models.Connection
 	vpnIntf           string
+	probeConnections  []models.Connection
+	tunnels           map[string]tunnel // interface to tunnel mapping
 	outboundSubnets   []netip.Prefix
+	outboundDomainIPs []netip.Prefix
 	allowedInputPorts map[uint16]map[string]struct{} // port to interfaces set mapping
 	portRedirections  portRedirections
 	stateMutex        sync.Mutex
@@ -51,6 +54,7 @@ func NewConfig(ctx context.Context, logger Logger,
 		runner:            runner,
 		logger:            logger,
 		allowedInputPorts: make(map[uint16]map[string]struct{}),
+		tunnels:           make(map[string]tunnel),
 		ipTables:          iptables,
 		ip6Tables:         ip6tables,
 		customRulesPath:   "/iptables/post-rules.txt",
@@ -160,6 +160,38 @@ func (c *Config) acceptOutputTrafficToVPN(ctx context.Context,
 	return c.runIP6tablesInstruction(ctx, instruction)
 }
 
+// acceptForwardFromSubnet accepts the traffic from the source subnet
+// given forwarded through the interface given, as well as its replies.
+func (c *Config) acceptForwardFromSubnet(ctx context.Context,
+	intf string, source netip.Prefix, remove bool) error {
+	instructions := []string{
+		fmt.Sprintf("%s FORWARD -s %s -o %s -j ACCEPT",
+			appendOrDelete(remove), source, intf),
+		fmt.Sprintf("%s FORWARD -d %s -i %s -m conntrack --ctstate ESTABLISHED,RELATED -j ACCEPT",
+			appendOrDelete(remove), source, intf),
+	}
+	if source.Addr().Is4() {
+		return c.runIptablesInstructions(ctx, instructions)
+	} else if c.ip6Tables == "" {
+		return fmt.Errorf("accept forward from %s: %w", source, ErrNeedIP6Tables)
+	}
+	return c.runIP6tablesInstructions(ctx, instructions)
+}
+
+// masqueradeSubnet masquerades the traffic from the source subnet
+// given going out through the interface given.
+func (c *Config) masqueradeSubnet(ctx context.Context,
+	intf string, source netip.Prefix, remove bool) error {
+	instruction := fmt.Sprintf("-t nat %s POSTROUTING -s %s -o %s -j MASQUERADE",
+		appendOrDelete(remove), source, intf)
+	if source.Addr().Is4() {
+		return c.runIptablesInstruction(ctx, instruction)
+	} else if c.ip6Tables == "" {
+		return fmt.Errorf("masquerade %s: %w", source, ErrNeedIP6Tables)
+	}
+	return c.runIP6tablesInstruction(ctx, instruction)
+}
+
 // Thanks to @npawelek.
 func (c *Config) acceptOutputFromIPToSubnet(ctx context.Context,
 	intf string, sourceIP netip.Addr, destinationSubnet netip.Prefix, remove bool) error {
@@ -0,0 +1,40 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+
+	"github.com/qdm12/gluetun/internal/subnet"
+)
+
+// SetOutboundDomainIPs allows outbound traffic through the default
+// interface to the IP addresses given, which are the IP addresses
+// resolved for the outbound domains. It removes the rules for the
+// previous IP addresses which are not part of the addresses given.
+func (c *Config) SetOutboundDomainIPs(ctx context.Context, addresses []netip.Addr) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	subnets := make([]netip.Prefix, len(addresses))
+	for i, address := range addresses {
+		subnets[i] = netip.PrefixFrom(address, address.BitLen())
+	}
+
+	if !c.enabled {
+		c.outboundDomainIPs = subnets
+		return nil
+	}
+
+	subnetsToAdd, subnetsToRemove := subnet.FindSubnetsToChange(c.outboundDomainIPs, subnets)
+	if len(subnetsToAdd) == 0 && len(subnetsToRemove) == 0 {
+		return nil
+	}
+
+	c.removeOutboundSubnets(ctx, &c.outboundDomainIPs, subnetsToRemove)
+	if err := c.addOutboundSubnets(ctx, &c.outboundDomainIPs, subnetsToAdd); err != nil {
+		return fmt.Errorf("setting allowed outbound domain IP addresses: %w", err)
+	}
+
+	return nil
+}
@@ -27,15 +27,18 @@ func (c *Config) SetOutboundSubnets(ctx context.Context, subnets []netip.Prefix)
 		return nil
 	}
 
-	c.removeOutboundSubnets(ctx, subnetsToRemove)
-	if err := c.addOutboundSubnets(ctx, subnetsToAdd); err != nil {
+	c.removeOutboundSubnets(ctx, &c.outboundSubnets, subnetsToRemove)
+	if err := c.addOutboundSubnets(ctx, &c.outboundSubnets, subnetsToAdd); err != nil {
 		return fmt.Errorf("setting allowed outbound subnets: %w", err)
 	}
 
 	return nil
 }
 
-func (c *Config) removeOutboundSubnets(ctx context.Context, subnets []netip.Prefix) {
+// removeOutboundSubnets removes the rules allowing outbound traffic to
+// the subnets given, and removes the subnets from the state slice given.
+func (c *Config) removeOutboundSubnets(ctx context.Context,
+	state *[]netip.Prefix, subnets []netip.Prefix) {
 	const remove = true
 	for _, subNet := range subnets {
 		subnetIsIPv6 := subNet.Addr().Is6()
@@ -60,11 +63,14 @@ func (c *Config) removeOutboundSubnets(ctx context.Context, subnets []netip.Pref
 			c.logIgnoredSubnetFamily(subNet)
 			continue
 		}
-		c.outboundSubnets = subnet.RemoveSubnetFromSubnets(c.outboundSubnets, subNet)
+		*state = subnet.RemoveSubnetFromSubnets(*state, subNet)
 	}
 }
 
-func (c *Config) addOutboundSubnets(ctx context.Context, subnets []netip.Prefix) error {
+// addOutboundSubnets adds rules allowing outbound traffic to
+// the subnets given, and adds the subnets to the state slice given.
+func (c *Config) addOutboundSubnets(ctx context.Context,
+	state *[]netip.Prefix, subnets []netip.Prefix) error {
 	const remove = false
 	for _, subnet := range subnets {
 		subnetIsIPv6 := subnet.Addr().Is6()
@@ -88,7 +94,7 @@ func (c *Config) addOutboundSubnets(ctx context.Context, subnets []netip.Prefix)
 			c.logIgnoredSubnetFamily(subnet)
 			continue
 		}
-		c.outboundSubnets = append(c.outboundSubnets, subnet)
+		*state = append(*state, subnet)
 	}
 	return nil
 }
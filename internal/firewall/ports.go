@@ -81,3 +81,38 @@ func (c *Config) RemoveAllowedPort(ctx context.Context, port uint16) (err error)
 
 	return nil
 }
+
+// RemoveAllowedPortOnInterface removes the allowed input port through
+// the network interface given only, keeping the port allowed through
+// other network interfaces.
+func (c *Config) RemoveAllowedPortOnInterface(ctx context.Context,
+	port uint16, intf string) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	interfacesSet, ok := c.allowedInputPorts[port]
+	if !ok {
+		return nil
+	} else if _, ok := interfacesSet[intf]; !ok {
+		return nil
+	}
+
+	if !c.enabled {
+		c.logger.Info("firewall disabled, only updating allowed ports internal list")
+	} else {
+		c.logger.Info("removing allowed port " + strconv.Itoa(int(port)) +
+			" through interface " + intf + "...")
+		const remove = true
+		err = c.acceptInputToPort(ctx, intf, port, remove)
+		if err != nil {
+			return fmt.Errorf("removing allowed port %d on interface %s: %w",
+				port, intf, err)
+		}
+	}
+
+	delete(interfacesSet, intf)
+	if len(interfacesSet) == 0 {
+		delete(c.allowedInputPorts, port)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// SetProbeConnections allows outbound traffic to the connections given,
+// in order to probe VPN servers before connecting to one of them.
+// It removes the rules allowing the previous probe connections, such
+// that calling it with no connection removes all the probe rules.
+func (c *Config) SetProbeConnections(ctx context.Context,
+	connections []models.Connection) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if !c.enabled {
+		return nil
+	}
+
+	remove := true
+	for _, connection := range c.probeConnections {
+		for _, defaultRoute := range c.defaultRoutes {
+			err := c.acceptOutputTrafficToVPN(ctx, defaultRoute.NetInterface, connection, remove)
+			if err != nil {
+				c.logger.Error("cannot remove probe connection rule: " + err.Error())
+			}
+		}
+	}
+	c.probeConnections = nil
+
+	remove = false
+	for _, connection := range connections {
+		// record the connection first to remove partially added rules later
+		c.probeConnections = append(c.probeConnections, connection)
+		for _, defaultRoute := range c.defaultRoutes {
+			err := c.acceptOutputTrafficToVPN(ctx, defaultRoute.NetInterface, connection, remove)
+			if err != nil {
+				return fmt.Errorf("allowing output traffic to probe connection: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
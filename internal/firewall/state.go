@@ -0,0 +1,79 @@
+package firewall
+
+import (
+	"net/netip"
+	"slices"
+	"sort"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// State is a snapshot of the rules state managed by the firewall.
+type State struct {
+	Enabled          bool              `json:"enabled"`
+	VPNConnection    models.Connection `json:"vpn_connection"`
+	VPNInterface     string            `json:"vpn_interface"`
+	OutboundSubnets  []netip.Prefix    `json:"outbound_subnets"`
+	InputPorts       []InputPort       `json:"input_ports"`
+	PortRedirections []PortRedirection `json:"port_redirections"`
+	// DefaultInterfaces are the network interfaces of the default
+	// routes, which input ports are allowed on by default.
+	DefaultInterfaces []string `json:"default_interfaces"`
+}
+
+type InputPort struct {
+	Port       uint16   `json:"port"`
+	Interfaces []string `json:"interfaces"`
+}
+
+type PortRedirection struct {
+	Interface       string `json:"interface"`
+	SourcePort      uint16 `json:"source_port"`
+	DestinationPort uint16 `json:"destination_port"`
+}
+
+// GetState returns a deep copy of the current firewall state.
+func (c *Config) GetState() (state State) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	state.Enabled = c.enabled
+	state.VPNConnection = c.vpnConnection
+	state.VPNInterface = c.vpnIntf
+	state.OutboundSubnets = make([]netip.Prefix, len(c.outboundSubnets))
+	copy(state.OutboundSubnets, c.outboundSubnets)
+
+	state.InputPorts = make([]InputPort, 0, len(c.allowedInputPorts))
+	for port, interfacesSet := range c.allowedInputPorts {
+		interfaces := make([]string, 0, len(interfacesSet))
+		for intf := range interfacesSet {
+			interfaces = append(interfaces, intf)
+		}
+		sort.Strings(interfaces)
+		state.InputPorts = append(state.InputPorts, InputPort{
+			Port:       port,
+			Interfaces: interfaces,
+		})
+	}
+	sort.Slice(state.InputPorts, func(i, j int) bool {
+		return state.InputPorts[i].Port < state.InputPorts[j].Port
+	})
+
+	state.PortRedirections = make([]PortRedirection, len(c.portRedirections))
+	for i, redirection := range c.portRedirections {
+		state.PortRedirections[i] = PortRedirection{
+			Interface:       redirection.interfaceName,
+			SourcePort:      redirection.sourcePort,
+			DestinationPort: redirection.destinationPort,
+		}
+	}
+
+	state.DefaultInterfaces = make([]string, 0, len(c.defaultRoutes))
+	for _, defaultRoute := range c.defaultRoutes {
+		if !slices.Contains(state.DefaultInterfaces, defaultRoute.NetInterface) {
+			state.DefaultInterfaces = append(state.DefaultInterfaces, defaultRoute.NetInterface)
+		}
+	}
+
+	return state
+}
@@ -0,0 +1,57 @@
+package firewall
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/routing"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Config_GetState(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		enabled:       true,
+		vpnConnection: models.Connection{Type: "wireguard", Port: 51820},
+		vpnIntf:       "wg0",
+		outboundSubnets: []netip.Prefix{
+			netip.MustParsePrefix("10.0.0.0/8"),
+		},
+		allowedInputPorts: map[uint16]map[string]struct{}{
+			8000: {"eth1": {}, "eth0": {}},
+			1000: {"wg0": {}},
+		},
+		portRedirections: portRedirections{
+			{interfaceName: "wg0", sourcePort: 1000, destinationPort: 2000},
+		},
+		defaultRoutes: []routing.DefaultRoute{
+			{NetInterface: "eth0"},
+			{NetInterface: "eth0"},
+			{NetInterface: "eth1"},
+		},
+	}
+
+	state := config.GetState()
+
+	expectedState := State{
+		Enabled:         true,
+		VPNConnection:   models.Connection{Type: "wireguard", Port: 51820},
+		VPNInterface:    "wg0",
+		OutboundSubnets: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+		InputPorts: []InputPort{
+			{Port: 1000, Interfaces: []string{"wg0"}},
+			{Port: 8000, Interfaces: []string{"eth0", "eth1"}},
+		},
+		PortRedirections: []PortRedirection{
+			{Interface: "wg0", SourcePort: 1000, DestinationPort: 2000},
+		},
+		DefaultInterfaces: []string{"eth0", "eth1"},
+	}
+	assert.Equal(t, expectedState, state)
+
+	// Mutating the state does not mutate the configuration
+	state.OutboundSubnets[0] = netip.Prefix{}
+	assert.Equal(t, netip.MustParsePrefix("10.0.0.0/8"), config.outboundSubnets[0])
+}
@@ -0,0 +1,158 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+type tunnel struct {
+	connection    models.Connection
+	sourceSubnets []netip.Prefix
+}
+
+// SetTunnelConnection allows the connection of an additional VPN tunnel,
+// the output traffic through its interface given, and the traffic from
+// the source subnets given forwarded through its interface. It replaces
+// the rules for the previous connection of the tunnel with the same
+// interface, and removes all the rules for the tunnel if the connection
+// given is the zero value. The source subnets should not change for the
+// same interface.
+func (c *Config) SetTunnelConnection(ctx context.Context,
+	connection models.Connection, intf string,
+	sourceSubnets []netip.Prefix) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	previous, exists := c.tunnels[intf]
+	if exists && previous.connection.Equal(connection) {
+		return nil
+	}
+
+	current := tunnel{
+		connection:    connection,
+		sourceSubnets: sourceSubnets,
+	}
+
+	if c.enabled {
+		err = c.replaceTunnel(ctx, previous, current, intf)
+		if err != nil {
+			return err
+		}
+	}
+
+	if connection.IP.IsValid() {
+		c.tunnels[intf] = current
+	} else {
+		delete(c.tunnels, intf)
+	}
+	return nil
+}
+
+func (c *Config) replaceTunnel(ctx context.Context,
+	previous, current tunnel, intf string) (err error) {
+	remove := false
+	if current.connection.IP.IsValid() {
+		for _, defaultRoute := range c.defaultRoutes {
+			err = c.acceptOutputTrafficToVPN(ctx, defaultRoute.NetInterface, current.connection, remove)
+			if err != nil {
+				return fmt.Errorf("allowing output traffic through tunnel connection: %w", err)
+			}
+		}
+
+		if !previous.connection.IP.IsValid() {
+			err = c.allowTunnelInterface(ctx, intf, current.sourceSubnets)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if !previous.connection.IP.IsValid() {
+		return nil
+	}
+
+	remove = true
+	for _, defaultRoute := range c.defaultRoutes {
+		err = c.acceptOutputTrafficToVPN(ctx, defaultRoute.NetInterface, previous.connection, remove)
+		if err != nil {
+			c.logger.Error("cannot remove outdated tunnel connection rule: " + err.Error())
+		}
+	}
+
+	if current.connection.IP.IsValid() {
+		return nil
+	}
+
+	err = c.acceptOutputThroughInterface(ctx, intf, remove)
+	if err != nil {
+		c.logger.Error("cannot remove tunnel interface rule: " + err.Error())
+	}
+	for _, subnet := range previous.sourceSubnets {
+		err = c.acceptForwardFromSubnet(ctx, intf, subnet, remove)
+		if err != nil {
+			c.logger.Error("cannot remove tunnel forward rules: " + err.Error())
+		}
+		err = c.masqueradeSubnet(ctx, intf, subnet, remove)
+		if err != nil {
+			c.logger.Error("cannot remove tunnel masquerade rule: " + err.Error())
+		}
+	}
+	return nil
+}
+
+func (c *Config) allowTunnelInterface(ctx context.Context, intf string,
+	sourceSubnets []netip.Prefix) (err error) {
+	const remove = false
+	err = c.acceptOutputThroughInterface(ctx, intf, remove)
+	if err != nil {
+		return fmt.Errorf("accepting output traffic through interface %s: %w", intf, err)
+	}
+
+	for _, subnet := range sourceSubnets {
+		err = c.acceptForwardFromSubnet(ctx, intf, subnet, remove)
+		if err != nil {
+			return fmt.Errorf("accepting forwarded traffic through interface %s: %w", intf, err)
+		}
+		err = c.masqueradeSubnet(ctx, intf, subnet, remove)
+		if err != nil {
+			return fmt.Errorf("masquerading traffic through interface %s: %w", intf, err)
+		}
+	}
+	return nil
+}
+
+func (c *Config) allowTunnels(ctx context.Context) (err error) {
+	const remove = false
+	for intf, tunnel := range c.tunnels {
+		for _, defaultRoute := range c.defaultRoutes {
+			err = c.acceptOutputTrafficToVPN(ctx, defaultRoute.NetInterface, tunnel.connection, remove)
+			if err != nil {
+				return fmt.Errorf("accepting output traffic through tunnel connection: %w", err)
+			}
+		}
+
+		err = c.allowTunnelInterface(ctx, intf, tunnel.sourceSubnets)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unmasqueradeTunnels removes the masquerade rules of the tunnels,
+// which are not removed by flushing the filter table chains.
+func (c *Config) unmasqueradeTunnels(ctx context.Context) (err error) {
+	const remove = true
+	for intf, tunnel := range c.tunnels {
+		for _, subnet := range tunnel.sourceSubnets {
+			err = c.masqueradeSubnet(ctx, intf, subnet, remove)
+			if err != nil {
+				return fmt.Errorf("removing masquerade rule for interface %s: %w", intf, err)
+			}
+		}
+	}
+	return nil
+}
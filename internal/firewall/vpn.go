@@ -7,6 +7,10 @@ import (
 	"github.com/qdm12/gluetun/internal/models"
 )
 
+// SetVPNConnection allows the VPN connection and the VPN interface given.
+// The rules for the new connection are added before the rules for the
+// previous connection are removed, such that traffic going through the
+// previous connection is not dropped while switching connection.
 func (c *Config) SetVPNConnection(ctx context.Context,
 	connection models.Connection, vpnIntf string) (err error) {
 	c.stateMutex.Lock()
@@ -24,7 +28,20 @@ func (c *Config) SetVPNConnection(ctx context.Context,
 		return nil
 	}
 
-	remove := true
+	remove := false
+	for _, defaultRoute := range c.defaultRoutes {
+		if err := c.acceptOutputTrafficToVPN(ctx, defaultRoute.NetInterface, connection, remove); err != nil {
+			return fmt.Errorf("allowing output traffic through VPN connection: %w", err)
+		}
+	}
+
+	if vpnIntf != c.vpnIntf {
+		if err = c.acceptOutputThroughInterface(ctx, vpnIntf, remove); err != nil {
+			return fmt.Errorf("accepting output traffic through interface %s: %w", vpnIntf, err)
+		}
+	}
+
+	remove = true
 	if c.vpnConnection.IP.IsValid() {
 		for _, defaultRoute := range c.defaultRoutes {
 			if err := c.acceptOutputTrafficToVPN(ctx, defaultRoute.NetInterface, c.vpnConnection, remove); err != nil {
@@ -32,27 +49,13 @@ func (c *Config) SetVPNConnection(ctx context.Context,
 			}
 		}
 	}
-	c.vpnConnection = models.Connection{}
+	c.vpnConnection = connection
 
-	if c.vpnIntf != "" {
+	if c.vpnIntf != "" && c.vpnIntf != vpnIntf {
 		if err = c.acceptOutputThroughInterface(ctx, c.vpnIntf, remove); err != nil {
 			c.logger.Error("cannot remove outdated VPN interface rule: " + err.Error())
 		}
 	}
-	c.vpnIntf = ""
-
-	remove = false
-
-	for _, defaultRoute := range c.defaultRoutes {
-		if err := c.acceptOutputTrafficToVPN(ctx, defaultRoute.NetInterface, connection, remove); err != nil {
-			return fmt.Errorf("allowing output traffic through VPN connection: %w", err)
-		}
-	}
-	c.vpnConnection = connection
-
-	if err = c.acceptOutputThroughInterface(ctx, vpnIntf, remove); err != nil {
-		return fmt.Errorf("accepting output traffic through interface %s: %w", vpnIntf, err)
-	}
 	c.vpnIntf = vpnIntf
 
 	return nil
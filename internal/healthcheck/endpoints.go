@@ -0,0 +1,199 @@
+package healthcheck
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// statusEndpoints contains the state and the objects used by
+// the liveness, readiness and startup endpoints.
+type statusEndpoints struct {
+	vpn StatusApplier
+	dns DNSLoop
+	// firewall is nil if the firewall is disabled in the settings.
+	firewall Firewall
+	// portForward is nil if a forwarded port is not required
+	// for the program to be ready.
+	portForward        PortForwarder
+	initialHealthyWait time.Duration
+	successWait        time.Duration
+	startTime          time.Time
+	heartbeat          time.Time
+	terminal           bool
+	mutex              sync.RWMutex
+	timeNow            func() time.Time
+}
+
+func newStatusEndpoints(vpn StatusApplier, dns DNSLoop, firewall Firewall,
+	portForward PortForwarder, initialHealthyWait, successWait time.Duration,
+) *statusEndpoints {
+	now := time.Now()
+	return &statusEndpoints{
+		vpn:                vpn,
+		dns:                dns,
+		firewall:           firewall,
+		portForward:        portForward,
+		initialHealthyWait: initialHealthyWait,
+		successWait:        successWait,
+		startTime:          now,
+		heartbeat:          now,
+		timeNow:            time.Now,
+	}
+}
+
+// beat records the health check loop is alive.
+func (e *statusEndpoints) beat() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.heartbeat = e.timeNow()
+}
+
+func (e *statusEndpoints) setTerminal(terminal bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.terminal = terminal
+}
+
+func (e *statusEndpoints) isTerminal() (terminal bool) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	return e.terminal
+}
+
+type check struct {
+	name string
+	err  error
+}
+
+var (
+	ErrHealthLoopStalled    = errors.New("health check loop is stalled")
+	ErrLoopNotRunning       = errors.New("loop is not running")
+	ErrFirewallDisabled     = errors.New("firewall is disabled")
+	ErrNoPortForwarded      = errors.New("no port is forwarded")
+	ErrFirstHealthCheckWait = errors.New("waiting for the first successful health check")
+)
+
+// livenessChecks returns the checks for the process and its loops to
+// be alive. They do not depend on the VPN connection health, so the
+// program stays alive while reconnecting, unless all the unhealthy
+// escalation steps failed.
+func (h *handler) livenessChecks() (checks []check) {
+	h.endpoints.mutex.RLock()
+	heartbeat, terminal := h.endpoints.heartbeat, h.endpoints.terminal
+	now := h.endpoints.timeNow()
+	h.endpoints.mutex.RUnlock()
+
+	// The loop runs at least every success wait duration, and the
+	// margin covers slow health checks and VPN restarts.
+	const margin = 5 * time.Minute
+	maxHeartbeatAge := h.endpoints.successWait + margin
+	var loopErr error
+	if age := now.Sub(heartbeat); age > maxHeartbeatAge {
+		loopErr = fmt.Errorf("%w: last run %s ago", ErrHealthLoopStalled, age.Round(time.Second))
+	}
+
+	var escalationErr error
+	if terminal {
+		escalationErr = ErrTerminallyUnhealthy
+	}
+
+	return []check{
+		{name: "healthcheck loop", err: loopErr},
+		{name: "vpn escalation", err: escalationErr},
+	}
+}
+
+// readinessChecks returns the checks for the program to be ready
+// to route traffic: the VPN is running and healthy, the DNS server
+// is running if enabled, the firewall is enabled if enabled in the
+// settings and a port is forwarded if required.
+func (h *handler) readinessChecks() (checks []check) {
+	checks = []check{
+		{name: "vpn", err: checkLoopRunning(h.endpoints.vpn.GetStatus())},
+		{name: "healthcheck", err: h.getErr()},
+	}
+
+	if *h.endpoints.dns.GetSettings().DoT.Enabled {
+		checks = append(checks, check{
+			name: "dns", err: checkLoopRunning(h.endpoints.dns.GetStatus()),
+		})
+	}
+
+	if h.endpoints.firewall != nil {
+		var err error
+		if !h.endpoints.firewall.GetState().Enabled {
+			err = ErrFirewallDisabled
+		}
+		checks = append(checks, check{name: "firewall", err: err})
+	}
+
+	if h.endpoints.portForward != nil {
+		var err error
+		if len(h.endpoints.portForward.GetPortsForwarded()) == 0 {
+			err = ErrNoPortForwarded
+		}
+		checks = append(checks, check{name: "port forwarding", err: err})
+	}
+
+	return checks
+}
+
+// startupChecks returns the check for the program to be started,
+// which succeeds once a health check succeeded or once the initial
+// healthy wait duration elapsed, from which point the unhealthy
+// VPN restarts and the liveness endpoint take over.
+func (h *handler) startupChecks() (checks []check) {
+	h.healthErrMu.RLock()
+	healthyOnce := h.healthyOnce
+	h.healthErrMu.RUnlock()
+
+	var err error
+	elapsed := h.endpoints.timeNow().Sub(h.endpoints.startTime)
+	if !healthyOnce && elapsed < h.endpoints.initialHealthyWait {
+		err = fmt.Errorf("%w: up to %s more", ErrFirstHealthCheckWait,
+			(h.endpoints.initialHealthyWait - elapsed).Round(time.Second))
+	}
+	return []check{{name: "startup", err: err}}
+}
+
+func checkLoopRunning(status models.LoopStatus) (err error) {
+	if status != constants.Running {
+		return fmt.Errorf("%w: status is %s", ErrLoopNotRunning, status)
+	}
+	return nil
+}
+
+// writeChecks writes the checks results in a plain text format
+// similar to the Kubernetes API server health endpoints, with
+// the status code 200 if all checks pass and 503 otherwise.
+func writeChecks(responseWriter http.ResponseWriter, endpoint string, checks []check) {
+	lines := make([]string, 0, len(checks)+1)
+	failed := false
+	for _, check := range checks {
+		if check.err == nil {
+			lines = append(lines, "[+]"+check.name+" ok")
+			continue
+		}
+		failed = true
+		lines = append(lines, "[-]"+check.name+" failed: "+check.err.Error())
+	}
+
+	statusCode := http.StatusOK
+	if failed {
+		statusCode = http.StatusServiceUnavailable
+		lines = append(lines, endpoint+" check failed")
+	} else {
+		lines = append(lines, endpoint+" check passed")
+	}
+
+	responseWriter.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	responseWriter.WriteHeader(statusCode)
+	_, _ = responseWriter.Write([]byte(strings.Join(lines, "\n") + "\n"))
+}
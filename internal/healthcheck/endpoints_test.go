@@ -0,0 +1,104 @@
+package healthcheck
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_handler_startupChecks(t *testing.T) {
+	t.Parallel()
+
+	startTime := time.Unix(1000, 0)
+
+	testCases := map[string]struct {
+		healthyOnce bool
+		now         time.Time
+		errMessage  string
+	}{
+		"waiting": {
+			now: startTime.Add(2 * time.Second),
+			errMessage: "waiting for the first successful health check: " +
+				"up to 4s more",
+		},
+		"healthy_once": {
+			healthyOnce: true,
+			now:         startTime.Add(time.Second),
+		},
+		"wait_elapsed": {
+			now: startTime.Add(6 * time.Second),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			h := &handler{
+				healthyOnce: testCase.healthyOnce,
+				endpoints: &statusEndpoints{
+					initialHealthyWait: 6 * time.Second,
+					startTime:          startTime,
+					timeNow:            func() time.Time { return testCase.now },
+				},
+			}
+
+			checks := h.startupChecks()
+
+			assert.Len(t, checks, 1)
+			if testCase.errMessage == "" {
+				assert.NoError(t, checks[0].err)
+			} else {
+				assert.ErrorIs(t, checks[0].err, ErrFirstHealthCheckWait)
+				assert.EqualError(t, checks[0].err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_writeChecks(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		checks     []check
+		statusCode int
+		body       string
+	}{
+		"all_pass": {
+			checks: []check{
+				{name: "vpn"},
+				{name: "healthcheck"},
+			},
+			statusCode: http.StatusOK,
+			body:       "[+]vpn ok\n[+]healthcheck ok\nreadyz check passed\n",
+		},
+		"one_fails": {
+			checks: []check{
+				{name: "vpn"},
+				{name: "firewall", err: errors.New("firewall is disabled")},
+			},
+			statusCode: http.StatusServiceUnavailable,
+			body: "[+]vpn ok\n[-]firewall failed: firewall is disabled\n" +
+				"readyz check failed\n",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+
+			writeChecks(recorder, "readyz", testCase.checks)
+
+			assert.Equal(t, testCase.statusCode, recorder.Code)
+			assert.Equal(t, testCase.body, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package healthcheck
+
+import (
+	"time"
+)
+
+// flapDetector detects the health flapping between healthy and
+// unhealthy, by counting the transitions within a time window.
+type flapDetector struct {
+	window      time.Duration
+	threshold   uint
+	transitions []time.Time
+	flapping    bool
+}
+
+func newFlapDetector(window time.Duration, threshold uint) *flapDetector {
+	return &flapDetector{
+		window:    window,
+		threshold: threshold,
+	}
+}
+
+// update updates the flapping state at the time given, recording a
+// transition if transition is true. It returns the flapping state,
+// whether it changed and the number of transitions in the window.
+func (f *flapDetector) update(transition bool, now time.Time) (
+	flapping, changed bool, transitions int) {
+	if f.threshold == 0 {
+		return false, false, 0
+	}
+
+	if transition {
+		f.transitions = append(f.transitions, now)
+	}
+
+	windowStart := now.Add(-f.window)
+	expired := 0
+	for expired < len(f.transitions) && !f.transitions[expired].After(windowStart) {
+		expired++
+	}
+	f.transitions = f.transitions[expired:]
+
+	flapping = uint(len(f.transitions)) >= f.threshold
+	changed = flapping != f.flapping
+	f.flapping = flapping
+	return flapping, changed, len(f.transitions)
+}
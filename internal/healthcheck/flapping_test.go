@@ -0,0 +1,39 @@
+package healthcheck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_flapDetector_update(t *testing.T) {
+	t.Parallel()
+
+	const window = time.Minute
+	const threshold = 3
+	detector := newFlapDetector(window, threshold)
+	start := time.Unix(10000, 0)
+
+	steps := []struct {
+		transition  bool
+		elapsed     time.Duration
+		flapping    bool
+		changed     bool
+		transitions int
+	}{
+		{transition: true, elapsed: 0, transitions: 1},
+		{transition: false, elapsed: 10 * time.Second, transitions: 1},
+		{transition: true, elapsed: 20 * time.Second, transitions: 2},
+		{transition: true, elapsed: 30 * time.Second, flapping: true, changed: true, transitions: 3},
+		{transition: false, elapsed: 50 * time.Second, flapping: true, transitions: 3},
+		{transition: false, elapsed: 70 * time.Second, changed: true, transitions: 2},
+	}
+
+	for i, step := range steps {
+		flapping, changed, transitions := detector.update(step.transition, start.Add(step.elapsed))
+		assert.Equal(t, step.flapping, flapping, "step %d", i)
+		assert.Equal(t, step.changed, changed, "step %d", i)
+		assert.Equal(t, step.transitions, transitions, "step %d", i)
+	}
+}
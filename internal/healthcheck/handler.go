@@ -1,21 +1,30 @@
 package healthcheck
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"sync"
+
+	"github.com/qdm12/gluetun/internal/models"
 )
 
 type handler struct {
 	healthErr   error
+	healthyOnce bool
 	healthErrMu sync.RWMutex
+	history     *history
+	// Liveness, readiness and startup
+	endpoints *statusEndpoints
 }
 
 var errHealthcheckNotRunYet = errors.New("healthcheck did not run yet")
 
-func newHandler() *handler {
+func newHandler(history *history, endpoints *statusEndpoints) *handler {
 	return &handler{
 		healthErr: errHealthcheckNotRunYet,
+		history:   history,
+		endpoints: endpoints,
 	}
 }
 
@@ -24,6 +33,20 @@ func (h *handler) ServeHTTP(responseWriter http.ResponseWriter, request *http.Re
 		http.Error(responseWriter, "method not supported for healthcheck", http.StatusBadRequest)
 		return
 	}
+	switch request.URL.Path {
+	case "/history":
+		h.getHistory(responseWriter)
+		return
+	case "/livez":
+		writeChecks(responseWriter, "livez", h.livenessChecks())
+		return
+	case "/readyz":
+		writeChecks(responseWriter, "readyz", h.readinessChecks())
+		return
+	case "/startupz":
+		writeChecks(responseWriter, "startupz", h.startupChecks())
+		return
+	}
 	if err := h.getErr(); err != nil {
 		http.Error(responseWriter, err.Error(), http.StatusInternalServerError)
 		return
@@ -31,10 +54,26 @@ func (h *handler) ServeHTTP(responseWriter http.ResponseWriter, request *http.Re
 	responseWriter.WriteHeader(http.StatusOK)
 }
 
+func (h *handler) getHistory(responseWriter http.ResponseWriter) {
+	data := struct {
+		Results []models.HealthResult `json:"results"`
+	}{
+		Results: h.history.get(),
+	}
+	responseWriter.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(responseWriter).Encode(data)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 func (h *handler) setErr(err error) {
 	h.healthErrMu.Lock()
 	defer h.healthErrMu.Unlock()
 	h.healthErr = err
+	if err == nil {
+		h.healthyOnce = true
+	}
 }
 
 func (h *handler) getErr() (err error) {
@@ -4,10 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"net"
 	"time"
+
+	"github.com/qdm12/gluetun/internal/events"
+	"github.com/qdm12/gluetun/internal/models"
 )
 
+var ErrTerminallyUnhealthy = errors.New("terminally unhealthy, VPN is no longer restarted")
+
 func (s *Server) runHealthcheckLoop(ctx context.Context, done chan<- struct{}) {
 	defer close(done)
 
@@ -24,22 +28,37 @@ func (s *Server) runHealthcheckLoop(ctx context.Context, done chan<- struct{}) {
 	s.vpn.healthyTimer = time.NewTimer(s.vpn.healthyWait)
 
 	for {
+		s.endpoints.beat()
 		previousErr := s.handler.getErr()
 
 		timeout := healthcheckTimeouts[timeoutIndex]
 		healthcheckCtx, healthcheckCancel := context.WithTimeout(
 			ctx, timeout)
+		healthcheckStart := time.Now()
 		err := s.healthCheck(healthcheckCtx)
 		healthcheckCancel()
+		healthcheckDuration := time.Since(healthcheckStart)
+		s.metrics.HealthCheckObserve(healthcheckDuration, err)
+		s.recordOverallResult(healthcheckStart, healthcheckDuration, err)
+
+		if err != nil && s.endpoints.isTerminal() {
+			err = fmt.Errorf("%w: %w", ErrTerminallyUnhealthy, err)
+		}
 
 		s.handler.setErr(err)
 
+		transition := !errors.Is(previousErr, errHealthcheckNotRunYet) &&
+			(previousErr == nil) != (err == nil)
+		s.updateFlapping(transition)
+
 		switch {
 		case previousErr != nil && err == nil: // First success
 			s.logger.Info("healthy!")
+			s.vpn.loop.RecordConnectionHealthy()
 			timeoutIndex = 0
 			s.vpn.healthyTimer.Stop()
 			s.vpn.healthyWait = *s.config.VPN.Initial
+			s.endpoints.setTerminal(false)
 		case previousErr == nil && err != nil: // First failure
 			s.logger.Debug("unhealthy: " + err.Error())
 			s.vpn.healthyTimer.Stop()
@@ -65,40 +84,35 @@ func (s *Server) runHealthcheckLoop(ctx context.Context, done chan<- struct{}) {
 	}
 }
 
-func (s *Server) healthCheck(ctx context.Context) (err error) {
-	// TODO use mullvad API if current provider is Mullvad
-
-	address, err := makeAddressToDial(s.config.TargetAddress)
-	if err != nil {
-		return err
+func (s *Server) recordOverallResult(start time.Time,
+	duration time.Duration, err error) {
+	result := models.HealthResult{
+		Time:    start,
+		Probe:   historyOverallProbe,
+		Latency: duration,
 	}
-
-	const dialNetwork = "tcp4"
-	connection, err := s.dialer.DialContext(ctx, dialNetwork, address)
 	if err != nil {
-		return fmt.Errorf("dialing: %w", err)
+		result.Error = err.Error()
 	}
+	s.history.add(result)
+}
 
-	err = connection.Close()
-	if err != nil {
-		return fmt.Errorf("closing connection: %w", err)
+// updateFlapping updates the flapping state, and logs and publishes
+// an event if the health starts or stops flapping.
+func (s *Server) updateFlapping(transition bool) {
+	flapping, changed, transitions := s.flapping.update(transition, time.Now())
+	if !changed {
+		return
 	}
 
-	return nil
-}
-
-func makeAddressToDial(address string) (addressToDial string, err error) {
-	host, port, err := net.SplitHostPort(address)
-	if err != nil {
-		addrErr := new(net.AddrError)
-		ok := errors.As(err, &addrErr)
-		if !ok || addrErr.Err != "missing port in address" {
-			return "", fmt.Errorf("splitting host and port from address: %w", err)
-		}
-		host = address
-		const defaultPort = "443"
-		port = defaultPort
+	if flapping {
+		s.logger.Warn(fmt.Sprintf("health is flapping with %d transitions in the last %s, "+
+			"holding off VPN restarts", transitions, s.config.FlappingWindow))
+	} else {
+		s.logger.Info("health is no longer flapping, resuming VPN restarts")
 	}
-	address = net.JoinHostPort(host, port)
-	return address, nil
+	s.events.Publish(events.HealthFlapping, events.Flapping{
+		Flapping:    flapping,
+		Transitions: transitions,
+	})
 }
@@ -0,0 +1,3 @@
+package healthcheck
+
+func ptrTo[T any](value T) *T { return &value }
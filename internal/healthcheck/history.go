@@ -0,0 +1,59 @@
+package healthcheck
+
+import (
+	"sync"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// historyOverallProbe is the probe name used for the
+// result of a whole health check in the history.
+const historyOverallProbe = "overall"
+
+// history is a bounded ring buffer of health results,
+// where the oldest results are overwritten once it is full.
+type history struct {
+	results []models.HealthResult
+	next    int
+	full    bool
+	mutex   sync.RWMutex
+}
+
+func newHistory(size int) *history {
+	return &history{
+		results: make([]models.HealthResult, size),
+	}
+}
+
+func (h *history) add(result models.HealthResult) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.results[h.next] = result
+	h.next = (h.next + 1) % len(h.results)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// get returns a copy of the results, sorted from the most recent.
+func (h *history) get() (results []models.HealthResult) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	count := h.next
+	if h.full {
+		count = len(h.results)
+	}
+	results = make([]models.HealthResult, count)
+	for i := range results {
+		index := (h.next - 1 - i + len(h.results)) % len(h.results)
+		results[i] = h.results[index]
+	}
+	return results
+}
+
+// GetHistory returns the latest health results,
+// sorted from the most recent.
+func (s *Server) GetHistory() (results []models.HealthResult) {
+	return s.history.get()
+}
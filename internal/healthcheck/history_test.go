@@ -0,0 +1,27 @@
+package healthcheck
+
+import (
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_history(t *testing.T) {
+	t.Parallel()
+
+	history := newHistory(3)
+	assert.Empty(t, history.get())
+
+	history.add(models.HealthResult{Probe: "1"})
+	history.add(models.HealthResult{Probe: "2"})
+	assert.Equal(t, []models.HealthResult{
+		{Probe: "2"}, {Probe: "1"},
+	}, history.get())
+
+	history.add(models.HealthResult{Probe: "3"})
+	history.add(models.HealthResult{Probe: "4"})
+	assert.Equal(t, []models.HealthResult{
+		{Probe: "4"}, {Probe: "3"}, {Probe: "2"},
+	}, history.get())
+}
@@ -0,0 +1,116 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+	"golang.org/x/sys/unix"
+)
+
+var ErrVPNInterfaceNotSet = errors.New("VPN interface is not set")
+
+// probeICMP sends an ICMP echo request to the IP address target
+// through the VPN interface and waits for the matching echo reply.
+func (s *Server) probeICMP(ctx context.Context, target string) (err error) {
+	ip, err := netip.ParseAddr(target)
+	if err != nil {
+		return fmt.Errorf("parsing IP address: %w", err)
+	}
+
+	vpnInterface := s.vpn.loop.GetInterface()
+	if vpnInterface == "" {
+		return fmt.Errorf("%w", ErrVPNInterfaceNotSet)
+	}
+
+	network := "ip4:icmp"
+	protocol := 1 // ICMP protocol number
+	var requestType, replyType icmp.Type = ipv4.ICMPTypeEcho, ipv4.ICMPTypeEchoReply
+	if ip.Is6() {
+		network = "ip6:ipv6-icmp"
+		protocol = 58 // ICMPv6 protocol number
+		requestType, replyType = ipv6.ICMPTypeEchoRequest, ipv6.ICMPTypeEchoReply
+	}
+
+	listenConfig := net.ListenConfig{
+		Control: func(_, _ string, rawConn syscall.RawConn) (err error) {
+			var bindErr error
+			err = rawConn.Control(func(fd uintptr) {
+				bindErr = unix.SetsockoptString(int(fd), unix.SOL_SOCKET,
+					unix.SO_BINDTODEVICE, vpnInterface)
+			})
+			if err != nil {
+				return err
+			}
+			return bindErr
+		},
+	}
+	connection, err := listenConfig.ListenPacket(ctx, network, "")
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", vpnInterface, err)
+	}
+	defer connection.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		err = connection.SetDeadline(deadline)
+		if err != nil {
+			return fmt.Errorf("setting deadline: %w", err)
+		}
+	}
+	stop := context.AfterFunc(ctx, func() {
+		_ = connection.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	echo := &icmp.Echo{
+		ID:   rand.Intn(1 << 16), //nolint:gosec
+		Seq:  rand.Intn(1 << 16), //nolint:gosec
+		Data: []byte("gluetun"),
+	}
+	request := icmp.Message{Type: requestType, Body: echo}
+	requestBytes, err := request.Marshal(nil)
+	if err != nil {
+		return fmt.Errorf("encoding echo request: %w", err)
+	}
+
+	_, err = connection.WriteTo(requestBytes, &net.IPAddr{IP: ip.AsSlice()})
+	if err != nil {
+		return fmt.Errorf("sending echo request: %w", wrapContextErr(ctx, err))
+	}
+
+	const maxPacketSize = 1500
+	buffer := make([]byte, maxPacketSize)
+	for {
+		n, _, err := connection.ReadFrom(buffer)
+		if err != nil {
+			return fmt.Errorf("receiving echo reply: %w", wrapContextErr(ctx, err))
+		}
+
+		reply, err := icmp.ParseMessage(protocol, buffer[:n])
+		if err != nil || reply.Type != replyType {
+			continue
+		}
+		replyEcho, ok := reply.Body.(*icmp.Echo)
+		if ok && replyEcho.ID == echo.ID && replyEcho.Seq == echo.Seq {
+			return nil
+		}
+	}
+}
+
+// wrapContextErr returns the context error wrapping the error
+// given if the context is done, since the context deadline and
+// cancellation are enforced using the connection deadline.
+func wrapContextErr(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("%w: %w", ctx.Err(), err)
+	}
+	return err
+}
@@ -3,5 +3,6 @@ package healthcheck
 type Logger interface {
 	Debug(s string)
 	Info(s string)
+	Warn(s string)
 	Error(s string)
 }
@@ -3,8 +3,6 @@ package healthcheck
 import (
 	"context"
 	"time"
-
-	"github.com/qdm12/gluetun/internal/constants"
 )
 
 type vpnHealth struct {
@@ -14,12 +12,28 @@ type vpnHealth struct {
 }
 
 func (s *Server) onUnhealthyVPN(ctx context.Context) {
+	if s.endpoints.isTerminal() {
+		return
+	}
+
+	if s.flapping.flapping {
+		s.logger.Warn("program has been unhealthy for " +
+			s.vpn.healthyWait.String() + " but health is flapping: holding off VPN restart")
+		s.vpn.healthyTimer = time.NewTimer(s.vpn.healthyWait)
+		return
+	}
+
 	s.logger.Info("program has been unhealthy for " +
 		s.vpn.healthyWait.String() + ": restarting VPN")
 	s.logger.Info("👉 See https://github.com/qdm12/gluetun-wiki/blob/main/faq/healthcheck.md")
 	s.logger.Info("DO NOT OPEN AN ISSUE UNLESS YOU READ AND TRIED EACH POSSIBLE SOLUTION")
-	_, _ = s.vpn.loop.ApplyStatus(ctx, constants.Stopped)
-	_, _ = s.vpn.loop.ApplyStatus(ctx, constants.Running)
+	s.vpn.loop.RecordConnectionFailure(s.handler.getErr())
+	terminal, _ := s.vpn.loop.EscalateUnhealthy(ctx)
+	if terminal {
+		s.endpoints.setTerminal(true)
+		return
+	}
+	s.metrics.UnhealthyRestartsInc()
 	s.vpn.healthyWait += *s.config.VPN.Addition
 	s.vpn.healthyTimer = time.NewTimer(s.vpn.healthyWait)
 }
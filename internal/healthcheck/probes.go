@@ -0,0 +1,197 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+var ErrQuorumNotReached = errors.New("health probes quorum not reached")
+
+type probeResult struct {
+	probe   settings.HealthProbe
+	latency time.Duration
+	err     error
+}
+
+// healthCheck runs all the health probes concurrently and returns
+// an error if fewer probes than the quorum succeed. It returns as
+// soon as the outcome is known, canceling the remaining probes.
+// The results of the probes completed are recorded in the history.
+func (s *Server) healthCheck(ctx context.Context) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	probes := s.config.Probes
+	quorum := int(*s.config.Quorum)
+	results := make(chan probeResult, len(probes))
+	for _, probe := range probes {
+		go func(probe settings.HealthProbe) {
+			start := time.Now()
+			err := s.runProbe(ctx, probe)
+			results <- probeResult{
+				probe:   probe,
+				latency: time.Since(start),
+				err:     err,
+			}
+		}(probe)
+	}
+
+	successes := 0
+	failures := make([]string, 0, len(probes))
+	for range probes {
+		result := <-results
+		s.recordProbeResult(result)
+		if result.err == nil {
+			successes++
+			if successes == quorum {
+				return nil
+			}
+			continue
+		}
+
+		failures = append(failures, result.probe.Type+" "+
+			result.probe.Target+": "+result.err.Error())
+		if len(probes)-len(failures) < quorum {
+			break
+		}
+	}
+
+	return fmt.Errorf("%w: %d of %d probes succeeded and %d are required: %s",
+		ErrQuorumNotReached, successes, len(probes), quorum,
+		strings.Join(failures, "; "))
+}
+
+func (s *Server) recordProbeResult(result probeResult) {
+	historyResult := models.HealthResult{
+		Time:    time.Now(),
+		Probe:   result.probe.String(),
+		Latency: result.latency,
+	}
+	if result.err != nil {
+		historyResult.Error = result.err.Error()
+	}
+	s.history.add(historyResult)
+}
+
+func (s *Server) runProbe(ctx context.Context,
+	probe settings.HealthProbe) (err error) {
+	if probe.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, probe.Timeout)
+		defer cancel()
+	}
+
+	switch probe.Type {
+	case constants.HealthProbeTCP:
+		return s.probeTCP(ctx, probe.Target)
+	case constants.HealthProbeHTTP:
+		return s.probeHTTP(ctx, probe.Target, probe.Status, probe.Body)
+	case constants.HealthProbeDNS:
+		return s.probeDNS(ctx, probe.Target)
+	case constants.HealthProbeICMP:
+		return s.probeICMP(ctx, probe.Target)
+	default:
+		panic(fmt.Sprintf("health probe type %q is not implemented", probe.Type))
+	}
+}
+
+func (s *Server) probeTCP(ctx context.Context, target string) (err error) {
+	// TODO use mullvad API if current provider is Mullvad
+
+	address, err := makeAddressToDial(target)
+	if err != nil {
+		return err
+	}
+
+	const dialNetwork = "tcp4"
+	connection, err := s.dialer.DialContext(ctx, dialNetwork, address)
+	if err != nil {
+		return fmt.Errorf("dialing: %w", err)
+	}
+
+	err = connection.Close()
+	if err != nil {
+		return fmt.Errorf("closing connection: %w", err)
+	}
+
+	return nil
+}
+
+func makeAddressToDial(address string) (addressToDial string, err error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		addrErr := new(net.AddrError)
+		ok := errors.As(err, &addrErr)
+		if !ok || addrErr.Err != "missing port in address" {
+			return "", fmt.Errorf("splitting host and port from address: %w", err)
+		}
+		host = address
+		const defaultPort = "443"
+		port = defaultPort
+	}
+	address = net.JoinHostPort(host, port)
+	return address, nil
+}
+
+var (
+	ErrHTTPStatusUnexpected = errors.New("HTTP response status is unexpected")
+	ErrHTTPBodyUnexpected   = errors.New("HTTP response body is unexpected")
+)
+
+func (s *Server) probeHTTP(ctx context.Context, url string,
+	expectedStatus int, expectedBody string) (err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != expectedStatus {
+		return fmt.Errorf("%w: %s instead of %d",
+			ErrHTTPStatusUnexpected, response.Status, expectedStatus)
+	}
+
+	if expectedBody == "" {
+		return nil
+	}
+
+	const maxBodySize = 1 << 20
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxBodySize))
+	if err != nil {
+		return fmt.Errorf("reading response body: %w", err)
+	}
+
+	if !strings.Contains(string(body), expectedBody) {
+		return fmt.Errorf("%w: it does not contain %q",
+			ErrHTTPBodyUnexpected, expectedBody)
+	}
+
+	return nil
+}
+
+func (s *Server) probeDNS(ctx context.Context, hostname string) (err error) {
+	addresses, err := s.dnsResolver.LookupHost(ctx, hostname)
+	if err != nil {
+		return fmt.Errorf("resolving: %w", err)
+	} else if len(addresses) == 0 {
+		return fmt.Errorf("resolving: %w", ErrDNSNoAddress)
+	}
+	return nil
+}
+
+var ErrDNSNoAddress = errors.New("no address found")
@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -24,8 +27,12 @@ func Test_Server_healthCheck(t *testing.T) {
 		server := &Server{
 			dialer: dialer,
 			config: settings.Health{
-				TargetAddress: address,
+				Probes: []settings.HealthProbe{
+					{Type: constants.HealthProbeTCP, Target: address},
+				},
+				Quorum: ptrTo(uint(1)),
 			},
+			history: newHistory(10),
 		}
 
 		canceledCtx, cancel := context.WithCancel(context.Background())
@@ -53,8 +60,12 @@ func Test_Server_healthCheck(t *testing.T) {
 		server := &Server{
 			dialer: dialer,
 			config: settings.Health{
-				TargetAddress: listeningAddress.String(),
+				Probes: []settings.HealthProbe{
+					{Type: constants.HealthProbeTCP, Target: listeningAddress.String()},
+				},
+				Quorum: ptrTo(uint(1)),
 			},
+			history: newHistory(10),
 		}
 
 		const timeout = 100 * time.Millisecond
@@ -65,6 +76,56 @@ func Test_Server_healthCheck(t *testing.T) {
 
 		assert.NoError(t, err)
 	})
+
+	t.Run("quorum", func(t *testing.T) {
+		t.Parallel()
+
+		httpServer := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte("status: ok"))
+			}))
+		t.Cleanup(httpServer.Close)
+
+		closedListener, err := net.Listen("tcp4", "localhost:0")
+		require.NoError(t, err)
+		closedAddress := closedListener.Addr().String()
+		err = closedListener.Close()
+		require.NoError(t, err)
+
+		probes := []settings.HealthProbe{
+			{Type: constants.HealthProbeHTTP, Target: httpServer.URL,
+				Status: http.StatusOK, Body: "ok"},
+			{Type: constants.HealthProbeHTTP, Target: httpServer.URL,
+				Status: http.StatusOK, Body: "not found"},
+			{Type: constants.HealthProbeTCP, Target: closedAddress,
+				Timeout: time.Second},
+		}
+
+		dialer := &net.Dialer{}
+		server := &Server{
+			dialer:     dialer,
+			httpClient: httpServer.Client(),
+			config: settings.Health{
+				Probes: probes,
+				Quorum: ptrTo(uint(1)),
+			},
+			history: newHistory(10),
+		}
+
+		const timeout = time.Second
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		err = server.healthCheck(ctx)
+		assert.NoError(t, err)
+
+		server.config.Quorum = ptrTo(uint(2))
+		err = server.healthCheck(ctx)
+		require.ErrorIs(t, err, ErrQuorumNotReached)
+		assert.Contains(t, err.Error(), "of 3 probes succeeded and 2 are required")
+		assert.Contains(t, err.Error(), `HTTP response body is unexpected: it does not contain "not found"`)
+		assert.Contains(t, err.Error(), "tcp "+closedAddress+": dialing:")
+	})
 }
 
 func Test_makeAddressToDial(t *testing.T) {
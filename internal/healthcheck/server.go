@@ -3,38 +3,109 @@ package healthcheck
 import (
 	"context"
 	"net"
+	"net/http"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/firewall"
 	"github.com/qdm12/gluetun/internal/models"
 )
 
 type Server struct {
-	logger  Logger
-	handler *handler
-	dialer  *net.Dialer
-	config  settings.Health
-	vpn     vpnHealth
+	logger      Logger
+	handler     *handler
+	dialer      *net.Dialer
+	httpClient  *http.Client
+	dnsResolver *net.Resolver
+	config      settings.Health
+	vpn         vpnHealth
+	history     *history
+	flapping    *flapDetector
+	endpoints   *statusEndpoints
+	metrics     Metrics
+	events      EventPublisher
 }
 
+// NewServer creates a health server. The firewall should be nil if
+// the firewall is disabled in the settings, and the port forwarder
+// is only used if a forwarded port is required to be ready.
 func NewServer(config settings.Health,
-	logger Logger, vpnLoop StatusApplier) *Server {
+	logger Logger, vpnLoop StatusApplier, dnsLoop DNSLoop,
+	firewall Firewall, portForward PortForwarder, metrics Metrics,
+	eventPublisher EventPublisher) *Server {
+	const historySize = 1000
+	history := newHistory(historySize)
+	if !*config.ReadyPortForwarded {
+		portForward = nil
+	}
+	endpoints := newStatusEndpoints(vpnLoop, dnsLoop, firewall,
+		portForward, *config.VPN.Initial, config.SuccessWait)
+	dialer := &net.Dialer{
+		Resolver: &net.Resolver{
+			PreferGo: true,
+		},
+	}
 	return &Server{
 		logger:  logger,
-		handler: newHandler(),
-		dialer: &net.Dialer{
-			Resolver: &net.Resolver{
-				PreferGo: true,
+		handler: newHandler(history, endpoints),
+		dialer:  dialer,
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				DialContext: dialer.DialContext,
 			},
 		},
-		config: config,
+		dnsResolver: newInternalDNSResolver(dialer),
+		config:      config,
 		vpn: vpnHealth{
 			loop:        vpnLoop,
 			healthyWait: *config.VPN.Initial,
 		},
+		history:   history,
+		flapping:  newFlapDetector(config.FlappingWindow, *config.FlappingTransitions),
+		endpoints: endpoints,
+		metrics:   metrics,
+		events:    eventPublisher,
 	}
 }
 
 type StatusApplier interface {
-	ApplyStatus(ctx context.Context, status models.LoopStatus) (
-		outcome string, err error)
+	EscalateUnhealthy(ctx context.Context) (terminal bool, err error)
+	RecordConnectionFailure(err error)
+	RecordConnectionHealthy()
+	GetInterface() (vpnInterface string)
+	GetStatus() (status models.LoopStatus)
+}
+
+type DNSLoop interface {
+	GetStatus() (status models.LoopStatus)
+	GetSettings() (settings settings.DNS)
+}
+
+type Firewall interface {
+	GetState() (state firewall.State)
+}
+
+type PortForwarder interface {
+	GetPortsForwarded() (ports []uint16)
+}
+
+// newInternalDNSResolver returns a resolver using the
+// internal DNS server listening on 127.0.0.1:53.
+func newInternalDNSResolver(dialer *net.Dialer) *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			const internalDNSAddress = "127.0.0.1:53"
+			return dialer.DialContext(ctx, network, internalDNSAddress)
+		},
+	}
+}
+
+type EventPublisher interface {
+	Publish(eventType string, data any)
+}
+
+type Metrics interface {
+	HealthCheckObserve(duration time.Duration, err error)
+	UnhealthyRestartsInc()
 }
@@ -2,43 +2,85 @@ package httpproxy
 
 import (
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 )
 
-func (h *handler) isAuthorized(responseWriter http.ResponseWriter, request *http.Request) (authorized bool) {
-	if h.username == "" || (request.Method != "CONNECT" && !request.URL.IsAbs()) {
-		return true
+// isAuthorized checks the request credentials and returns the route
+// to use for the request if it is authorized. Requests with the
+// credentials of a tunnel user use the tunnel route, and all other
+// authorized requests use the main route.
+func (h *handler) isAuthorized(responseWriter http.ResponseWriter,
+	request *http.Request) (route *route, authorized bool) {
+	if request.Method != "CONNECT" && !request.URL.IsAbs() {
+		return h.mainRoute, true
 	}
-	basicAuth := request.Header.Get("Proxy-Authorization")
-	if basicAuth == "" {
+
+	username, password, err := parseProxyAuthorization(request.Header.Get("Proxy-Authorization"))
+	if err == nil {
+		tunnelRoute, ok := h.tunnelRoutes[username]
+		switch {
+		case ok && tunnelRoute.password == password:
+			return tunnelRoute, true
+		case ok:
+			h.logger.Info(fmt.Sprintf("Password mismatch for tunnel user %q from %s",
+				username, request.RemoteAddr))
+			responseWriter.WriteHeader(http.StatusUnauthorized)
+			return nil, false
+		}
+	}
+
+	if h.username == "" {
+		return h.mainRoute, true
+	}
+
+	switch {
+	case errors.Is(err, errProxyAuthorizationMissing):
 		h.logger.Info("Proxy-Authorization header not found from " + request.RemoteAddr)
 		responseWriter.Header().Set("Proxy-Authenticate", `Basic realm="Access to Gluetun over HTTP"`)
 		responseWriter.WriteHeader(http.StatusProxyAuthRequired)
-		return false
+		return nil, false
+	case errors.Is(err, errProxyAuthorizationMalformed):
+		responseWriter.WriteHeader(http.StatusBadRequest)
+		return nil, false
+	case err != nil:
+		h.logger.Info("Cannot decode Proxy-Authorization header value from " +
+			request.RemoteAddr + ": " + err.Error())
+		responseWriter.WriteHeader(http.StatusUnauthorized)
+		return nil, false
+	}
+
+	if h.username != username || h.password != password {
+		h.logger.Info(fmt.Sprintf("Username (%q) or password (%q) mismatch from %s",
+			username, password, request.RemoteAddr))
+		h.logger.Debug("username provided \"" + username +
+			"\" and password provided \"" + password + "\"")
+		responseWriter.WriteHeader(http.StatusUnauthorized)
+		return nil, false
+	}
+	return h.mainRoute, true
+}
+
+var (
+	errProxyAuthorizationMissing   = errors.New("proxy authorization header not found")
+	errProxyAuthorizationMalformed = errors.New("proxy authorization header value is malformed")
+)
+
+func parseProxyAuthorization(basicAuth string) (username, password string, err error) {
+	if basicAuth == "" {
+		return "", "", fmt.Errorf("%w", errProxyAuthorizationMissing)
 	}
 	b64UsernamePassword := strings.TrimPrefix(basicAuth, "Basic ")
 	b, err := base64.StdEncoding.DecodeString(b64UsernamePassword)
 	if err != nil {
-		h.logger.Info("Cannot decode Proxy-Authorization header value from " +
-			request.RemoteAddr + ": " + err.Error())
-		responseWriter.WriteHeader(http.StatusUnauthorized)
-		return false
+		return "", "", err
 	}
 	usernamePassword := strings.Split(string(b), ":")
 	const expectedFields = 2
 	if len(usernamePassword) != expectedFields {
-		responseWriter.WriteHeader(http.StatusBadRequest)
-		return false
-	}
-	if h.username != usernamePassword[0] || h.password != usernamePassword[1] {
-		h.logger.Info(fmt.Sprintf("Username (%q) or password (%q) mismatch from %s",
-			usernamePassword[0], usernamePassword[1], request.RemoteAddr))
-		h.logger.Debug("username provided \"" + usernamePassword[0] +
-			"\" and password provided \"" + usernamePassword[1] + "\"")
-		responseWriter.WriteHeader(http.StatusUnauthorized)
-		return false
+		return "", "", fmt.Errorf("%w", errProxyAuthorizationMalformed)
 	}
-	return true
+	return usernamePassword[0], usernamePassword[1], nil
 }
@@ -0,0 +1,116 @@
+package httpproxy
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_handler_isAuthorized(t *testing.T) {
+	t.Parallel()
+
+	makeHeader := func(username, password string) string {
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+	}
+
+	testCases := map[string]struct {
+		username     string
+		password     string
+		header       string
+		logInfo      bool
+		authorized   bool
+		route        string
+		responseCode int
+	}{
+		"no_credentials_required": {
+			authorized:   true,
+			route:        "main",
+			responseCode: http.StatusOK,
+		},
+		"no_credentials_required_with_unknown_credentials": {
+			header:       makeHeader("unknown", "x"),
+			authorized:   true,
+			route:        "main",
+			responseCode: http.StatusOK,
+		},
+		"tunnel_user": {
+			username:     "user",
+			password:     "pass",
+			header:       makeHeader("tunnel", "secret"),
+			authorized:   true,
+			route:        "tunnel",
+			responseCode: http.StatusOK,
+		},
+		"tunnel_user_wrong_password": {
+			header:       makeHeader("tunnel", "wrong"),
+			logInfo:      true,
+			responseCode: http.StatusUnauthorized,
+		},
+		"main_user": {
+			username:     "user",
+			password:     "pass",
+			header:       makeHeader("user", "pass"),
+			authorized:   true,
+			route:        "main",
+			responseCode: http.StatusOK,
+		},
+		"missing_credentials": {
+			username:     "user",
+			password:     "pass",
+			logInfo:      true,
+			responseCode: http.StatusProxyAuthRequired,
+		},
+		"malformed_credentials": {
+			username:     "user",
+			password:     "pass",
+			header:       "Basic " + base64.StdEncoding.EncodeToString([]byte("user")),
+			responseCode: http.StatusBadRequest,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			logger := NewMockLogger(ctrl)
+			if testCase.logInfo {
+				logger.EXPECT().Info(gomock.Any())
+			}
+
+			tunnelUser := TunnelUser{
+				Username:     "tunnel",
+				Password:     "secret",
+				LocalAddress: netip.MustParseAddr("10.2.0.2"),
+			}
+			h := newHandler(context.Background(), nil, logger, false, false,
+				testCase.username, testCase.password, []TunnelUser{tunnelUser}).(*handler)
+
+			request := httptest.NewRequest(http.MethodConnect, "http://example.com:443", nil)
+			if testCase.header != "" {
+				request.Header.Set("Proxy-Authorization", testCase.header)
+			}
+			recorder := httptest.NewRecorder()
+
+			route, authorized := h.isAuthorized(recorder, request)
+
+			assert.Equal(t, testCase.authorized, authorized)
+			assert.Equal(t, testCase.responseCode, recorder.Code)
+			switch testCase.route {
+			case "tunnel":
+				assert.Same(t, h.tunnelRoutes["tunnel"], route)
+			case "main":
+				assert.Same(t, h.mainRoute, route)
+			default:
+				assert.Nil(t, route)
+			}
+		})
+	}
+}
@@ -4,30 +4,34 @@ import (
 	"context"
 	"net/http"
 	"sync"
-	"time"
 )
 
 func newHandler(ctx context.Context, wg *sync.WaitGroup, logger Logger,
-	stealth, verbose bool, username, password string) http.Handler {
-	const httpTimeout = 24 * time.Hour
+	stealth, verbose bool, username, password string,
+	tunnelUsers []TunnelUser) http.Handler {
+	tunnelRoutes := make(map[string]*route, len(tunnelUsers))
+	for _, user := range tunnelUsers {
+		tunnelRoutes[user.Username] = newTunnelRoute(user)
+	}
 	return &handler{
-		ctx: ctx,
-		wg:  wg,
-		client: &http.Client{
-			Timeout:       httpTimeout,
-			CheckRedirect: returnRedirect},
-		logger:   logger,
-		verbose:  verbose,
-		stealth:  stealth,
-		username: username,
-		password: password,
+		ctx:          ctx,
+		wg:           wg,
+		mainRoute:    newMainRoute(),
+		tunnelRoutes: tunnelRoutes,
+		logger:       logger,
+		verbose:      verbose,
+		stealth:      stealth,
+		username:     username,
+		password:     password,
 	}
 }
 
 type handler struct {
-	ctx                context.Context //nolint:containedctx
-	wg                 *sync.WaitGroup
-	client             *http.Client
+	ctx       context.Context //nolint:containedctx
+	wg        *sync.WaitGroup
+	mainRoute *route
+	// tunnelRoutes maps tunnel usernames to their route.
+	tunnelRoutes       map[string]*route
 	logger             Logger
 	verbose, stealth   bool
 	username, password string
@@ -37,7 +41,8 @@ func (h *handler) ServeHTTP(responseWriter http.ResponseWriter, request *http.Re
 	if !h.isAccepted(responseWriter, request) {
 		return
 	}
-	if !h.isAuthorized(responseWriter, request) {
+	route, authorized := h.isAuthorized(responseWriter, request)
+	if !authorized {
 		return
 	}
 	request.Header.Del("Proxy-Connection")
@@ -45,9 +50,9 @@ func (h *handler) ServeHTTP(responseWriter http.ResponseWriter, request *http.Re
 	request.Header.Del("Proxy-Authorization")
 	switch request.Method {
 	case http.MethodConnect:
-		h.handleHTTPS(responseWriter, request)
+		h.handleHTTPS(responseWriter, request, route.dialer)
 	default:
-		h.handleHTTP(responseWriter, request)
+		h.handleHTTP(responseWriter, request, route.client)
 	}
 }
 
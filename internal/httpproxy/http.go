@@ -8,7 +8,8 @@ import (
 	"strings"
 )
 
-func (h *handler) handleHTTP(responseWriter http.ResponseWriter, request *http.Request) {
+func (h *handler) handleHTTP(responseWriter http.ResponseWriter, request *http.Request,
+	client *http.Client) {
 	switch request.URL.Scheme {
 	case "http", "https":
 	default:
@@ -29,7 +30,7 @@ func (h *handler) handleHTTP(responseWriter http.ResponseWriter, request *http.R
 		setForwardedHeaders(request)
 	}
 
-	response, err := h.client.Do(request)
+	response, err := client.Do(request)
 	if err != nil {
 		http.Error(responseWriter, "server error", http.StatusInternalServerError)
 		h.logger.Warn("cannot process request for client " + request.RemoteAddr + ": " + err.Error())
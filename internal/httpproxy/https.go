@@ -6,8 +6,8 @@ import (
 	"net/http"
 )
 
-func (h *handler) handleHTTPS(responseWriter http.ResponseWriter, request *http.Request) {
-	dialer := net.Dialer{}
+func (h *handler) handleHTTPS(responseWriter http.ResponseWriter, request *http.Request,
+	dialer *net.Dialer) {
 	destinationConn, err := dialer.DialContext(h.ctx, "tcp", request.Host)
 	if err != nil {
 		http.Error(responseWriter, err.Error(), http.StatusServiceUnavailable)
@@ -14,6 +14,8 @@ import (
 type Loop struct {
 	statusManager *loopstate.State
 	state         *state.State
+	// Fixed settings
+	tunnelUsers []TunnelUser
 	// Other objects
 	logger Logger
 	// Internal channels and locks
@@ -26,19 +28,21 @@ type Loop struct {
 
 const defaultBackoffTime = 10 * time.Second
 
-func NewLoop(logger Logger, settings settings.HTTPProxy) *Loop {
+func NewLoop(logger Logger, settings settings.HTTPProxy,
+	tunnelUsers []TunnelUser) *Loop {
 	start := make(chan struct{})
 	running := make(chan models.LoopStatus)
 	stop := make(chan struct{})
 	stopped := make(chan struct{})
 
 	statusManager := loopstate.New(constants.Stopped,
-		start, running, stop, stopped)
+		start, running, stop, stopped, nil)
 	state := state.New(statusManager, settings)
 
 	return &Loop{
 		statusManager: statusManager,
 		state:         state,
+		tunnelUsers:   tunnelUsers,
 		logger:        logger,
 		start:         start,
 		running:       running,
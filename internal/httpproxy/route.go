@@ -0,0 +1,50 @@
+package httpproxy
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"time"
+)
+
+// TunnelUser is an HTTP proxy user whose proxied traffic
+// goes through an additional VPN tunnel.
+type TunnelUser struct {
+	Username string
+	Password string
+	// LocalAddress is the address of the tunnel interface to dial
+	// from, such that the traffic is routed through the tunnel by
+	// the policy rule matching this source address.
+	LocalAddress netip.Addr
+}
+
+// route contains the dialer and client to use to reach
+// the destination of a proxied request.
+type route struct {
+	// password is the password of the tunnel user,
+	// and is empty for the main route.
+	password string
+	dialer   *net.Dialer
+	client   *http.Client
+}
+
+func newMainRoute() *route {
+	const httpTimeout = 24 * time.Hour
+	return &route{
+		dialer: &net.Dialer{},
+		client: &http.Client{
+			Timeout:       httpTimeout,
+			CheckRedirect: returnRedirect,
+		},
+	}
+}
+
+func newTunnelRoute(user TunnelUser) *route {
+	route := newMainRoute()
+	route.password = user.Password
+	route.dialer.LocalAddr = &net.TCPAddr{IP: user.LocalAddress.AsSlice()}
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	transport.DialContext = route.dialer.DialContext
+	route.client.Transport = transport
+	return route
+}
@@ -17,12 +17,13 @@ type Server struct {
 }
 
 func New(ctx context.Context, address string, logger Logger,
-	stealth, verbose bool, username, password string,
+	stealth, verbose bool, username, password string, tunnelUsers []TunnelUser,
 	readHeaderTimeout, readTimeout time.Duration) *Server {
 	wg := &sync.WaitGroup{}
 	return &Server{
-		address:           address,
-		handler:           newHandler(ctx, wg, logger, stealth, verbose, username, password),
+		address: address,
+		handler: newHandler(ctx, wg, logger, stealth, verbose,
+			username, password, tunnelUsers),
 		logger:            logger,
 		internalWG:        wg,
 		readHeaderTimeout: readHeaderTimeout,
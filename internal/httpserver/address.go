@@ -1,5 +1,16 @@
 package httpserver
 
+import "strings"
+
+const unixSocketPrefix = "unix://"
+
+// UnixSocketPath returns the unix socket file path and true if
+// the address given is prefixed with unix://, and returns
+// an empty string and false otherwise.
+func UnixSocketPath(address string) (path string, ok bool) {
+	return strings.CutPrefix(address, unixSocketPrefix)
+}
+
 // GetAddress obtains the address the HTTP server is listening on.
 func (s *Server) GetAddress() (address string) {
 	<-s.addressSet
@@ -37,3 +37,5 @@ func newRegexMatcher(regex string) *regexMatcher {
 		regexp: regexp.MustCompile(regex),
 	}
 }
+
+func ptrTo[T any](value T) *T { return &value }
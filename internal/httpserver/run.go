@@ -2,9 +2,12 @@ package httpserver
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
+	"fmt"
 	"net"
 	"net/http"
+	"os"
 )
 
 // Run runs the HTTP server until ctx is canceled.
@@ -37,7 +40,7 @@ func (s *Server) Run(ctx context.Context, ready chan<- struct{}, done chan<- str
 		}
 	}()
 
-	listener, err := net.Listen("tcp", s.address)
+	listener, err := s.listen()
 	if err != nil {
 		close(s.addressSet)
 		close(crashed) // stop shutdown goroutine
@@ -47,9 +50,15 @@ func (s *Server) Run(ctx context.Context, ready chan<- struct{}, done chan<- str
 		return
 	}
 
-	s.address = listener.Addr().String()
+	if _, isUnix := UnixSocketPath(s.address); !isUnix {
+		s.address = listener.Addr().String()
+	}
 	close(s.addressSet)
 
+	if s.tlsConfig != nil {
+		listener = tls.NewListener(listener, s.tlsConfig)
+	}
+
 	// note: no further write so no need to mutex
 	s.logger.Info("http server listening on " + s.address)
 	close(ready)
@@ -68,3 +77,51 @@ func (s *Server) Run(ctx context.Context, ready chan<- struct{}, done chan<- str
 	}
 	close(done)
 }
+
+var ErrUnixSocketPathNotSocket = errors.New("unix socket path exists and is not a socket")
+
+// listen listens on the unix socket path if the address is
+// prefixed with unix://, and on the TCP address otherwise.
+func (s *Server) listen() (listener net.Listener, err error) {
+	socketPath, isUnix := UnixSocketPath(s.address)
+	if !isUnix {
+		return net.Listen("tcp", s.address)
+	}
+
+	// Remove any socket file left over from a previous run,
+	// which would otherwise make listening fail.
+	fileInfo, err := os.Lstat(socketPath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("checking existing unix socket file: %w", err)
+	case fileInfo.Mode()&os.ModeSocket == 0:
+		return nil, fmt.Errorf("%w: %s", ErrUnixSocketPathNotSocket, socketPath)
+	default:
+		err = os.Remove(socketPath)
+		if err != nil {
+			return nil, fmt.Errorf("removing existing unix socket file: %w", err)
+		}
+	}
+
+	listener, err = net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.Chmod(socketPath, s.unixSocketMode)
+	if err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("setting unix socket file mode: %w", err)
+	}
+
+	if s.unixSocketUID != -1 || s.unixSocketGID != -1 {
+		err = os.Chown(socketPath, s.unixSocketUID, s.unixSocketGID)
+		if err != nil {
+			_ = listener.Close()
+			return nil, fmt.Errorf("setting unix socket file owner: %w", err)
+		}
+	}
+
+	return listener, nil
+}
@@ -2,12 +2,17 @@ package httpserver
 
 import (
 	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
 	"regexp"
 	"testing"
 	"time"
 
 	gomock "github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_Server_Run_success(t *testing.T) {
@@ -70,3 +75,58 @@ func Test_Server_Run_failure(t *testing.T) {
 		assert.False(t, ok)
 	}
 }
+
+func Test_Server_Run_unixSocket(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	socketPath := filepath.Join(t.TempDir(), "gluetun.sock")
+	address := "unix://" + socketPath
+
+	logger := NewMockLogger(ctrl)
+	logger.EXPECT().Info("http server listening on " + address)
+
+	server := &Server{
+		address:         address,
+		unixSocketMode:  0o600,
+		unixSocketUID:   -1,
+		unixSocketGID:   -1,
+		addressSet:      make(chan struct{}),
+		handler:         http.NotFoundHandler(),
+		logger:          logger,
+		shutdownTimeout: 10 * time.Second,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready := make(chan struct{})
+	done := make(chan struct{})
+
+	go server.Run(ctx, ready, done)
+
+	<-ready
+	assert.Equal(t, address, server.GetAddress())
+
+	fileInfo, err := os.Stat(socketPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.ModeSocket|0o600, fileInfo.Mode())
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				dialer := net.Dialer{}
+				return dialer.DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://unix/", nil)
+	require.NoError(t, err)
+	response, err := client.Do(request)
+	require.NoError(t, err)
+	_ = response.Body.Close()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	cancel()
+	_, ok := <-done
+	assert.False(t, ok)
+}
@@ -1,8 +1,10 @@
 package httpserver
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net/http"
+	"os"
 	"time"
 )
 
@@ -10,6 +12,10 @@ import (
 // the HTTP handler provided.
 type Server struct {
 	address           string
+	unixSocketMode    os.FileMode
+	unixSocketUID     int
+	unixSocketGID     int
+	tlsConfig         *tls.Config
 	addressSet        chan struct{}
 	handler           http.Handler
 	logger            Logger
@@ -27,8 +33,17 @@ func New(settings Settings) (s *Server, err error) {
 		return nil, fmt.Errorf("http server settings validation failed: %w", err)
 	}
 
+	tlsConfig, err := newTLSConfig(settings)
+	if err != nil {
+		return nil, fmt.Errorf("setting up TLS: %w", err)
+	}
+
 	return &Server{
 		address:           settings.Address,
+		unixSocketMode:    settings.UnixSocketMode,
+		unixSocketUID:     ptrToOwnerID(settings.UnixSocketUID),
+		unixSocketGID:     ptrToOwnerID(settings.UnixSocketGID),
+		tlsConfig:         tlsConfig,
 		addressSet:        make(chan struct{}),
 		handler:           settings.Handler,
 		logger:            settings.Logger,
@@ -37,3 +52,12 @@ func New(settings Settings) (s *Server, err error) {
 		shutdownTimeout:   settings.ShutdownTimeout,
 	}, nil
 }
+
+// ptrToOwnerID returns -1 if the pointer is nil, such that
+// the owner id is left unchanged by os.Chown.
+func ptrToOwnerID(id *int) int {
+	if id == nil {
+		return -1
+	}
+	return *id
+}
@@ -38,6 +38,9 @@ func Test_New(t *testing.T) {
 			},
 			expected: &Server{
 				address:           ":8001",
+				unixSocketMode:    0o660,
+				unixSocketUID:     -1,
+				unixSocketGID:     -1,
 				handler:           someHandler,
 				logger:            someLogger,
 				readHeaderTimeout: time.Second,
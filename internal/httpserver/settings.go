@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/qdm12/gosettings"
@@ -14,27 +15,58 @@ import (
 
 type Settings struct {
 	// Address is the server listening address.
+	// It can be a TCP address such as :8000 or a unix socket
+	// path prefixed with unix://, for example unix:///tmp/gluetun.sock.
 	// It defaults to :8000.
-	Address string
+	Address string `json:"address"`
+	// UnixSocketMode is the file mode to set on the unix socket
+	// file, and is only used if the address is a unix socket.
+	// It defaults to 0660.
+	UnixSocketMode os.FileMode `json:"unix_socket_mode"`
+	// UnixSocketUID is the user id to set as owner of the unix
+	// socket file, and is only used if the address is a unix socket.
+	// It defaults to nil, which leaves the owner unchanged.
+	UnixSocketUID *int `json:"unix_socket_uid"`
+	// UnixSocketGID is the group id to set as owner of the unix
+	// socket file, and is only used if the address is a unix socket.
+	// It defaults to nil, which leaves the group unchanged.
+	UnixSocketGID *int `json:"unix_socket_gid"`
+	// TLSCertificateFilepath is the path to the PEM encoded TLS
+	// certificate file. If set, TLSKeyFilepath must also be set
+	// and the server serves HTTPS. It defaults to the empty string,
+	// in which case the server serves plaintext HTTP.
+	TLSCertificateFilepath string `json:"tls_certificate_filepath"`
+	// TLSKeyFilepath is the path to the PEM encoded TLS private
+	// key file, and must be set if TLSCertificateFilepath is set.
+	TLSKeyFilepath string `json:"tls_key_filepath"`
+	// TLSClientCAFilepath is the path to a PEM encoded certificate
+	// authority file used to verify client certificates. Client
+	// certificates are verified if given, and it is up to the
+	// handler to reject requests without a verified certificate.
+	// It defaults to the empty string, in which case client
+	// certificates are not requested.
+	TLSClientCAFilepath string `json:"tls_client_ca_filepath"`
 	// Handler is the HTTP Handler to use.
 	// It must be set and cannot be left to nil.
-	Handler http.Handler
+	Handler http.Handler `json:"-"`
 	// Logger is the logger to use.
 	// It must be set and cannot be left to nil.
-	Logger Logger
+	Logger Logger `json:"-"`
 	// ReadHeaderTimeout is the HTTP header read timeout duration
 	// of the HTTP server. It defaults to 3 seconds if left unset.
-	ReadHeaderTimeout time.Duration
+	ReadHeaderTimeout time.Duration `json:"read_header_timeout"`
 	// ReadTimeout is the HTTP read timeout duration
 	// of the HTTP server. It defaults to 3 seconds if left unset.
-	ReadTimeout time.Duration
+	ReadTimeout time.Duration `json:"read_timeout"`
 	// ShutdownTimeout is the shutdown timeout duration
 	// of the HTTP server. It defaults to 3 seconds if left unset.
-	ShutdownTimeout time.Duration
+	ShutdownTimeout time.Duration `json:"shutdown_timeout"`
 }
 
 func (s *Settings) SetDefaults() {
 	s.Address = gosettings.DefaultComparable(s.Address, ":8000")
+	const defaultUnixSocketMode os.FileMode = 0o660
+	s.UnixSocketMode = gosettings.DefaultComparable(s.UnixSocketMode, defaultUnixSocketMode)
 	const defaultReadTimeout = 3 * time.Second
 	s.ReadHeaderTimeout = gosettings.DefaultComparable(s.ReadHeaderTimeout, defaultReadTimeout)
 	s.ReadTimeout = gosettings.DefaultComparable(s.ReadTimeout, defaultReadTimeout)
@@ -44,17 +76,30 @@ func (s *Settings) SetDefaults() {
 
 func (s Settings) Copy() Settings {
 	return Settings{
-		Address:           s.Address,
-		Handler:           s.Handler,
-		Logger:            s.Logger,
-		ReadHeaderTimeout: s.ReadHeaderTimeout,
-		ReadTimeout:       s.ReadTimeout,
-		ShutdownTimeout:   s.ShutdownTimeout,
+		Address:                s.Address,
+		UnixSocketMode:         s.UnixSocketMode,
+		UnixSocketUID:          gosettings.CopyPointer(s.UnixSocketUID),
+		UnixSocketGID:          gosettings.CopyPointer(s.UnixSocketGID),
+		TLSCertificateFilepath: s.TLSCertificateFilepath,
+		TLSKeyFilepath:         s.TLSKeyFilepath,
+		TLSClientCAFilepath:    s.TLSClientCAFilepath,
+		Handler:                s.Handler,
+		Logger:                 s.Logger,
+		ReadHeaderTimeout:      s.ReadHeaderTimeout,
+		ReadTimeout:            s.ReadTimeout,
+		ShutdownTimeout:        s.ShutdownTimeout,
 	}
 }
 
 func (s *Settings) OverrideWith(other Settings) {
 	s.Address = gosettings.OverrideWithComparable(s.Address, other.Address)
+	s.UnixSocketMode = gosettings.OverrideWithComparable(s.UnixSocketMode, other.UnixSocketMode)
+	s.UnixSocketUID = gosettings.OverrideWithPointer(s.UnixSocketUID, other.UnixSocketUID)
+	s.UnixSocketGID = gosettings.OverrideWithPointer(s.UnixSocketGID, other.UnixSocketGID)
+	s.TLSCertificateFilepath = gosettings.OverrideWithComparable(s.TLSCertificateFilepath,
+		other.TLSCertificateFilepath)
+	s.TLSKeyFilepath = gosettings.OverrideWithComparable(s.TLSKeyFilepath, other.TLSKeyFilepath)
+	s.TLSClientCAFilepath = gosettings.OverrideWithComparable(s.TLSClientCAFilepath, other.TLSClientCAFilepath)
 	s.Handler = gosettings.OverrideWithComparable(s.Handler, other.Handler)
 	if other.Logger != nil {
 		s.Logger = other.Logger
@@ -65,17 +110,39 @@ func (s *Settings) OverrideWith(other Settings) {
 }
 
 var (
-	ErrHandlerIsNotSet           = errors.New("HTTP handler cannot be left unset")
-	ErrLoggerIsNotSet            = errors.New("logger cannot be left unset")
-	ErrReadHeaderTimeoutTooSmall = errors.New("read header timeout is too small")
-	ErrReadTimeoutTooSmall       = errors.New("read timeout is too small")
-	ErrShutdownTimeoutTooSmall   = errors.New("shutdown timeout is too small")
+	ErrUnixSocketPathNotAbsolute    = errors.New("unix socket path is not absolute")
+	ErrUnixSocketModeNotValid       = errors.New("unix socket file mode is not valid")
+	ErrTLSKeyFilepathNotSet         = errors.New("TLS key filepath is not set")
+	ErrTLSCertificateFilepathNotSet = errors.New("TLS certificate filepath is not set")
+	ErrHandlerIsNotSet              = errors.New("HTTP handler cannot be left unset")
+	ErrLoggerIsNotSet               = errors.New("logger cannot be left unset")
+	ErrReadHeaderTimeoutTooSmall    = errors.New("read header timeout is too small")
+	ErrReadTimeoutTooSmall          = errors.New("read timeout is too small")
+	ErrShutdownTimeoutTooSmall      = errors.New("shutdown timeout is too small")
 )
 
 func (s Settings) Validate() (err error) {
-	err = validate.ListeningAddress(s.Address, os.Getuid())
-	if err != nil {
-		return err
+	socketPath, isUnix := UnixSocketPath(s.Address)
+	if isUnix {
+		if !filepath.IsAbs(socketPath) {
+			return fmt.Errorf("%w: %s", ErrUnixSocketPathNotAbsolute, socketPath)
+		}
+		if s.UnixSocketMode&^os.ModePerm != 0 {
+			return fmt.Errorf("%w: %o", ErrUnixSocketModeNotValid, s.UnixSocketMode)
+		}
+	} else {
+		err = validate.ListeningAddress(s.Address, os.Getuid())
+		if err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case s.TLSCertificateFilepath != "" && s.TLSKeyFilepath == "":
+		return fmt.Errorf("%w", ErrTLSKeyFilepathNotSet)
+	case s.TLSCertificateFilepath == "" &&
+		(s.TLSKeyFilepath != "" || s.TLSClientCAFilepath != ""):
+		return fmt.Errorf("%w", ErrTLSCertificateFilepathNotSet)
 	}
 
 	if s.Handler == nil {
@@ -112,6 +179,23 @@ func (s Settings) Validate() (err error) {
 func (s Settings) ToLinesNode() (node *gotree.Node) {
 	node = gotree.New("HTTP server settings:")
 	node.Appendf("Listening address: %s", s.Address)
+	if _, isUnix := UnixSocketPath(s.Address); isUnix {
+		node.Appendf("Unix socket file mode: %04o", s.UnixSocketMode)
+		if s.UnixSocketUID != nil {
+			node.Appendf("Unix socket owner user id: %d", *s.UnixSocketUID)
+		}
+		if s.UnixSocketGID != nil {
+			node.Appendf("Unix socket owner group id: %d", *s.UnixSocketGID)
+		}
+	}
+	if s.TLSCertificateFilepath != "" {
+		tlsNode := node.Appendf("TLS:")
+		tlsNode.Appendf("Certificate filepath: %s", s.TLSCertificateFilepath)
+		tlsNode.Appendf("Key filepath: %s", s.TLSKeyFilepath)
+		if s.TLSClientCAFilepath != "" {
+			tlsNode.Appendf("Client certificate authority filepath: %s", s.TLSClientCAFilepath)
+		}
+	}
 	node.Appendf("Read header timeout: %s", s.ReadHeaderTimeout)
 	node.Appendf("Read timeout: %s", s.ReadTimeout)
 	node.Appendf("Shutdown timeout: %s", s.ShutdownTimeout)
@@ -2,6 +2,7 @@ package httpserver
 
 import (
 	"net/http"
+	"os"
 	"testing"
 	"time"
 
@@ -22,6 +23,7 @@ func Test_Settings_SetDefaults(t *testing.T) {
 			settings: Settings{},
 			expected: Settings{
 				Address:           ":8000",
+				UnixSocketMode:    0o660,
 				ReadHeaderTimeout: defaultTimeout,
 				ReadTimeout:       defaultTimeout,
 				ShutdownTimeout:   defaultTimeout,
@@ -36,6 +38,7 @@ func Test_Settings_SetDefaults(t *testing.T) {
 			},
 			expected: Settings{
 				Address:           ":8001",
+				UnixSocketMode:    0o660,
 				ReadHeaderTimeout: time.Second,
 				ReadTimeout:       time.Second,
 				ShutdownTimeout:   time.Second,
@@ -202,6 +205,21 @@ func Test_Settings_Validate(t *testing.T) {
 			errWrapped: validate.ErrPortNotAnInteger,
 			errMessage: "port value is not an integer: notanint",
 		},
+		"relative_unix_socket_path": {
+			settings: Settings{
+				Address: "unix://gluetun.sock",
+			},
+			errWrapped: ErrUnixSocketPathNotAbsolute,
+			errMessage: "unix socket path is not absolute: gluetun.sock",
+		},
+		"unix_socket_mode_not_valid": {
+			settings: Settings{
+				Address:        "unix:///tmp/gluetun.sock",
+				UnixSocketMode: os.ModeDir | 0o660,
+			},
+			errWrapped: ErrUnixSocketModeNotValid,
+			errMessage: "unix socket file mode is not valid: 20000000660",
+		},
 		"nil handler": {
 			settings: Settings{
 				Address: ":8000",
@@ -295,6 +313,23 @@ func Test_Settings_String(t *testing.T) {
 ├── Listening address: :8000
 ├── Read header timeout: 1ms
 ├── Read timeout: 1ms
+└── Shutdown timeout: 1s`,
+		},
+		"unix socket": {
+			settings: Settings{
+				Address:           "unix:///tmp/gluetun.sock",
+				UnixSocketMode:    0o660,
+				UnixSocketUID:     ptrTo(1000),
+				ReadHeaderTimeout: time.Millisecond,
+				ReadTimeout:       time.Millisecond,
+				ShutdownTimeout:   time.Second,
+			},
+			s: `HTTP server settings:
+├── Listening address: unix:///tmp/gluetun.sock
+├── Unix socket file mode: 0660
+├── Unix socket owner user id: 1000
+├── Read header timeout: 1ms
+├── Read timeout: 1ms
 └── Shutdown timeout: 1s`,
 		},
 	}
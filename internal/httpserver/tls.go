@@ -0,0 +1,54 @@
+package httpserver
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var ErrClientCANoCertificate = errors.New("no certificate found in client CA file")
+
+// newTLSConfig returns a TLS configuration using the certificate
+// and key files from the settings, or nil if no certificate is set.
+// If the client CA file is set, client certificates are requested
+// and verified against it if given.
+func newTLSConfig(settings Settings) (tlsConfig *tls.Config, err error) {
+	if settings.TLSCertificateFilepath == "" {
+		return nil, nil //nolint:nilnil
+	}
+
+	certificate, err := tls.LoadX509KeyPair(
+		settings.TLSCertificateFilepath, settings.TLSKeyFilepath)
+	if err != nil {
+		return nil, fmt.Errorf("loading certificate and key: %w", err)
+	}
+
+	tlsConfig = &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{certificate},
+	}
+
+	if settings.TLSClientCAFilepath == "" {
+		return tlsConfig, nil
+	}
+
+	caPEM, err := os.ReadFile(settings.TLSClientCAFilepath)
+	if err != nil {
+		return nil, fmt.Errorf("reading client CA file: %w", err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("%w: %s", ErrClientCANoCertificate,
+			settings.TLSClientCAFilepath)
+	}
+	tlsConfig.ClientCAs = clientCAs
+	// Clients without certificate are let through the TLS handshake
+	// such that the authentication middleware can decide, per route,
+	// if a verified client certificate is required.
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,183 @@
+package httpserver
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSelfSignedCertificate writes a self signed certificate and its
+// private key as PEM files in a temporary directory, and returns their
+// file paths together with the parsed certificate.
+func writeSelfSignedCertificate(t *testing.T) (certificatePath, keyPath string,
+	certificate *x509.Certificate) {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	certificateDER, err := x509.CreateCertificate(rand.Reader, template, template,
+		&privateKey.PublicKey, privateKey)
+	require.NoError(t, err)
+	certificate, err = x509.ParseCertificate(certificateDER)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(privateKey)
+	require.NoError(t, err)
+
+	directory := t.TempDir()
+	certificatePath = filepath.Join(directory, "cert.pem")
+	keyPath = filepath.Join(directory, "key.pem")
+	const permission = 0o600
+	err = os.WriteFile(certificatePath, pem.EncodeToMemory(&pem.Block{
+		Type: "CERTIFICATE", Bytes: certificateDER}), permission)
+	require.NoError(t, err)
+	err = os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{
+		Type: "EC PRIVATE KEY", Bytes: keyDER}), permission)
+	require.NoError(t, err)
+
+	return certificatePath, keyPath, certificate
+}
+
+func Test_newTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	certificatePath, keyPath, _ := writeSelfSignedCertificate(t)
+	emptyCAPath := filepath.Join(t.TempDir(), "ca.pem")
+	err := os.WriteFile(emptyCAPath, nil, 0o600)
+	require.NoError(t, err)
+
+	t.Run("no_certificate", func(t *testing.T) {
+		t.Parallel()
+		tlsConfig, err := newTLSConfig(Settings{})
+		assert.NoError(t, err)
+		assert.Nil(t, tlsConfig)
+	})
+
+	t.Run("certificate_only", func(t *testing.T) {
+		t.Parallel()
+		tlsConfig, err := newTLSConfig(Settings{
+			TLSCertificateFilepath: certificatePath,
+			TLSKeyFilepath:         keyPath,
+		})
+		require.NoError(t, err)
+		assert.Len(t, tlsConfig.Certificates, 1)
+		assert.Equal(t, tls.NoClientCert, tlsConfig.ClientAuth)
+	})
+
+	t.Run("client_ca", func(t *testing.T) {
+		t.Parallel()
+		tlsConfig, err := newTLSConfig(Settings{
+			TLSCertificateFilepath: certificatePath,
+			TLSKeyFilepath:         keyPath,
+			TLSClientCAFilepath:    certificatePath,
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, tlsConfig.ClientCAs)
+		assert.Equal(t, tls.VerifyClientCertIfGiven, tlsConfig.ClientAuth)
+	})
+
+	t.Run("client_ca_empty", func(t *testing.T) {
+		t.Parallel()
+		tlsConfig, err := newTLSConfig(Settings{
+			TLSCertificateFilepath: certificatePath,
+			TLSKeyFilepath:         keyPath,
+			TLSClientCAFilepath:    emptyCAPath,
+		})
+		assert.ErrorIs(t, err, ErrClientCANoCertificate)
+		assert.EqualError(t, err, "no certificate found in client CA file: "+emptyCAPath)
+		assert.Nil(t, tlsConfig)
+	})
+}
+
+func Test_Server_Run_mutualTLS(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	certificatePath, keyPath, certificate := writeSelfSignedCertificate(t)
+
+	logger := NewMockLogger(ctrl)
+	logger.EXPECT().Info(newRegexMatcher("^http server listening on 127.0.0.1:[1-9][0-9]{0,4}$"))
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.VerifiedChains) == 0 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(r.TLS.VerifiedChains[0][0].Subject.CommonName))
+	})
+
+	server, err := New(Settings{
+		Address:                "127.0.0.1:0",
+		TLSCertificateFilepath: certificatePath,
+		TLSKeyFilepath:         keyPath,
+		TLSClientCAFilepath:    certificatePath,
+		Handler:                handler,
+		Logger:                 logger,
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready := make(chan struct{})
+	done := make(chan struct{})
+	go server.Run(ctx, ready, done)
+	<-ready
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(certificate)
+	clientCertificate, err := tls.LoadX509KeyPair(certificatePath, keyPath)
+	require.NoError(t, err)
+
+	doRequest := func(clientCertificates []tls.Certificate) (statusCode int) {
+		client := &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					MinVersion:   tls.VersionTLS12,
+					RootCAs:      rootCAs,
+					Certificates: clientCertificates,
+				},
+			},
+		}
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet,
+			"https://"+server.GetAddress()+"/", nil)
+		require.NoError(t, err)
+		response, err := client.Do(request)
+		require.NoError(t, err)
+		_ = response.Body.Close()
+		return response.StatusCode
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, doRequest(nil))
+	assert.Equal(t, http.StatusOK, doRequest([]tls.Certificate{clientCertificate}))
+
+	cancel()
+	_, ok := <-done
+	assert.False(t, ok)
+}
@@ -36,7 +36,7 @@ func (s *State) ApplyStatus(ctx context.Context, status models.LoopStatus) (
 			return "already " + existingStatus.String(), nil
 		}
 
-		s.status = constants.Starting
+		s.setStatus(constants.Starting)
 		s.statusMu.Unlock()
 		s.start <- struct{}{}
 
@@ -56,7 +56,7 @@ func (s *State) ApplyStatus(ctx context.Context, status models.LoopStatus) (
 			return "already " + existingStatus.String(), nil
 		}
 
-		s.status = constants.Stopping
+		s.setStatus(constants.Stopping)
 		s.statusMu.Unlock()
 		s.stop <- struct{}{}
 
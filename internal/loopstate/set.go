@@ -8,5 +8,18 @@ import "github.com/qdm12/gluetun/internal/models"
 func (s *State) SetStatus(status models.LoopStatus) {
 	s.statusMu.Lock()
 	defer s.statusMu.Unlock()
+	s.setStatus(status)
+}
+
+// setStatus sets the status and notifies the status change
+// function if the status changed. It must be called with
+// the status mutex locked.
+func (s *State) setStatus(status models.LoopStatus) {
+	if s.status == status {
+		return
+	}
 	s.status = status
+	if s.onStatusChange != nil {
+		s.onStatusChange(status)
+	}
 }
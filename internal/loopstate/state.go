@@ -6,23 +6,28 @@ import (
 	"github.com/qdm12/gluetun/internal/models"
 )
 
+// New creates a new loop state. The onStatusChange function
+// is optional and is called each time the status changes.
 func New(status models.LoopStatus,
 	start chan<- struct{}, running <-chan models.LoopStatus,
-	stop chan<- struct{}, stopped <-chan struct{}) *State {
+	stop chan<- struct{}, stopped <-chan struct{},
+	onStatusChange func(status models.LoopStatus)) *State {
 	return &State{
-		status:  status,
-		start:   start,
-		running: running,
-		stop:    stop,
-		stopped: stopped,
+		status:         status,
+		onStatusChange: onStatusChange,
+		start:          start,
+		running:        running,
+		stop:           stop,
+		stopped:        stopped,
 	}
 }
 
 type State struct {
 	loopMu sync.RWMutex
 
-	status   models.LoopStatus
-	statusMu sync.RWMutex
+	status         models.LoopStatus
+	statusMu       sync.RWMutex
+	onStatusChange func(status models.LoopStatus)
 
 	start   chan<- struct{}
 	running <-chan models.LoopStatus
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/events"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// Run updates the metrics from the events received on the events
+// channel given, until the context is canceled, and then calls the
+// unsubscribe function given. The caller should subscribe before
+// launching Run in a goroutine, so no event published meanwhile is missed.
+func (m *Metrics) Run(ctx context.Context, done chan<- struct{},
+	eventsCh <-chan events.Event, unsubscribe func()) {
+	defer close(done)
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-eventsCh:
+			m.onEvent(event)
+		}
+	}
+}
+
+func (m *Metrics) onEvent(event events.Event) {
+	switch event.Type {
+	case events.VPNStatus:
+		m.setLoopStatus("vpn", event.Data.(events.Status).Status) //nolint:forcetypeassert
+	case events.DNSStatus:
+		m.setLoopStatus("dns", event.Data.(events.Status).Status) //nolint:forcetypeassert
+	case events.UpdaterStatus:
+		m.setLoopStatus("updater", event.Data.(events.Status).Status) //nolint:forcetypeassert
+	case events.PortForwarded:
+		ports := event.Data.(events.Ports).Ports //nolint:forcetypeassert
+		m.portForwarded.Reset()
+		for i, port := range ports {
+			m.portForwarded.WithLabelValues(strconv.Itoa(i)).Set(float64(port))
+		}
+	case events.PublicIP:
+		publicIP := event.Data.(models.PublicIP) //nolint:forcetypeassert
+		if !publicIP.IP.IsValid() {
+			// public IP data cleared when the VPN goes down
+			return
+		}
+		if m.lastPublicIP.IsValid() && m.lastPublicIP != publicIP.IP {
+			m.publicIPChanges.Inc()
+		}
+		m.lastPublicIP = publicIP.IP
+	}
+}
+
+func (m *Metrics) setLoopStatus(loop string, status models.LoopStatus) {
+	allStatuses := []models.LoopStatus{
+		constants.Starting,
+		constants.Running,
+		constants.Stopping,
+		constants.Stopped,
+		constants.Crashed,
+		constants.Completed,
+	}
+	for _, possibleStatus := range allStatuses {
+		value := 0.0
+		if possibleStatus == status {
+			value = 1
+		}
+		m.loopStatus.WithLabelValues(loop, string(possibleStatus)).Set(value)
+	}
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/events"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Metrics_onEvent(t *testing.T) {
+	t.Parallel()
+
+	metrics, err := New(nil)
+	require.NoError(t, err)
+
+	metrics.onEvent(events.Event{
+		Type: events.VPNStatus,
+		Data: events.Status{Status: constants.Running},
+	})
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.loopStatus.WithLabelValues("vpn", "running")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.loopStatus.WithLabelValues("vpn", "stopped")))
+
+	metrics.onEvent(events.Event{
+		Type: events.PortForwarded,
+		Data: events.Ports{Ports: []uint16{1000, 2000}},
+	})
+	assert.Equal(t, 2000.0, testutil.ToFloat64(metrics.portForwarded.WithLabelValues("1")))
+
+	publicIPs := []netip.Addr{
+		netip.AddrFrom4([4]byte{1, 1, 1, 1}),
+		{}, // cleared when the VPN goes down
+		netip.AddrFrom4([4]byte{1, 1, 1, 1}),
+		netip.AddrFrom4([4]byte{2, 2, 2, 2}),
+	}
+	for _, publicIP := range publicIPs {
+		metrics.onEvent(events.Event{
+			Type: events.PublicIP,
+			Data: models.PublicIP{IP: publicIP},
+		})
+	}
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.publicIPChanges))
+}
@@ -0,0 +1,18 @@
+package metrics
+
+import (
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+type VPNLooper interface {
+	GetStatus() (status models.LoopStatus)
+	GetSettings() (settings settings.VPN)
+}
+
+type Logger interface {
+	Debug(s string)
+	Info(s string)
+	Warn(s string)
+	Error(s string)
+}
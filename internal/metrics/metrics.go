@@ -0,0 +1,138 @@
+// Package metrics defines Prometheus metrics for gluetun
+// and an HTTP server to expose them.
+package metrics
+
+import (
+	"fmt"
+	"net/netip"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	prom "github.com/qdm12/dns/v2/pkg/metrics/prometheus"
+	filterprometheus "github.com/qdm12/dns/v2/pkg/middlewares/filter/metrics/prometheus"
+)
+
+const namespace = "gluetun"
+
+type Metrics struct {
+	registry *prometheus.Registry
+	logger   Logger
+	// DNSFilter contains the DNS over TLS filter metrics,
+	// notably counting blocked queries.
+	DNSFilter *filterprometheus.Metrics
+
+	loopStatus          *prometheus.GaugeVec
+	unhealthyRestarts   prometheus.Counter
+	healthCheckDuration prometheus.Histogram
+	healthCheckFailures prometheus.Counter
+	portForwarded       *prometheus.GaugeVec
+	publicIPChanges     prometheus.Counter
+
+	// Only accessed in the Run goroutine
+	lastPublicIP netip.Addr
+}
+
+// New creates all the metrics and registers them on a dedicated registry.
+func New(logger Logger) (metrics *Metrics, err error) {
+	registry := prometheus.NewRegistry()
+
+	metrics = &Metrics{
+		registry: registry,
+		logger:   logger,
+		loopStatus: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "loop_status",
+			Help:      "Status of a loop, set to 1 for its current status and 0 otherwise",
+		}, []string{"loop", "status"}),
+		unhealthyRestarts: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "vpn_unhealthy_restarts_total",
+			Help:      "Number of VPN restarts triggered by the health check",
+		}),
+		healthCheckDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "health_check_duration_seconds",
+			Help:      "Duration of health checks",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 11), //nolint:gomnd
+		}),
+		healthCheckFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "health_check_failures_total",
+			Help:      "Number of failed health checks",
+		}),
+		portForwarded: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "port_forwarded",
+			Help:      "Port forwarded by the VPN provider, by index for providers forwarding multiple ports",
+		}, []string{"index"}),
+		publicIPChanges: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "public_ip_changes_total",
+			Help:      "Number of times the public IP address changed",
+		}),
+	}
+
+	err = registry.Register(collectors.NewGoCollector())
+	if err != nil {
+		return nil, fmt.Errorf("registering Go collector: %w", err)
+	}
+
+	err = registry.Register(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	if err != nil {
+		return nil, fmt.Errorf("registering process collector: %w", err)
+	}
+
+	collectorsToRegister := []prometheus.Collector{
+		metrics.loopStatus,
+		metrics.unhealthyRestarts,
+		metrics.healthCheckDuration,
+		metrics.healthCheckFailures,
+		metrics.portForwarded,
+		metrics.publicIPChanges,
+	}
+	for _, collector := range collectorsToRegister {
+		err = registry.Register(collector)
+		if err != nil {
+			return nil, fmt.Errorf("registering collector: %w", err)
+		}
+	}
+
+	metrics.DNSFilter, err = filterprometheus.New(filterprometheus.Settings{
+		Prometheus: prom.Settings{
+			Prefix:   namespace + "_dns",
+			Registry: registry,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating DNS filter metrics: %w", err)
+	}
+
+	return metrics, nil
+}
+
+// RegisterTunnelCollector registers a collector reading the VPN
+// tunnel statistics at scrape time. It is separate from New since
+// the VPN loop is created after other loops using the metrics.
+func (m *Metrics) RegisterTunnelCollector(vpnLooper VPNLooper) (err error) {
+	err = m.registry.Register(newTunnelCollector(vpnLooper, m.logger))
+	if err != nil {
+		return fmt.Errorf("registering tunnel collector: %w", err)
+	}
+	return nil
+}
+
+// UnhealthyRestartsInc increments the counter of VPN restarts
+// triggered by the health check.
+func (m *Metrics) UnhealthyRestartsInc() {
+	m.unhealthyRestarts.Inc()
+}
+
+// HealthCheckObserve records the duration of a health check
+// and whether it failed.
+func (m *Metrics) HealthCheckObserve(duration time.Duration, err error) {
+	m.healthCheckDuration.Observe(duration.Seconds())
+	if err != nil {
+		m.healthCheckFailures.Inc()
+	}
+}
@@ -0,0 +1,27 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/qdm12/gluetun/internal/httpserver"
+)
+
+// NewServer creates an HTTP server serving the metrics
+// at the /metrics path on the address given.
+func (m *Metrics) NewServer(address string, logger httpserver.Logger) (
+	server *httpserver.Server, err error) {
+	handler := http.NewServeMux()
+	handler.Handle("/metrics", promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+
+	server, err = httpserver.New(httpserver.Settings{
+		Address: address,
+		Handler: handler,
+		Logger:  logger,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating metrics server: %w", err)
+	}
+	return server, nil
+}
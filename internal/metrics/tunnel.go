@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"golang.zx2c4.com/wireguard/wgctrl"
+)
+
+// tunnelCollector collects VPN tunnel metrics at scrape time,
+// since these are read from the kernel and not pushed by gluetun.
+type tunnelCollector struct {
+	vpnLooper VPNLooper
+	logger    Logger
+	timeNow   func() time.Time
+
+	receivedBytes    *prometheus.Desc
+	transmittedBytes *prometheus.Desc
+	handshakeAge     *prometheus.Desc
+}
+
+func newTunnelCollector(vpnLooper VPNLooper, logger Logger) *tunnelCollector {
+	return &tunnelCollector{
+		vpnLooper: vpnLooper,
+		logger:    logger,
+		timeNow:   time.Now,
+		receivedBytes: prometheus.NewDesc(namespace+"_tunnel_receive_bytes_total",
+			"Number of bytes received on the VPN network interface", []string{"interface"}, nil),
+		transmittedBytes: prometheus.NewDesc(namespace+"_tunnel_transmit_bytes_total",
+			"Number of bytes transmitted on the VPN network interface", []string{"interface"}, nil),
+		handshakeAge: prometheus.NewDesc(namespace+"_wireguard_last_handshake_age_seconds",
+			"Duration since the last Wireguard handshake with the VPN server", []string{"interface"}, nil),
+	}
+}
+
+func (c *tunnelCollector) Describe(descriptions chan<- *prometheus.Desc) {
+	descriptions <- c.receivedBytes
+	descriptions <- c.transmittedBytes
+	descriptions <- c.handshakeAge
+}
+
+func (c *tunnelCollector) Collect(metrics chan<- prometheus.Metric) {
+	if c.vpnLooper.GetStatus() != constants.Running {
+		return
+	}
+
+	settings := c.vpnLooper.GetSettings()
+	vpnInterface := settings.OpenVPN.Interface
+	if settings.Type == vpn.Wireguard {
+		vpnInterface = settings.Wireguard.Interface
+	}
+
+	for desc, statistic := range map[*prometheus.Desc]string{
+		c.receivedBytes:    "rx_bytes",
+		c.transmittedBytes: "tx_bytes",
+	} {
+		value, err := readInterfaceStatistic(vpnInterface, statistic)
+		if err != nil {
+			c.logger.Debug(err.Error())
+			continue
+		}
+		metrics <- prometheus.MustNewConstMetric(desc,
+			prometheus.CounterValue, value, vpnInterface)
+	}
+
+	if settings.Type != vpn.Wireguard {
+		return
+	}
+
+	lastHandshake, err := getLastHandshake(vpnInterface)
+	if err != nil {
+		c.logger.Debug(err.Error())
+		return
+	} else if lastHandshake.IsZero() {
+		return
+	}
+	age := c.timeNow().Sub(lastHandshake)
+	metrics <- prometheus.MustNewConstMetric(c.handshakeAge,
+		prometheus.GaugeValue, age.Seconds(), vpnInterface)
+}
+
+func readInterfaceStatistic(interfaceName, statistic string) (value float64, err error) {
+	path := filepath.Join("/sys/class/net", interfaceName, "statistics", statistic)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("reading %s statistic: %w", statistic, err)
+	}
+
+	value, err = strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing %s statistic: %w", statistic, err)
+	}
+	return value, nil
+}
+
+func getLastHandshake(interfaceName string) (lastHandshake time.Time, err error) {
+	client, err := wgctrl.New()
+	if err != nil {
+		return lastHandshake, fmt.Errorf("creating wgctrl client: %w", err)
+	}
+	defer client.Close()
+
+	device, err := client.Device(interfaceName)
+	if err != nil {
+		return lastHandshake, fmt.Errorf("getting Wireguard device: %w", err)
+	}
+
+	for _, peer := range device.Peers {
+		if peer.LastHandshakeTime.After(lastHandshake) {
+			lastHandshake = peer.LastHandshakeTime
+		}
+	}
+	return lastHandshake, nil
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+// Escalation is the state of the unhealthy VPN escalation ladder.
+type Escalation struct {
+	// Step is the current escalation step, which is 'none'
+	// if the VPN is healthy.
+	Step string `json:"step"`
+	// Attempt is the attempt number within the current step,
+	// starting at 1, and is zero for the 'none' step.
+	Attempt uint `json:"attempt"`
+	// Location is the fallback location used for the
+	// 'switch_location' step.
+	Location string `json:"location,omitempty"`
+	// Since is the time the current step started, and
+	// is the zero time if no step was taken.
+	Since time.Time `json:"since"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"net/netip"
+	"time"
+)
+
+// ServerFailure is the failure history of a VPN server endpoint.
+type ServerFailure struct {
+	// ServerName is the name of the server, if any.
+	ServerName string `json:"server_name,omitempty"`
+	// Hostname is the hostname of the server, if any.
+	Hostname string `json:"hostname,omitempty"`
+	// IP is the IP address of the server endpoint which failed.
+	IP netip.Addr `json:"ip"`
+	// Count is the number of failures recorded for the endpoint.
+	Count uint `json:"count"`
+	// LastFailure is the time of the last failure recorded.
+	LastFailure time.Time `json:"last_failure"`
+	// LastError is the error message of the last failure recorded.
+	LastError string `json:"last_error"`
+}
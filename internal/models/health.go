@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// HealthResult is the result of a health probe, or of a whole
+// health check if its probe is 'overall'.
+type HealthResult struct {
+	// Time is the time the result was recorded.
+	Time time.Time `json:"time"`
+	// Probe is the probe description, such as 'tcp cloudflare.com:443',
+	// or 'overall' for the result of a whole health check.
+	Probe string `json:"probe"`
+	// Latency is the duration the probe or health check took.
+	Latency time.Duration `json:"latency"`
+	// Error is the error message if the probe or health check
+	// failed, and is empty otherwise.
+	Error string `json:"error,omitempty"`
+}
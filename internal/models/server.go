@@ -36,6 +36,10 @@ type Server struct {
 	PortForward bool         `json:"port_forward,omitempty"`
 	Keep        bool         `json:"keep,omitempty"`
 	IPs         []netip.Addr `json:"ips,omitempty"`
+	// Load is the server load percentage reported by the
+	// VPN provider, and is nil if the provider does not report it.
+	// It is ignored when comparing servers since it changes often.
+	Load *uint8 `json:"load,omitempty"`
 }
 
 var (
@@ -72,6 +76,8 @@ func (s *Server) Equal(other Server) (equal bool) {
 	serverCopy := *s
 	serverCopy.IPs = nil
 	other.IPs = nil
+	serverCopy.Load = nil
+	other.Load = nil
 	return reflect.DeepEqual(serverCopy, other)
 }
 
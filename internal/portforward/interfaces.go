@@ -3,12 +3,15 @@ package portforward
 import (
 	"context"
 	"net/netip"
+
+	"github.com/qdm12/gluetun/internal/portforward/service"
 )
 
 type Service interface {
 	Start(ctx context.Context) (runError <-chan error, err error)
 	Stop() (err error)
 	GetPortsForwarded() (ports []uint16)
+	GetDetails() (details service.Details)
 }
 
 type Routing interface {
@@ -23,6 +26,10 @@ type PortAllower interface {
 		destinationPort uint16) (err error)
 }
 
+type EventPublisher interface {
+	Publish(eventType string, data any)
+}
+
 type Logger interface {
 	Debug(s string)
 	Info(s string)
@@ -2,11 +2,13 @@ package portforward
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"sync"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants/providers"
 	"github.com/qdm12/gluetun/internal/portforward/service"
 )
 
@@ -16,10 +18,11 @@ type Loop struct {
 	settingsMutex sync.RWMutex
 	service       Service
 	// Fixed injected objects
-	routing     Routing
-	client      *http.Client
-	portAllower PortAllower
-	logger      Logger
+	routing        Routing
+	client         *http.Client
+	portAllower    PortAllower
+	eventPublisher EventPublisher
+	logger         Logger
 	// Fixed parameters
 	uid, gid int
 	// Internal channels and locks
@@ -34,7 +37,7 @@ type Loop struct {
 
 func NewLoop(settings settings.PortForwarding, routing Routing,
 	client *http.Client, portAllower PortAllower,
-	logger Logger, uid, gid int) *Loop {
+	eventPublisher EventPublisher, logger Logger, uid, gid int) *Loop {
 	return &Loop{
 		settings: Settings{
 			VPNIsUp: ptrTo(false),
@@ -44,12 +47,13 @@ func NewLoop(settings settings.PortForwarding, routing Routing,
 				ListeningPort: *settings.ListeningPort,
 			},
 		},
-		routing:     routing,
-		client:      client,
-		portAllower: portAllower,
-		logger:      logger,
-		uid:         uid,
-		gid:         gid,
+		routing:        routing,
+		client:         client,
+		portAllower:    portAllower,
+		eventPublisher: eventPublisher,
+		logger:         logger,
+		uid:            uid,
+		gid:            gid,
 	}
 }
 
@@ -111,9 +115,15 @@ func (l *Loop) run(runCtx context.Context, runDone chan<- struct{},
 		serviceSettings := l.settings.Service.Copy()
 		// Only enable port forward if the VPN tunnel is up
 		*serviceSettings.Enabled = *serviceSettings.Enabled && *l.settings.VPNIsUp
+		if l.settings.Service.RequestNewPort {
+			// Only request a new port for this service start
+			l.settingsMutex.Lock()
+			l.settings.Service.RequestNewPort = false
+			l.settingsMutex.Unlock()
+		}
 
 		l.service = service.New(serviceSettings, l.routing, l.client,
-			l.portAllower, l.logger, l.uid, l.gid)
+			l.portAllower, l.eventPublisher, l.logger, l.uid, l.gid)
 
 		var err error
 		serviceRunError, err = l.service.Start(runCtx)
@@ -143,6 +153,44 @@ func (l *Loop) UpdateWith(partialUpdate Settings) (err error) {
 	}
 }
 
+var (
+	ErrNotEnabled          = errors.New("port forwarding is not enabled or the VPN is not up")
+	ErrNewPortNotSupported = errors.New("requesting a new port is not supported")
+)
+
+// Refresh re-obtains the ports forwarded from the port forwarding
+// provider and restarts keeping them alive, without restarting the VPN.
+// If requestNewPort is true, the provider is requested to forward
+// a new port instead of re-using the previous one, and an error is
+// returned if the provider does not support it.
+func (l *Loop) Refresh(requestNewPort bool) (err error) {
+	l.settingsMutex.RLock()
+	enabled := *l.settings.Service.Enabled && *l.settings.VPNIsUp
+	portForwarder := l.settings.Service.PortForwarder
+	l.settingsMutex.RUnlock()
+	if !enabled {
+		return fmt.Errorf("%w", ErrNotEnabled)
+	}
+
+	// Only the Private Internet Access port forwarder re-uses
+	// a saved port and can therefore request a new one.
+	if requestNewPort && (portForwarder == nil ||
+		portForwarder.Name() != providers.PrivateInternetAccess) {
+		providerName := "unknown"
+		if portForwarder != nil {
+			providerName = portForwarder.Name()
+		}
+		return fmt.Errorf("%w: for provider %s", ErrNewPortNotSupported, providerName)
+	}
+
+	partialUpdate := Settings{
+		Service: service.Settings{
+			RequestNewPort: requestNewPort,
+		},
+	}
+	return l.UpdateWith(partialUpdate)
+}
+
 func (l *Loop) Stop() (err error) {
 	l.runCancel()
 	<-l.runDone
@@ -160,6 +208,13 @@ func (l *Loop) GetPortsForwarded() (ports []uint16) {
 	return l.service.GetPortsForwarded()
 }
 
+func (l *Loop) GetDetails() (details service.Details) {
+	if l.service == nil {
+		return details
+	}
+	return l.service.GetDetails()
+}
+
 func ptrTo[T any](value T) *T {
 	return &value
 }
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"net/netip"
+	"time"
+)
+
+// Details contains details on the current port forwarding.
+type Details struct {
+	Ports []uint16
+	// Protocols are the network protocols the ports are
+	// forwarded for, which are always TCP and UDP since
+	// the firewall allows both protocols for each port.
+	Protocols []string
+	Gateway   netip.Addr
+	Provider  string
+	// Expiry is the time at which the port forward expires
+	// if it is not kept alive, and is the zero time if the
+	// port forward does not expire.
+	Expiry time.Time
+	// LastKeepAlive is the last time the port forward was
+	// obtained or kept alive.
+	LastKeepAlive time.Time
+}
+
+// GetDetails returns details on the current port forwarding.
+// If no port is forwarded, the returned details are empty.
+func (s *Service) GetDetails() (details Details) {
+	s.portMutex.RLock()
+	defer s.portMutex.RUnlock()
+
+	if len(s.ports) == 0 {
+		return details
+	}
+
+	details.Ports = make([]uint16, len(s.ports))
+	copy(details.Ports, s.ports)
+	details.Protocols = []string{"tcp", "udp"}
+	details.Gateway = s.gateway
+	details.Provider = s.settings.PortForwarder.Name()
+	details.Expiry = s.expiry
+	details.LastKeepAlive = s.lastKeepAlive
+	return details
+}
+
+// NotifyKeepAlive is called by the port forwarder each time
+// the port forward is obtained or kept alive.
+func (s *Service) NotifyKeepAlive(expiry time.Time) {
+	s.portMutex.Lock()
+	defer s.portMutex.Unlock()
+	s.expiry = expiry
+	s.lastKeepAlive = s.timeNow()
+}
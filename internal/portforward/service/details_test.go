@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/provider/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+type testPortForwarder struct{}
+
+func (testPortForwarder) Name() string { return "test" }
+func (testPortForwarder) PortForward(context.Context, utils.PortForwardObjects) ([]uint16, error) {
+	return nil, nil
+}
+func (testPortForwarder) KeepPortForward(context.Context, utils.PortForwardObjects) error {
+	return nil
+}
+
+func Test_Service_GetDetails(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1000, 0)
+	expiry := time.Unix(2000, 0)
+	service := &Service{
+		settings: Settings{PortForwarder: testPortForwarder{}},
+		timeNow:  func() time.Time { return now },
+	}
+
+	details := service.GetDetails()
+	assert.Equal(t, Details{}, details)
+
+	service.ports = []uint16{1, 2}
+	service.gateway = netip.AddrFrom4([4]byte{10, 0, 0, 1})
+	service.NotifyKeepAlive(expiry)
+
+	details = service.GetDetails()
+	expectedDetails := Details{
+		Ports:         []uint16{1, 2},
+		Protocols:     []string{"tcp", "udp"},
+		Gateway:       netip.AddrFrom4([4]byte{10, 0, 0, 1}),
+		Provider:      "test",
+		Expiry:        expiry,
+		LastKeepAlive: now,
+	}
+	assert.Equal(t, expectedDetails, details)
+
+	// Returned ports are a copy
+	details.Ports[0] = 9
+	assert.Equal(t, []uint16{1, 2}, service.GetPortsForwarded())
+}
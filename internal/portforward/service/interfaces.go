@@ -19,6 +19,10 @@ type Routing interface {
 	AssignedIP(interfaceName string, family int) (ip netip.Addr, err error)
 }
 
+type EventPublisher interface {
+	Publish(eventType string, data any)
+}
+
 type Logger interface {
 	Debug(s string)
 	Info(s string)
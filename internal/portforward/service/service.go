@@ -3,22 +3,29 @@ package service
 import (
 	"context"
 	"net/http"
+	"net/netip"
 	"sync"
+	"time"
 )
 
 type Service struct {
 	// State
-	portMutex sync.RWMutex
-	ports     []uint16
+	portMutex     sync.RWMutex
+	ports         []uint16
+	gateway       netip.Addr
+	expiry        time.Time
+	lastKeepAlive time.Time
 	// Fixed parameters
 	settings Settings
 	puid     int
 	pgid     int
 	// Fixed injected objects
-	routing     Routing
-	client      *http.Client
-	portAllower PortAllower
-	logger      Logger
+	routing        Routing
+	client         *http.Client
+	portAllower    PortAllower
+	eventPublisher EventPublisher
+	logger         Logger
+	timeNow        func() time.Time
 	// Internal channels and locks
 	startStopMutex sync.Mutex
 	keepPortCancel context.CancelFunc
@@ -26,17 +33,20 @@ type Service struct {
 }
 
 func New(settings Settings, routing Routing, client *http.Client,
-	portAllower PortAllower, logger Logger, puid, pgid int) *Service {
+	portAllower PortAllower, eventPublisher EventPublisher,
+	logger Logger, puid, pgid int) *Service {
 	return &Service{
 		// Fixed parameters
 		settings: settings,
 		puid:     puid,
 		pgid:     pgid,
 		// Fixed injected objects
-		routing:     routing,
-		client:      client,
-		portAllower: portAllower,
-		logger:      logger,
+		routing:        routing,
+		client:         client,
+		portAllower:    portAllower,
+		eventPublisher: eventPublisher,
+		logger:         logger,
+		timeNow:        time.Now,
 	}
 }
 
@@ -18,6 +18,9 @@ type Settings struct {
 	ListeningPort  uint16
 	Username       string // needed for PIA
 	Password       string // needed for PIA
+	// RequestNewPort is set to request a new port instead of
+	// re-using a saved one, and is only used by PIA.
+	RequestNewPort bool
 }
 
 func (s Settings) Copy() (copied Settings) {
@@ -30,6 +33,7 @@ func (s Settings) Copy() (copied Settings) {
 	copied.ListeningPort = s.ListeningPort
 	copied.Username = s.Username
 	copied.Password = s.Password
+	copied.RequestNewPort = s.RequestNewPort
 	return copied
 }
 
@@ -43,6 +47,7 @@ func (s *Settings) OverrideWith(update Settings) {
 	s.ListeningPort = gosettings.OverrideWithComparable(s.ListeningPort, update.ListeningPort)
 	s.Username = gosettings.OverrideWithComparable(s.Username, update.Username)
 	s.Password = gosettings.OverrideWithComparable(s.Password, update.Password)
+	s.RequestNewPort = gosettings.OverrideWithComparable(s.RequestNewPort, update.RequestNewPort)
 }
 
 var (
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/qdm12/gluetun/internal/events"
 	"github.com/qdm12/gluetun/internal/netlink"
 	"github.com/qdm12/gluetun/internal/provider/utils"
 )
@@ -33,14 +34,16 @@ func (s *Service) Start(ctx context.Context) (runError <-chan error, err error)
 	}
 
 	obj := utils.PortForwardObjects{
-		Logger:         s.logger,
-		Gateway:        gateway,
-		InternalIP:     internalIP,
-		Client:         s.client,
-		ServerName:     s.settings.ServerName,
-		CanPortForward: s.settings.CanPortForward,
-		Username:       s.settings.Username,
-		Password:       s.settings.Password,
+		Logger:            s.logger,
+		Gateway:           gateway,
+		InternalIP:        internalIP,
+		Client:            s.client,
+		ServerName:        s.settings.ServerName,
+		CanPortForward:    s.settings.CanPortForward,
+		Username:          s.settings.Username,
+		Password:          s.settings.Password,
+		RequestNewPort:    s.settings.RequestNewPort,
+		KeepAliveNotifier: s,
 	}
 	ports, err := s.settings.PortForwarder.PortForward(ctx, obj)
 	if err != nil {
@@ -71,7 +74,10 @@ func (s *Service) Start(ctx context.Context) (runError <-chan error, err error)
 
 	s.portMutex.Lock()
 	s.ports = ports
+	s.gateway = gateway
+	s.lastKeepAlive = s.timeNow()
 	s.portMutex.Unlock()
+	s.eventPublisher.Publish(events.PortForwarded, events.Ports{Ports: ports})
 
 	keepPortCtx, keepPortCancel := context.WithCancel(context.Background())
 	s.keepPortCancel = keepPortCancel
@@ -3,7 +3,11 @@ package service
 import (
 	"context"
 	"fmt"
+	"net/netip"
 	"os"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/events"
 )
 
 func (s *Service) Stop() (err error) {
@@ -47,6 +51,10 @@ func (s *Service) cleanup() (err error) {
 	}
 
 	s.ports = nil
+	s.gateway = netip.Addr{}
+	s.expiry = time.Time{}
+	s.lastKeepAlive = time.Time{}
+	s.eventPublisher.Publish(events.PortForwarded, events.Ports{Ports: []uint16{}})
 
 	filepath := s.settings.Filepath
 	s.logger.Info("removing port file " + filepath)
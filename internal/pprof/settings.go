@@ -15,16 +15,16 @@ import (
 type Settings struct {
 	// Enabled can be false or true.
 	// It defaults to false.
-	Enabled *bool
+	Enabled *bool `json:"enabled"`
 	// See runtime.SetBlockProfileRate
 	// Set to 0 to disable profiling.
-	BlockProfileRate *int
+	BlockProfileRate *int `json:"block_profile_rate"`
 	// See runtime.SetMutexProfileFraction
 	// Set to 0 to disable profiling.
-	MutexProfileRate *int
+	MutexProfileRate *int `json:"mutex_profile_rate"`
 	// HTTPServer contains settings to configure
 	// the HTTP server serving pprof data.
-	HTTPServer httpserver.Settings
+	HTTPServer httpserver.Settings `json:"http_server"`
 }
 
 func (s *Settings) SetDefaults() {
@@ -22,6 +22,7 @@ func Test_Settings_SetDefaults(t *testing.T) {
 				Enabled: boolPtr(false),
 				HTTPServer: httpserver.Settings{
 					Address:           "localhost:6060",
+					UnixSocketMode:    0o660,
 					ReadHeaderTimeout: 3 * time.Second,
 					ReadTimeout:       5 * time.Minute,
 					ShutdownTimeout:   3 * time.Second,
@@ -46,6 +47,7 @@ func Test_Settings_SetDefaults(t *testing.T) {
 				MutexProfileRate: intPtr(1),
 				HTTPServer: httpserver.Settings{
 					Address:           ":6061",
+					UnixSocketMode:    0o660,
 					ReadHeaderTimeout: time.Second,
 					ReadTimeout:       time.Second,
 					ShutdownTimeout:   time.Second,
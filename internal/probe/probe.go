@@ -0,0 +1,67 @@
+// Package probe measures round trip times to remote endpoints
+// using TCP connections or protocol specific UDP handshakes.
+package probe
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// TCP returns the duration taken to establish a TCP connection
+// to the address given.
+func TCP(ctx context.Context, address string) (rtt time.Duration, err error) {
+	dialer := net.Dialer{}
+	start := time.Now()
+	connection, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return 0, err
+	}
+	rtt = time.Since(start)
+
+	err = connection.Close()
+	if err != nil {
+		return 0, fmt.Errorf("closing connection: %w", err)
+	}
+	return rtt, nil
+}
+
+// udpRoundTrip sends the request to the UDP address given and returns
+// the duration until a response matching isResponse is received.
+func udpRoundTrip(ctx context.Context, address string, request []byte,
+	isResponse func(response []byte) bool) (rtt time.Duration, err error) {
+	dialer := net.Dialer{}
+	connection, err := dialer.DialContext(ctx, "udp", address)
+	if err != nil {
+		return 0, err
+	}
+	defer connection.Close()
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = connection.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	start := time.Now()
+	_, err = connection.Write(request)
+	if err != nil {
+		return 0, fmt.Errorf("writing request: %w", err)
+	}
+
+	const maxPacketSize = 1500
+	buffer := make([]byte, maxPacketSize)
+	for {
+		n, err := connection.Read(buffer)
+		if err != nil {
+			if ctx.Err() != nil {
+				return 0, ctx.Err()
+			}
+			return 0, fmt.Errorf("reading response: %w", err)
+		}
+
+		if isResponse(buffer[:n]) {
+			return time.Since(start), nil
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package probe
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.zx2c4.com/wireguard/conn"
+	"golang.zx2c4.com/wireguard/device"
+	"golang.zx2c4.com/wireguard/tun/tuntest"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+func Test_TCP(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	rtt, err := TCP(ctx, listener.Addr().String())
+
+	require.NoError(t, err)
+	assert.Greater(t, rtt, time.Duration(0))
+}
+
+func Test_udpRoundTrip_timeout(t *testing.T) {
+	t.Parallel()
+
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = server.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	isResponse := func([]byte) bool { return true }
+	_, err = udpRoundTrip(ctx, server.LocalAddr().String(), []byte{0}, isResponse)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func Test_WireguardHandshake(t *testing.T) {
+	t.Parallel()
+
+	serverKey, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+	clientKey, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+
+	// Run a userspace Wireguard server knowing the client peer.
+	tun := tuntest.NewChannelTUN()
+	logger := device.NewLogger(device.LogLevelSilent, "")
+	server := device.NewDevice(tun.TUN(), conn.NewDefaultBind(), logger)
+	t.Cleanup(server.Close)
+	clientPublicKey := clientKey.PublicKey()
+	config := fmt.Sprintf("private_key=%s\nlisten_port=0\n"+
+		"public_key=%s\nallowed_ip=10.0.0.2/32\n",
+		hex.EncodeToString(serverKey[:]),
+		hex.EncodeToString(clientPublicKey[:]))
+	err = server.IpcSet(config)
+	require.NoError(t, err)
+	err = server.Up()
+	require.NoError(t, err)
+
+	serverConfig, err := server.IpcGet()
+	require.NoError(t, err)
+	port := 0
+	for _, line := range strings.Split(serverConfig, "\n") {
+		value, ok := strings.CutPrefix(line, "listen_port=")
+		if ok {
+			port, err = strconv.Atoi(value)
+			require.NoError(t, err)
+		}
+	}
+	require.NotZero(t, port)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	address := net.JoinHostPort("127.0.0.1", fmt.Sprint(port))
+	rtt, err := WireguardHandshake(ctx, address, clientKey, serverKey.PublicKey())
+
+	require.NoError(t, err)
+	assert.Greater(t, rtt, time.Duration(0))
+}
@@ -0,0 +1,168 @@
+package probe
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+
+	"golang.org/x/crypto/blake2s"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+)
+
+const (
+	wireguardConstruction = "Noise_IKpsk2_25519_ChaChaPoly_BLAKE2s"
+	wireguardIdentifier   = "WireGuard v1 zx2c4 Jason@zx2c4.com"
+	wireguardLabelMAC1    = "mac1----"
+
+	wireguardInitiationType = 1
+	wireguardResponseType   = 2
+	wireguardInitiationSize = 148
+	wireguardResponseSize   = 92
+)
+
+// WireguardHandshake returns the round trip time of a Wireguard
+// handshake initiation and response with the Wireguard server at the
+// address given. The private key must be the key of a client peer known
+// by the server, since the server silently drops initiations from
+// unknown peers.
+func WireguardHandshake(ctx context.Context, address string,
+	privateKey, serverPublicKey [32]byte) (rtt time.Duration, err error) {
+	request, err := newWireguardInitiation(privateKey, serverPublicKey,
+		time.Now(), rand.Reader)
+	if err != nil {
+		return 0, fmt.Errorf("creating handshake initiation: %w", err)
+	}
+
+	senderIndex := request[4:8]
+	isResponse := func(response []byte) bool {
+		return len(response) == wireguardResponseSize &&
+			response[0] == wireguardResponseType &&
+			string(response[8:12]) == string(senderIndex)
+	}
+	return udpRoundTrip(ctx, address, request, isResponse)
+}
+
+// newWireguardInitiation creates a Wireguard handshake initiation
+// message following the Noise IK pattern described in the Wireguard
+// whitepaper, with an empty mac2 since no cookie is known.
+func newWireguardInitiation(privateKey, serverPublicKey [32]byte,
+	now time.Time, random io.Reader) (message []byte, err error) {
+	chainKey := blake2s.Sum256([]byte(wireguardConstruction))
+	hashValue := mixHash(chainKey, []byte(wireguardIdentifier))
+	hashValue = mixHash(hashValue, serverPublicKey[:])
+
+	var ephemeralPrivateKey [32]byte
+	_, err = io.ReadFull(random, ephemeralPrivateKey[:])
+	if err != nil {
+		return nil, fmt.Errorf("generating ephemeral private key: %w", err)
+	}
+	ephemeralPublicKey, err := curve25519.X25519(ephemeralPrivateKey[:], curve25519.Basepoint)
+	if err != nil {
+		return nil, fmt.Errorf("computing ephemeral public key: %w", err)
+	}
+
+	message = make([]byte, wireguardInitiationSize)
+	message[0] = wireguardInitiationType
+	_, err = io.ReadFull(random, message[4:8]) // sender index
+	if err != nil {
+		return nil, fmt.Errorf("generating sender index: %w", err)
+	}
+
+	copy(message[8:40], ephemeralPublicKey)
+	hashValue = mixHash(hashValue, ephemeralPublicKey)
+	chainKey = kdf1(chainKey, ephemeralPublicKey)
+
+	sharedSecret, err := curve25519.X25519(ephemeralPrivateKey[:], serverPublicKey[:])
+	if err != nil {
+		return nil, fmt.Errorf("computing ephemeral shared secret: %w", err)
+	}
+	chainKey, key := kdf2(chainKey, sharedSecret)
+	publicKey, err := curve25519.X25519(privateKey[:], curve25519.Basepoint)
+	if err != nil {
+		return nil, fmt.Errorf("computing public key: %w", err)
+	}
+	err = seal(message[40:40], key, publicKey, hashValue)
+	if err != nil {
+		return nil, fmt.Errorf("encrypting static key: %w", err)
+	}
+	hashValue = mixHash(hashValue, message[40:88])
+
+	sharedSecret, err = curve25519.X25519(privateKey[:], serverPublicKey[:])
+	if err != nil {
+		return nil, fmt.Errorf("computing static shared secret: %w", err)
+	}
+	_, key = kdf2(chainKey, sharedSecret)
+	timestamp := tai64n(now)
+	err = seal(message[88:88], key, timestamp[:], hashValue)
+	if err != nil {
+		return nil, fmt.Errorf("encrypting timestamp: %w", err)
+	}
+
+	mac1Key := blake2s.Sum256(append([]byte(wireguardLabelMAC1), serverPublicKey[:]...))
+	mac, err := blake2s.New128(mac1Key[:])
+	if err != nil {
+		return nil, fmt.Errorf("creating mac1 hash: %w", err)
+	}
+	_, _ = mac.Write(message[:116])
+	mac.Sum(message[116:116])
+
+	return message, nil
+}
+
+func mixHash(hashValue [32]byte, data []byte) [32]byte {
+	hasher, _ := blake2s.New256(nil)
+	_, _ = hasher.Write(hashValue[:])
+	_, _ = hasher.Write(data)
+	var result [32]byte
+	hasher.Sum(result[:0])
+	return result
+}
+
+func hmacBlake2s(key, data []byte) (sum [32]byte) {
+	newHash := func() hash.Hash {
+		hasher, _ := blake2s.New256(nil)
+		return hasher
+	}
+	mac := hmac.New(newHash, key)
+	_, _ = mac.Write(data)
+	mac.Sum(sum[:0])
+	return sum
+}
+
+func kdf1(key [32]byte, input []byte) (first [32]byte) {
+	prk := hmacBlake2s(key[:], input)
+	return hmacBlake2s(prk[:], []byte{0x1})
+}
+
+func kdf2(key [32]byte, input []byte) (first, second [32]byte) {
+	prk := hmacBlake2s(key[:], input)
+	first = hmacBlake2s(prk[:], []byte{0x1})
+	second = hmacBlake2s(prk[:], append(first[:], 0x2))
+	return first, second
+}
+
+// seal encrypts the plaintext with a zero nonce and the additional
+// data given, appending the ciphertext to destination.
+func seal(destination []byte, key [32]byte, plaintext []byte,
+	additionalData [32]byte) (err error) {
+	aead, err := chacha20poly1305.New(key[:])
+	if err != nil {
+		return err
+	}
+	var nonce [chacha20poly1305.NonceSize]byte
+	aead.Seal(destination, nonce[:], plaintext, additionalData[:])
+	return nil
+}
+
+func tai64n(t time.Time) (timestamp [12]byte) {
+	const tai64Base = uint64(0x400000000000000a)
+	binary.BigEndian.PutUint64(timestamp[:8], tai64Base+uint64(t.Unix()))
+	binary.BigEndian.PutUint32(timestamp[8:], uint32(t.Nanosecond()))
+	return timestamp
+}
@@ -18,6 +18,13 @@ type Fetcher interface {
 	FetchServers(ctx context.Context, minServers int) (servers []models.Server, err error)
 }
 
+// LoadFetcher is a Fetcher also able to fetch the
+// current load of each server, keyed by server IP address.
+type LoadFetcher interface {
+	Fetcher
+	FetchLoads(ctx context.Context) (ipToLoad map[netip.Addr]uint8, err error)
+}
+
 type ParallelResolver interface {
 	Resolve(ctx context.Context, settings resolver.ParallelSettings) (
 		hostToIPs map[string][]netip.Addr, warnings []string, err error)
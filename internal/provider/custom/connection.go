@@ -8,6 +8,7 @@ import (
 	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/constants/vpn"
 	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider/utils"
 )
 
 var (
@@ -15,7 +16,9 @@ var (
 )
 
 // GetConnection gets the connection from the OpenVPN configuration file.
-func (p *Provider) GetConnection(selection settings.ServerSelection, _ bool) (
+// The connection options are ignored since there is a single server.
+func (p *Provider) GetConnection(selection settings.ServerSelection,
+	_ utils.ConnectionOptions, _ bool) (
 	connection models.Connection, err error) {
 	switch selection.VPN {
 	case vpn.OpenVPN:
@@ -6,11 +6,12 @@ import (
 	"github.com/qdm12/gluetun/internal/provider/utils"
 )
 
-func (p *Provider) GetConnection(selection settings.ServerSelection, ipv6Supported bool) (
+func (p *Provider) GetConnection(selection settings.ServerSelection,
+	options utils.ConnectionOptions, ipv6Supported bool) (
 	connection models.Connection, err error) {
 	// TODO: Set the default ports for each VPN protocol+network protocol
 	// combination. If one combination is not supported, set it to `0`.
 	defaults := utils.NewConnectionDefaults(443, 1194, 51820) //nolint:gomnd
 	return utils.GetConnection(p.Name(),
-		p.storage, selection, defaults, ipv6Supported, p.randSource)
+		p.storage, selection, options, defaults, ipv6Supported, p.randSource)
 }
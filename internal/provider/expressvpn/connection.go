@@ -6,9 +6,10 @@ import (
 	"github.com/qdm12/gluetun/internal/provider/utils"
 )
 
-func (p *Provider) GetConnection(selection settings.ServerSelection, ipv6Supported bool) (
+func (p *Provider) GetConnection(selection settings.ServerSelection,
+	options utils.ConnectionOptions, ipv6Supported bool) (
 	connection models.Connection, err error) {
 	defaults := utils.NewConnectionDefaults(0, 1195, 0) //nolint:gomnd
 	return utils.GetConnection(p.Name(),
-		p.storage, selection, defaults, ipv6Supported, p.randSource)
+		p.storage, selection, options, defaults, ipv6Supported, p.randSource)
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/qdm12/gluetun/internal/constants/vpn"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/provider/common"
+	"github.com/qdm12/gluetun/internal/provider/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -103,7 +104,8 @@ func Test_Provider_GetConnection(t *testing.T) {
 			parallelResolver := (common.ParallelResolver)(nil)
 			provider := New(storage, randSource, client, warner, parallelResolver)
 
-			connection, err := provider.GetConnection(testCase.selection, testCase.ipv6Supported)
+			connection, err := provider.GetConnection(testCase.selection,
+				utils.ConnectionOptions{}, testCase.ipv6Supported)
 
 			assert.ErrorIs(t, err, testCase.errWrapped)
 			if testCase.errWrapped != nil {
@@ -14,6 +14,7 @@ import (
 	"github.com/qdm12/gluetun/internal/constants/vpn"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/provider/common"
+	"github.com/qdm12/gluetun/internal/provider/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -101,7 +102,8 @@ func Test_Provider_GetConnection(t *testing.T) {
 			client := (*http.Client)(nil)
 			provider := New(storage, randSource, client)
 
-			connection, err := provider.GetConnection(testCase.selection, testCase.ipv6Supported)
+			connection, err := provider.GetConnection(testCase.selection,
+				utils.ConnectionOptions{}, testCase.ipv6Supported)
 
 			assert.ErrorIs(t, err, testCase.errWrapped)
 			if testCase.errWrapped != nil {
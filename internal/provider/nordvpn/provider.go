@@ -12,15 +12,15 @@ import (
 type Provider struct {
 	storage    common.Storage
 	randSource rand.Source
-	common.Fetcher
+	common.LoadFetcher
 }
 
 func New(storage common.Storage, randSource rand.Source,
 	client *http.Client, updaterWarner common.Warner) *Provider {
 	return &Provider{
-		storage:    storage,
-		randSource: randSource,
-		Fetcher:    updater.New(client, updaterWarner),
+		storage:     storage,
+		randSource:  randSource,
+		LoadFetcher: updater.New(client, updaterWarner),
 	}
 }
 
@@ -0,0 +1,74 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+)
+
+// FetchLoads obtains the current load of each server,
+// keyed by server entry IP address.
+func (u *Updater) FetchLoads(ctx context.Context) (
+	ipToLoad map[netip.Addr]uint8, err error) {
+	data, err := fetchLoadsAPI(ctx, u.client)
+	if err != nil {
+		return nil, err
+	}
+
+	ipToLoad = make(map[netip.Addr]uint8, len(data))
+	for _, server := range data {
+		if !server.Station.IsValid() {
+			continue
+		}
+		ipToLoad[server.Station] = server.Load
+	}
+	return ipToLoad, nil
+}
+
+// Check out the JSON data from
+// https://api.nordvpn.com/v1/servers?limit=10&fields[servers.station]&fields[servers.load]
+type loadData struct {
+	// Station is the server entry IP address.
+	Station netip.Addr `json:"station"`
+	// Load is the server load percentage from 0 to 100.
+	Load uint8 `json:"load"`
+}
+
+// fetchLoadsAPI fetches the load of all the servers, requesting only
+// the station and load fields of each server, so the response is a
+// small fraction of the full servers data used by the updater.
+func fetchLoadsAPI(ctx context.Context, client *http.Client) (
+	data []loadData, err error) {
+	// The v1 API has a default limit of 10 servers, so the limit is set
+	// well above the number of NordVPN servers.
+	const url = "https://api.nordvpn.com/v1/servers?limit=100000" +
+		"&fields[servers.station]&fields[servers.load]"
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s", ErrHTTPStatusCodeNotOK, response.Status)
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	if err := decoder.Decode(&data); err != nil {
+		return nil, fmt.Errorf("decoding response body: %w", err)
+	}
+
+	if err := response.Body.Close(); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
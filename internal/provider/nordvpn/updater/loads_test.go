@@ -0,0 +1,48 @@
+package updater
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func Test_Updater_FetchLoads(t *testing.T) {
+	t.Parallel()
+
+	client := &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			assert.Equal(t, "https://api.nordvpn.com/v1/servers?limit=100000"+
+				"&fields[servers.station]&fields[servers.load]", r.URL.String())
+			file, err := os.Open("testdata/loads.json")
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Status:     http.StatusText(http.StatusOK),
+				Body:       file,
+			}, nil
+		}),
+	}
+	updater := New(client, nil)
+
+	ipToLoad, err := updater.FetchLoads(context.Background())
+
+	require.NoError(t, err)
+	expected := map[netip.Addr]uint8{
+		netip.MustParseAddr("37.120.211.19"):  23,
+		netip.MustParseAddr("194.233.96.102"): 58,
+	}
+	assert.Equal(t, expected, ipToLoad)
+}
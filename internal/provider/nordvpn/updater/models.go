@@ -29,6 +29,8 @@ type serverData struct {
 	Hostname string
 	// Status is the server status, for example 'online'
 	Status string `json:"status"`
+	// Load is the server load percentage from 0 to 100.
+	Load uint8 `json:"load"`
 	// Locations is the list of location IDs for the server.
 	// Only the first location is taken into account for now.
 	LocationIDs  []uint32 `json:"location_ids"`
@@ -60,7 +60,12 @@ func (p *Provider) PortForward(ctx context.Context,
 	durationToExpiration := data.Expiration.Sub(p.timeNow())
 	expired := durationToExpiration <= 0
 
-	if dataFound {
+	switch {
+	case dataFound && objects.RequestNewPort:
+		logger.Info("Discarding saved forwarded port data for port " +
+			strconv.Itoa(int(data.Port)) + " to get another one")
+		dataFound = false
+	case dataFound:
 		logger.Info("Found saved forwarded port data for port " + strconv.Itoa(int(data.Port)))
 		if expired {
 			logger.Warn("Forwarded port data expired on " +
@@ -83,6 +88,7 @@ func (p *Provider) PortForward(ctx context.Context,
 	if err := bindPort(ctx, privateIPClient, apiIP, data); err != nil {
 		return nil, fmt.Errorf("binding port: %w", err)
 	}
+	objects.KeepAliveNotifier.NotifyKeepAlive(data.Expiration)
 
 	return []uint16{data.Port}, nil
 }
@@ -133,6 +139,7 @@ func (p *Provider) KeepPortForward(ctx context.Context,
 			if err != nil {
 				return fmt.Errorf("binding port: %w", err)
 			}
+			objects.KeepAliveNotifier.NotifyKeepAlive(data.Expiration)
 			keepAliveTimer.Reset(keepAlivePeriod)
 		case <-expiryTimer.C:
 			return fmt.Errorf("%w: on %s", ErrPortForwardedExpired,
@@ -57,6 +57,7 @@ func (p *Provider) PortForward(ctx context.Context, objects utils.PortForwardObj
 	checkExternalPorts(logger, assignedUDPExternalPort, assignedTCPExternalPort)
 
 	p.portForwarded = assignedTCPExternalPort
+	objects.KeepAliveNotifier.NotifyKeepAlive(time.Now().Add(assignedLifetime))
 
 	return []uint16{assignedTCPExternalPort}, nil
 }
@@ -97,6 +98,7 @@ func (p *Provider) KeepPortForward(ctx context.Context,
 		const internalPort = 0
 		const lifetime = 60 * time.Second
 
+		var expiry time.Time
 		for _, networkProtocol := range networkProtocols {
 			_, _, assignedExternalPort, assignedLiftetime, err :=
 				client.AddPortMapping(ctx, objects.Gateway, networkProtocol,
@@ -110,12 +112,14 @@ func (p *Provider) KeepPortForward(ctx context.Context,
 					" from requested lifetime %s",
 					assignedLiftetime, lifetime))
 			}
+			expiry = time.Now().Add(assignedLiftetime)
 
 			if p.portForwarded != assignedExternalPort {
 				return fmt.Errorf("%w: %d changed to %d",
 					ErrExternalPortChanged, p.portForwarded, assignedExternalPort)
 			}
 		}
+		objects.KeepAliveNotifier.NotifyKeepAlive(expiry)
 
 		objects.Logger.Debug(fmt.Sprintf("port forwarded %d maintained", p.portForwarded))
 
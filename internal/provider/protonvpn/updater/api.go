@@ -25,6 +25,8 @@ type logicalServer struct {
 	Servers     []physicalServer `json:"Servers"`
 	Features    uint16           `json:"Features"`
 	Tier        *uint8           `json:"Tier,omitempty"`
+	// Load is the server load percentage from 0 to 100.
+	Load uint8 `json:"Load"`
 }
 
 type physicalServer struct {
@@ -0,0 +1,34 @@
+package updater
+
+import (
+	"context"
+	"net/netip"
+)
+
+// FetchLoads obtains the current load of each server,
+// keyed by server entry IP address.
+func (u *Updater) FetchLoads(ctx context.Context) (
+	ipToLoad map[netip.Addr]uint8, err error) {
+	data, err := fetchAPI(ctx, u.client)
+	if err != nil {
+		return nil, err
+	}
+
+	return extractLoads(data), nil
+}
+
+func extractLoads(data apiData) (ipToLoad map[netip.Addr]uint8) {
+	ipToLoad = make(map[netip.Addr]uint8, len(data.LogicalServers))
+	for _, logicalServer := range data.LogicalServers {
+		for _, physicalServer := range logicalServer.Servers {
+			_, ok := ipToLoad[physicalServer.EntryIP]
+			if ok {
+				// keep the first logical server load, in
+				// the same way as for the servers data.
+				continue
+			}
+			ipToLoad[physicalServer.EntryIP] = logicalServer.Load
+		}
+	}
+	return ipToLoad
+}
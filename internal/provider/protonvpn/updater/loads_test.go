@@ -0,0 +1,47 @@
+package updater
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func Test_Updater_FetchLoads(t *testing.T) {
+	t.Parallel()
+
+	client := &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			assert.Equal(t, "https://api.protonmail.ch/vpn/logicals", r.URL.String())
+			file, err := os.Open("testdata/logicals.json")
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Status:     http.StatusText(http.StatusOK),
+				Body:       file,
+			}, nil
+		}),
+	}
+	updater := New(client, nil)
+
+	ipToLoad, err := updater.FetchLoads(context.Background())
+
+	require.NoError(t, err)
+	expected := map[netip.Addr]uint8{
+		netip.MustParseAddr("185.159.157.132"): 37,
+		netip.MustParseAddr("89.39.107.113"):   91,
+	}
+	assert.Equal(t, expected, ipToLoad)
+}
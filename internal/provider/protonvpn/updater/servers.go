@@ -75,7 +75,8 @@ func (u *Updater) FetchServers(ctx context.Context, minServers int) (
 				u.warner.Warn(warning)
 			}
 
-			ipToServer.add(country, region, city, name, hostname, wgPubKey, free, entryIP, features)
+			ipToServer.add(country, region, city, name, hostname, wgPubKey,
+				free, entryIP, features, logicalServer.Load)
 		}
 	}
 
@@ -5,11 +5,13 @@ import (
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider/utils"
 )
 
 // Provider contains methods to read and modify the openvpn configuration to connect as a client.
 type Provider interface {
-	GetConnection(selection settings.ServerSelection, ipv6Supported bool) (connection models.Connection, err error)
+	GetConnection(selection settings.ServerSelection, options utils.ConnectionOptions,
+		ipv6Supported bool) (connection models.Connection, err error)
 	OpenVPNConfig(connection models.Connection, settings settings.OpenVPN, ipv6Supported bool) (lines []string)
 	Name() string
 	FetchServers(ctx context.Context, minServers int) (
@@ -3,8 +3,10 @@ package utils
 import (
 	"fmt"
 	"math/rand"
+	"net/netip"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/constants/vpn"
 	"github.com/qdm12/gluetun/internal/models"
 )
@@ -32,6 +34,7 @@ type Storage interface {
 func GetConnection(provider string,
 	storage Storage,
 	selection settings.ServerSelection,
+	options ConnectionOptions,
 	defaults ConnectionDefaults,
 	ipv6Supported bool,
 	randSource rand.Source) (
@@ -46,6 +49,7 @@ func GetConnection(provider string,
 		defaults.OpenVPNUDPPort, defaults.WireguardPort)
 
 	connections := make([]models.Connection, 0, len(servers))
+	ipToLoad := make(map[netip.Addr]uint8)
 	for _, server := range servers {
 		for _, ip := range server.IPs {
 			if !ipv6Supported && ip.Is6() {
@@ -70,8 +74,17 @@ func GetConnection(provider string,
 				PubKey:      server.WgPubKey, // Wireguard
 			}
 			connections = append(connections, connection)
+			if server.Load != nil {
+				ipToLoad[ip] = *server.Load
+			}
 		}
 	}
 
-	return pickConnection(connections, selection, randSource)
+	connections = excludeConnections(connections, options.ExcludedConnections)
+	connections = restrictToCandidates(connections, options.CandidateIPs)
+	if *selection.Strategy == constants.SelectionLeastLoaded {
+		connections = leastLoadedConnections(connections, ipToLoad)
+	}
+
+	return pickConnection(connections, selection, options.Prober, randSource)
 }
@@ -26,6 +26,7 @@ func Test_GetConnection(t *testing.T) {
 		filteredServers []models.Server
 		filterError     error
 		serverSelection settings.ServerSelection
+		options         ConnectionOptions
 		defaults        ConnectionDefaults
 		ipv6Supported   bool
 		randSource      rand.Source
@@ -196,7 +197,7 @@ func Test_GetConnection(t *testing.T) {
 				Return(testCase.filteredServers, testCase.filterError)
 
 			connection, err := GetConnection(testCase.provider, storage,
-				testCase.serverSelection, testCase.defaults, testCase.ipv6Supported,
+				testCase.serverSelection, testCase.options, testCase.defaults, testCase.ipv6Supported,
 				testCase.randSource)
 
 			assert.Equal(t, testCase.connection, connection)
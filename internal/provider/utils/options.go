@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"net/netip"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// ConnectionOptions contains runtime options to pick a connection,
+// set by the VPN loop and not part of the user settings.
+type ConnectionOptions struct {
+	// ExcludedConnections is a list of connections whose servers
+	// should not be picked, for example to rotate away from the
+	// current server. It is ignored if excluding these servers
+	// leaves no connection to pick from.
+	ExcludedConnections []models.Connection
+	// CandidateIPs is a list of server IP addresses to restrict the
+	// connection to, for example to try servers in order. It is
+	// ignored if none of these IP addresses are available to pick from.
+	CandidateIPs []netip.Addr
+	// Prober is the connection latency prober to use with
+	// the 'latency' strategy. If it is nil, a random connection
+	// is picked instead.
+	Prober ConnectionProber
+}
+
+// ConnectionProber measures the latency to each of the connections
+// given, where a zero latency indicates the probe failed.
+type ConnectionProber interface {
+	ProbeLatencies(connections []models.Connection) (latencies []time.Duration)
+}
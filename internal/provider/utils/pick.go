@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"math/rand"
 	"net/netip"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/constants/vpn"
 	"github.com/qdm12/gluetun/internal/models"
 )
@@ -16,10 +18,12 @@ var ErrNoConnectionToPickFrom = errors.New("no connection to pick from")
 // pickConnection picks a connection from a pool of connections.
 // If the VPN protocol is Wireguard and the target IP is set,
 // it finds the connection corresponding to this target IP.
-// Otherwise, it picks a random connection from the pool of connections
-// and sets the target IP address as the IP if this one is set.
+// Otherwise, it picks a connection from the pool of connections using
+// the selection strategy, and sets the target IP address as the IP if
+// this one is set.
 func pickConnection(connections []models.Connection,
-	selection settings.ServerSelection, randSource rand.Source) (
+	selection settings.ServerSelection, prober ConnectionProber,
+	randSource rand.Source) (
 	connection models.Connection, err error) {
 	if len(connections) == 0 {
 		return connection, ErrNoConnectionToPickFrom
@@ -32,7 +36,12 @@ func pickConnection(connections []models.Connection,
 		return getTargetIPConnection(connections, selection.TargetIP)
 	}
 
-	connection = pickRandomConnection(connections, randSource)
+	if prober != nil && *selection.Strategy == constants.SelectionLatency {
+		connection = pickLowestLatencyConnection(connections,
+			int(*selection.LatencyCandidates), prober, randSource)
+	} else {
+		connection = pickRandomConnection(connections, randSource)
+	}
 	if targetIPSet {
 		connection.IP = selection.TargetIP
 	}
@@ -40,11 +49,132 @@ func pickConnection(connections []models.Connection,
 	return connection, nil
 }
 
+// excludeConnections returns the connections whose server is not the
+// server of any of the excluded connections. If all the connections
+// are excluded, it returns all the connections given.
+func excludeConnections(connections, excluded []models.Connection) (
+	filtered []models.Connection) {
+	if len(excluded) == 0 {
+		return connections
+	}
+
+	filtered = make([]models.Connection, 0, len(connections))
+	for _, connection := range connections {
+		isExcluded := false
+		for _, excludedConnection := range excluded {
+			if sameServer(connection, excludedConnection) {
+				isExcluded = true
+				break
+			}
+		}
+		if !isExcluded {
+			filtered = append(filtered, connection)
+		}
+	}
+
+	if len(filtered) == 0 {
+		return connections
+	}
+	return filtered
+}
+
+// restrictToCandidates returns the connections whose IP address is
+// one of the candidate IP addresses. If no candidate IP address is
+// given or no connection matches, it returns all the connections given.
+func restrictToCandidates(connections []models.Connection,
+	candidateIPs []netip.Addr) (restricted []models.Connection) {
+	if len(candidateIPs) == 0 {
+		return connections
+	}
+
+	restricted = make([]models.Connection, 0, len(candidateIPs))
+	for _, connection := range connections {
+		for _, candidateIP := range candidateIPs {
+			if connection.IP == candidateIP {
+				restricted = append(restricted, connection)
+				break
+			}
+		}
+	}
+
+	if len(restricted) == 0 {
+		return connections
+	}
+	return restricted
+}
+
+// leastLoadedConnections returns the connections whose server
+// has the lowest known load. If no server load is known, it
+// returns all the connections given.
+func leastLoadedConnections(connections []models.Connection,
+	ipToLoad map[netip.Addr]uint8) (leastLoaded []models.Connection) {
+	var minLoad uint8
+	minLoadFound := false
+	for _, connection := range connections {
+		load, ok := ipToLoad[connection.IP]
+		if ok && (!minLoadFound || load < minLoad) {
+			minLoad = load
+			minLoadFound = true
+		}
+	}
+
+	if !minLoadFound {
+		return connections
+	}
+
+	leastLoaded = make([]models.Connection, 0, len(connections))
+	for _, connection := range connections {
+		load, ok := ipToLoad[connection.IP]
+		if ok && load == minLoad {
+			leastLoaded = append(leastLoaded, connection)
+		}
+	}
+	return leastLoaded
+}
+
+// sameServer returns true if both connections are for the same server,
+// comparing their server names, hostnames or IP addresses in this order.
+func sameServer(a, b models.Connection) bool {
+	switch {
+	case a.ServerName != "" || b.ServerName != "":
+		return a.ServerName == b.ServerName
+	case a.Hostname != "" || b.Hostname != "":
+		return a.Hostname == b.Hostname
+	default:
+		return a.IP == b.IP
+	}
+}
+
 func pickRandomConnection(connections []models.Connection,
 	source rand.Source) models.Connection {
 	return connections[rand.New(source).Intn(len(connections))] //nolint:gosec
 }
 
+// pickLowestLatencyConnection probes up to candidatesCount randomly
+// sampled connections and returns the connection with the lowest
+// latency. If all the probes fail, it returns a random connection.
+func pickLowestLatencyConnection(connections []models.Connection,
+	candidatesCount int, prober ConnectionProber,
+	source rand.Source) models.Connection {
+	candidatesCount = min(candidatesCount, len(connections))
+	candidates := make([]models.Connection, candidatesCount)
+	permutation := rand.New(source).Perm(len(connections)) //nolint:gosec
+	for i := range candidates {
+		candidates[i] = connections[permutation[i]]
+	}
+
+	latencies := prober.ProbeLatencies(candidates)
+	bestIndex := 0
+	var bestLatency time.Duration
+	for i, latency := range latencies {
+		if latency > 0 && (bestLatency == 0 || latency < bestLatency) {
+			bestIndex = i
+			bestLatency = latency
+		}
+	}
+	return candidates[bestIndex]
+}
+
 var errTargetIPNotFound = errors.New("target IP address not found")
 
 func getTargetIPConnection(connections []models.Connection,
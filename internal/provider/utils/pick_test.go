@@ -2,7 +2,9 @@ package utils
 
 import (
 	"math/rand"
+	"net/netip"
 	"testing"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/stretchr/testify/assert"
@@ -24,3 +26,205 @@ func Test_pickRandomConnection(t *testing.T) {
 	connection = pickRandomConnection(connections, source)
 	assert.Equal(t, models.Connection{Port: 2}, connection)
 }
+
+func Test_excludeConnections(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		connections []models.Connection
+		excluded    []models.Connection
+		filtered    []models.Connection
+	}{
+		"no_exclusion": {
+			connections: []models.Connection{{ServerName: "a"}},
+			filtered:    []models.Connection{{ServerName: "a"}},
+		},
+		"exclude_by_server_name": {
+			connections: []models.Connection{
+				{ServerName: "a", IP: netip.AddrFrom4([4]byte{1, 1, 1, 1})},
+				{ServerName: "a", IP: netip.AddrFrom4([4]byte{2, 2, 2, 2})},
+				{ServerName: "b", IP: netip.AddrFrom4([4]byte{3, 3, 3, 3})},
+			},
+			excluded: []models.Connection{{ServerName: "a"}},
+			filtered: []models.Connection{
+				{ServerName: "b", IP: netip.AddrFrom4([4]byte{3, 3, 3, 3})},
+			},
+		},
+		"exclude_by_hostname": {
+			connections: []models.Connection{{Hostname: "a"}, {Hostname: "b"}},
+			excluded:    []models.Connection{{Hostname: "b"}},
+			filtered:    []models.Connection{{Hostname: "a"}},
+		},
+		"exclude_by_ip": {
+			connections: []models.Connection{
+				{IP: netip.AddrFrom4([4]byte{1, 1, 1, 1})},
+				{IP: netip.AddrFrom4([4]byte{2, 2, 2, 2})},
+			},
+			excluded: []models.Connection{{IP: netip.AddrFrom4([4]byte{1, 1, 1, 1})}},
+			filtered: []models.Connection{{IP: netip.AddrFrom4([4]byte{2, 2, 2, 2})}},
+		},
+		"all_excluded": {
+			connections: []models.Connection{{ServerName: "a"}},
+			excluded:    []models.Connection{{ServerName: "a"}},
+			filtered:    []models.Connection{{ServerName: "a"}},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			filtered := excludeConnections(testCase.connections, testCase.excluded)
+
+			assert.Equal(t, testCase.filtered, filtered)
+		})
+	}
+}
+
+func Test_restrictToCandidates(t *testing.T) {
+	t.Parallel()
+
+	ipA := netip.AddrFrom4([4]byte{1, 1, 1, 1})
+	ipB := netip.AddrFrom4([4]byte{2, 2, 2, 2})
+
+	testCases := map[string]struct {
+		connections  []models.Connection
+		candidateIPs []netip.Addr
+		restricted   []models.Connection
+	}{
+		"no_candidate": {
+			connections: []models.Connection{{IP: ipA}, {IP: ipB}},
+			restricted:  []models.Connection{{IP: ipA}, {IP: ipB}},
+		},
+		"candidate_matched": {
+			connections:  []models.Connection{{IP: ipA}, {IP: ipB}},
+			candidateIPs: []netip.Addr{ipB},
+			restricted:   []models.Connection{{IP: ipB}},
+		},
+		"candidate_not_matched": {
+			connections:  []models.Connection{{IP: ipA}},
+			candidateIPs: []netip.Addr{ipB},
+			restricted:   []models.Connection{{IP: ipA}},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			restricted := restrictToCandidates(testCase.connections, testCase.candidateIPs)
+
+			assert.Equal(t, testCase.restricted, restricted)
+		})
+	}
+}
+
+func Test_leastLoadedConnections(t *testing.T) {
+	t.Parallel()
+
+	ipA := netip.AddrFrom4([4]byte{1, 1, 1, 1})
+	ipB := netip.AddrFrom4([4]byte{2, 2, 2, 2})
+	ipC := netip.AddrFrom4([4]byte{3, 3, 3, 3})
+
+	testCases := map[string]struct {
+		connections []models.Connection
+		ipToLoad    map[netip.Addr]uint8
+		leastLoaded []models.Connection
+	}{
+		"no_load_known": {
+			connections: []models.Connection{{IP: ipA}, {IP: ipB}},
+			leastLoaded: []models.Connection{{IP: ipA}, {IP: ipB}},
+		},
+		"single_least_loaded": {
+			connections: []models.Connection{{IP: ipA}, {IP: ipB}, {IP: ipC}},
+			ipToLoad:    map[netip.Addr]uint8{ipA: 50, ipB: 10, ipC: 90},
+			leastLoaded: []models.Connection{{IP: ipB}},
+		},
+		"equally_least_loaded": {
+			connections: []models.Connection{{IP: ipA}, {IP: ipB}, {IP: ipC}},
+			ipToLoad:    map[netip.Addr]uint8{ipA: 20, ipB: 40, ipC: 20},
+			leastLoaded: []models.Connection{{IP: ipA}, {IP: ipC}},
+		},
+		"unknown_load_ignored": {
+			connections: []models.Connection{{IP: ipA}, {IP: ipB}},
+			ipToLoad:    map[netip.Addr]uint8{ipB: 70},
+			leastLoaded: []models.Connection{{IP: ipB}},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			leastLoaded := leastLoadedConnections(testCase.connections, testCase.ipToLoad)
+
+			assert.Equal(t, testCase.leastLoaded, leastLoaded)
+		})
+	}
+}
+
+type fakeProber struct {
+	latencies map[uint16]time.Duration
+}
+
+func (p *fakeProber) ProbeLatencies(connections []models.Connection) (
+	latencies []time.Duration) {
+	latencies = make([]time.Duration, len(connections))
+	for i, connection := range connections {
+		latencies[i] = p.latencies[connection.Port]
+	}
+	return latencies
+}
+
+func Test_pickLowestLatencyConnection(t *testing.T) {
+	t.Parallel()
+
+	connections := []models.Connection{
+		{Port: 1}, {Port: 2}, {Port: 3}, {Port: 4},
+	}
+
+	testCases := map[string]struct {
+		candidatesCount int
+		latencies       map[uint16]time.Duration
+		connection      models.Connection
+	}{
+		"lowest_latency": {
+			candidatesCount: 4,
+			latencies: map[uint16]time.Duration{
+				1: 30 * time.Millisecond,
+				2: 10 * time.Millisecond,
+				3: 20 * time.Millisecond,
+			},
+			connection: models.Connection{Port: 2},
+		},
+		"candidates_count_above_connections": {
+			candidatesCount: 10,
+			latencies: map[uint16]time.Duration{
+				4: time.Millisecond,
+			},
+			connection: models.Connection{Port: 4},
+		},
+		"all_probes_failed": {
+			candidatesCount: 1,
+			connection:      models.Connection{Port: 2},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			prober := &fakeProber{latencies: testCase.latencies}
+			source := rand.NewSource(0)
+
+			connection := pickLowestLatencyConnection(connections,
+				testCase.candidatesCount, prober, source)
+
+			assert.Equal(t, testCase.connection, connection)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"net/http"
 	"net/netip"
+	"time"
 )
 
 // PortForwardObjects contains fields that may or may not need to be set
@@ -25,6 +26,18 @@ type PortForwardObjects struct {
 	Username string
 	// Password is used by Private Internet Access for port forwarding.
 	Password string
+	// RequestNewPort is used by Private Internet Access to request
+	// a new port instead of re-using a previously saved port.
+	RequestNewPort bool
+	// KeepAliveNotifier is notified each time the port forward is
+	// obtained or kept alive, by Private Internet Access and ProtonVPN.
+	KeepAliveNotifier KeepAliveNotifier
+}
+
+type KeepAliveNotifier interface {
+	// NotifyKeepAlive is called with the expiry time of the port
+	// forward, which is the zero time if it does not expire.
+	NotifyKeepAlive(expiry time.Time)
 }
 
 type Routing interface {
@@ -18,7 +18,7 @@ func BuildWireguardSettings(connection models.Connection,
 	settings.MTU = userSettings.MTU
 	settings.IPv6 = &ipv6Supported
 
-	const rulePriority = 101 // 100 is to receive external connections
+	const rulePriority = 102 // 100 is to receive external connections, 101 for tunnels
 	settings.RulePriority = rulePriority
 
 	settings.Endpoint = netip.AddrPortFrom(connection.IP, connection.Port)
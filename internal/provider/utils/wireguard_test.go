@@ -56,7 +56,7 @@ func Test_BuildWireguardSettings(t *testing.T) {
 					netip.PrefixFrom(netip.AddrFrom4([4]byte{2, 2, 2, 2}), 32),
 				},
 				PersistentKeepaliveInterval: time.Hour,
-				RulePriority:                101,
+				RulePriority:                102,
 				IPv6:                        boolPtr(false),
 			},
 		},
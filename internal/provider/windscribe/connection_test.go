@@ -14,6 +14,7 @@ import (
 	"github.com/qdm12/gluetun/internal/constants/vpn"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/provider/common"
+	"github.com/qdm12/gluetun/internal/provider/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -105,12 +106,14 @@ func Test_Provider_GetConnection(t *testing.T) {
 
 			if testCase.panicMessage != "" {
 				assert.PanicsWithValue(t, testCase.panicMessage, func() {
-					_, _ = provider.GetConnection(testCase.selection, testCase.ipv6Supported)
+					_, _ = provider.GetConnection(testCase.selection,
+						utils.ConnectionOptions{}, testCase.ipv6Supported)
 				})
 				return
 			}
 
-			connection, err := provider.GetConnection(testCase.selection, testCase.ipv6Supported)
+			connection, err := provider.GetConnection(testCase.selection,
+				utils.ConnectionOptions{}, testCase.ipv6Supported)
 
 			assert.ErrorIs(t, err, testCase.errWrapped)
 			if testCase.errWrapped != nil {
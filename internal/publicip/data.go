@@ -1,6 +1,9 @@
 package publicip
 
-import "github.com/qdm12/gluetun/internal/models"
+import (
+	"github.com/qdm12/gluetun/internal/events"
+	"github.com/qdm12/gluetun/internal/models"
+)
 
 // GetData returns the public IP data obtained from the last
 // fetch. It is notably used by the HTTP control server.
@@ -16,6 +19,7 @@ func (l *Loop) ClearData() (err error) {
 	l.ipDataMutex.Lock()
 	defer l.ipDataMutex.Unlock()
 	l.ipData = models.PublicIP{}
+	l.eventPublisher.Publish(events.PublicIP, l.ipData)
 
 	l.settingsMutex.RLock()
 	filepath := *l.settings.IPFilepath
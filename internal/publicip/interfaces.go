@@ -12,6 +12,10 @@ type Fetcher interface {
 		result models.PublicIP, err error)
 }
 
+type EventPublisher interface {
+	Publish(eventType string, data any)
+}
+
 type Logger interface {
 	Info(s string)
 	Warn(s string)
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/events"
 	"github.com/qdm12/gluetun/internal/models"
 )
 
@@ -18,8 +19,9 @@ type Loop struct {
 	ipData        models.PublicIP
 	ipDataMutex   sync.RWMutex
 	// Fixed injected objects
-	fetcher Fetcher
-	logger  Logger
+	fetcher        Fetcher
+	eventPublisher EventPublisher
+	logger         Logger
 	// Fixed parameters
 	puid int
 	pgid int
@@ -37,15 +39,16 @@ type Loop struct {
 	timeNow func() time.Time
 }
 
-func NewLoop(fetcher Fetcher, logger Logger,
+func NewLoop(fetcher Fetcher, eventPublisher EventPublisher, logger Logger,
 	settings settings.PublicIP, puid, pgid int) *Loop {
 	return &Loop{
-		settings: settings,
-		fetcher:  fetcher,
-		logger:   logger,
-		puid:     puid,
-		pgid:     pgid,
-		timeNow:  time.Now,
+		settings:       settings,
+		fetcher:        fetcher,
+		eventPublisher: eventPublisher,
+		logger:         logger,
+		puid:           puid,
+		pgid:           pgid,
+		timeNow:        time.Now,
 	}
 }
 
@@ -121,6 +124,7 @@ func (l *Loop) run(runCtx context.Context, runDone chan<- struct{},
 		l.ipDataMutex.Lock()
 		l.ipData = result
 		l.ipDataMutex.Unlock()
+		l.eventPublisher.Publish(events.PublicIP, result)
 
 		filepath := *l.settings.IPFilepath
 		err = persistPublicIP(filepath, result.IP.String(), l.puid, l.pgid)
@@ -51,5 +51,9 @@ func (r *Routing) TearDown() error {
 		return fmt.Errorf("setting outbound subnets routes: %w", err)
 	}
 
+	if err := r.SetOutboundDomainRoutes(nil); err != nil {
+		return fmt.Errorf("removing outbound domains routes: %w", err)
+	}
+
 	return nil
 }
@@ -94,8 +94,9 @@ func (r *Routing) AddLocalRules(subnets []LocalNetwork) (err error) {
 		// The main table is a built-in value for Linux, see "man 8 ip-route"
 		const mainTable = 254
 
-		// Local has higher priority then outbound(99) and inbound(100) as the
-		// local routes might be necessary to reach the outbound/inbound routes.
+		// Local has higher priority then outbound(99), inbound(100), tunnels(101)
+		// and Wireguard(102) as the local routes might be necessary to reach the
+		// outbound/inbound routes.
 		const localPriority = 98
 
 		// Main table was setup correctly by Docker, just need to add rules to use it
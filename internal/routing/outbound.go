@@ -33,12 +33,12 @@ func (r *Routing) setOutboundRoutes(outboundSubnets []netip.Prefix,
 		return nil
 	}
 
-	warnings := r.removeOutboundSubnets(subnetsToRemove, defaultRoutes)
+	warnings := r.removeOutboundSubnets(&r.outboundSubnets, subnetsToRemove, defaultRoutes)
 	for _, warning := range warnings {
 		r.logger.Warn("cannot remove outdated outbound subnet from routing: " + warning)
 	}
 
-	err = r.addOutboundSubnets(subnetsToAdd, defaultRoutes)
+	err = r.addOutboundSubnets(&r.outboundSubnets, subnetsToAdd, defaultRoutes)
 	if err != nil {
 		return fmt.Errorf("adding outbound subnet to routes: %w", err)
 	}
@@ -46,8 +46,10 @@ func (r *Routing) setOutboundRoutes(outboundSubnets []netip.Prefix,
 	return nil
 }
 
-func (r *Routing) removeOutboundSubnets(subnets []netip.Prefix,
-	defaultRoutes []DefaultRoute) (warnings []string) {
+// removeOutboundSubnets removes the routes and rules for the subnets
+// given, and removes the subnets from the state slice given.
+func (r *Routing) removeOutboundSubnets(state *[]netip.Prefix,
+	subnets []netip.Prefix, defaultRoutes []DefaultRoute) (warnings []string) {
 	for i, subNet := range subnets {
 		for _, defaultRoute := range defaultRoutes {
 			err := r.deleteRouteVia(subNet, defaultRoute.Gateway, defaultRoute.NetInterface, outboundTable)
@@ -66,14 +68,16 @@ func (r *Routing) removeOutboundSubnets(subnets []netip.Prefix,
 			continue
 		}
 
-		r.outboundSubnets = subnet.RemoveSubnetFromSubnets(r.outboundSubnets, subNet)
+		*state = subnet.RemoveSubnetFromSubnets(*state, subNet)
 	}
 
 	return warnings
 }
 
-func (r *Routing) addOutboundSubnets(subnets []netip.Prefix,
-	defaultRoutes []DefaultRoute) (err error) {
+// addOutboundSubnets adds routes and rules for the subnets given
+// in the outbound table, and adds the subnets to the state slice given.
+func (r *Routing) addOutboundSubnets(state *[]netip.Prefix,
+	subnets []netip.Prefix, defaultRoutes []DefaultRoute) (err error) {
 	for i, subnet := range subnets {
 		subnetIsIPv6 := subnet.Addr().Is6()
 		subnetRouteAdded := false
@@ -102,7 +106,7 @@ func (r *Routing) addOutboundSubnets(subnets []netip.Prefix,
 			return fmt.Errorf("adding rule: for subnet %s: %w", subnet, err)
 		}
 
-		r.outboundSubnets = append(r.outboundSubnets, subnet)
+		*state = append(*state, subnet)
 	}
 	return nil
 }
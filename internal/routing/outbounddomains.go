@@ -0,0 +1,43 @@
+package routing
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/qdm12/gluetun/internal/subnet"
+)
+
+// SetOutboundDomainRoutes routes the traffic to the IP addresses given
+// through the default route instead of the VPN, using the outbound
+// routing table. The IP addresses are the ones resolved for the outbound
+// domains, and routes for previous IP addresses not part of the addresses
+// given are removed.
+func (r *Routing) SetOutboundDomainRoutes(addresses []netip.Addr) (err error) {
+	defaultRoutes, err := r.DefaultRoutes()
+	if err != nil {
+		return err
+	}
+
+	subnets := make([]netip.Prefix, len(addresses))
+	for i, address := range addresses {
+		subnets[i] = netip.PrefixFrom(address, address.BitLen())
+	}
+
+	r.stateMutex.Lock()
+	defer r.stateMutex.Unlock()
+
+	subnetsToAdd, subnetsToRemove := subnet.FindSubnetsToChange(
+		r.outboundDomainIPs, subnets)
+
+	warnings := r.removeOutboundSubnets(&r.outboundDomainIPs, subnetsToRemove, defaultRoutes)
+	for _, warning := range warnings {
+		r.logger.Warn("cannot remove outdated outbound domain IP address from routing: " + warning)
+	}
+
+	err = r.addOutboundSubnets(&r.outboundDomainIPs, subnetsToAdd, defaultRoutes)
+	if err != nil {
+		return fmt.Errorf("adding outbound domain IP address to routes: %w", err)
+	}
+
+	return nil
+}
@@ -48,13 +48,20 @@ type Routing struct {
 	netLinker       NetLinker
 	logger          Logger
 	outboundSubnets []netip.Prefix
-	stateMutex      sync.RWMutex
+	// outboundDomainIPs are the single IP address prefixes
+	// resolved for the outbound domains.
+	outboundDomainIPs []netip.Prefix
+	// tunnelSources maps the routing table of each additional
+	// VPN tunnel to the sources routed through it.
+	tunnelSources map[int][]netip.Prefix
+	stateMutex    sync.RWMutex
 }
 
 // New creates a new routing instance.
 func New(netLinker NetLinker, logger Logger) *Routing {
 	return &Routing{
-		netLinker: netLinker,
-		logger:    logger,
+		netLinker:     netLinker,
+		logger:        logger,
+		tunnelSources: make(map[int][]netip.Prefix),
 	}
 }
@@ -0,0 +1,55 @@
+package routing
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/qdm12/gluetun/internal/subnet"
+)
+
+// tunnelPriority is the priority of the rules routing traffic through
+// the additional VPN tunnels. It comes after the local and outbound
+// subnets rules, after the inbound rule and before the main Wireguard
+// tunnel rule, so the rules order does not depend on their insertion order.
+const tunnelPriority = 101
+
+// SetTunnelRules sets the rules routing the traffic coming from the
+// sources given through the routing table given, which is the routing
+// table of an additional VPN tunnel. Rules for previous sources of the
+// table not part of the sources given are removed, such that calling it
+// without sources removes all the rules for the table.
+func (r *Routing) SetTunnelRules(table int, sources []netip.Prefix) (err error) {
+	r.stateMutex.Lock()
+	defer r.stateMutex.Unlock()
+
+	state := r.tunnelSources[table]
+	defer func() {
+		if len(state) == 0 {
+			delete(r.tunnelSources, table)
+			return
+		}
+		r.tunnelSources[table] = state
+	}()
+
+	sourcesToAdd, sourcesToRemove := subnet.FindSubnetsToChange(state, sources)
+
+	for _, source := range sourcesToRemove {
+		err = r.deleteIPRule(source, netip.Prefix{}, table, tunnelPriority)
+		if err != nil {
+			r.logger.Warn("cannot remove outdated tunnel rule: for source " +
+				source.String() + ": " + err.Error())
+			continue
+		}
+		state = subnet.RemoveSubnetFromSubnets(state, source)
+	}
+
+	for _, source := range sourcesToAdd {
+		err = r.addIPRule(source, netip.Prefix{}, table, tunnelPriority)
+		if err != nil {
+			return fmt.Errorf("adding rule: for source %s: %w", source, err)
+		}
+		state = append(state, source)
+	}
+
+	return nil
+}
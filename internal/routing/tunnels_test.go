@@ -0,0 +1,51 @@
+package routing
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/netlink"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Routing_SetTunnelRules(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	logger := NewMockLogger(ctrl)
+	logger.EXPECT().Debug(gomock.Any()).AnyTimes()
+	netLinker := NewMockNetLinker(ctrl)
+
+	r := New(netLinker, logger)
+
+	const table = 51821
+	tunnelAddress := netip.MustParsePrefix("10.2.0.2/32")
+	sourceSubnet := makeNetipPrefix(1)
+
+	tunnelAddressRule := makeIPRule(tunnelAddress, netip.Prefix{}, table, tunnelPriority)
+	sourceSubnetRule := makeIPRule(sourceSubnet, netip.Prefix{}, table, tunnelPriority)
+
+	netLinker.EXPECT().RuleList(netlink.FamilyAll).Return(nil, nil).Times(2)
+	netLinker.EXPECT().RuleAdd(tunnelAddressRule)
+	netLinker.EXPECT().RuleAdd(sourceSubnetRule)
+	err := r.SetTunnelRules(table, []netip.Prefix{tunnelAddress, sourceSubnet})
+	require.NoError(t, err)
+	assert.Equal(t, map[int][]netip.Prefix{
+		table: {tunnelAddress, sourceSubnet},
+	}, r.tunnelSources)
+
+	// Same sources do not change anything.
+	err = r.SetTunnelRules(table, []netip.Prefix{sourceSubnet, tunnelAddress})
+	require.NoError(t, err)
+
+	existingRules := []netlink.Rule{tunnelAddressRule, sourceSubnetRule}
+	netLinker.EXPECT().RuleList(netlink.FamilyAll).Return(existingRules, nil).Times(2)
+	netLinker.EXPECT().RuleDel(tunnelAddressRule)
+	netLinker.EXPECT().RuleDel(sourceSubnetRule)
+	err = r.SetTunnelRules(table, nil)
+	require.NoError(t, err)
+	assert.Empty(t, r.tunnelSources)
+}
@@ -0,0 +1,335 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+func newAllSettingsHandler(ctx context.Context, store *settingsStore,
+	loopers settingsLoopers, storage Storage, ipv6Supported bool,
+	w warner) http.Handler {
+	return &allSettingsHandler{
+		ctx:           ctx,
+		store:         store,
+		loopers:       loopers,
+		storage:       storage,
+		ipv6Supported: ipv6Supported,
+		warner:        w,
+	}
+}
+
+// settingsLoopers contains the objects settings sections are routed to.
+type settingsLoopers struct {
+	vpn         VPNLooper
+	dns         DNSLoop
+	httpProxy   HTTPProxyLooper
+	shadowsocks ShadowsocksLooper
+	publicIP    PublicIPLoop
+	updater     UpdaterLooper
+	firewall    Firewall
+	routing     Routing
+}
+
+type allSettingsHandler struct {
+	ctx context.Context //nolint:containedctx
+	// store holds the last known settings, for sections
+	// which cannot be obtained from the loopers.
+	store         *settingsStore
+	loopers       settingsLoopers
+	storage       Storage
+	ipv6Supported bool
+	warner        warner
+}
+
+func (h *allSettingsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.RequestURI = strings.TrimPrefix(r.RequestURI, "/settings")
+	// Query parameters are parsed from r.URL
+	r.RequestURI, _, _ = strings.Cut(r.RequestURI, "?")
+	switch r.RequestURI {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			h.getSettings(w)
+		case http.MethodPatch:
+			h.patchSettings(w, r)
+		default:
+			errMethodNotSupported(w, r.Method)
+		}
+	default:
+		errRouteNotSupported(w, r.RequestURI)
+	}
+}
+
+func (h *allSettingsHandler) getSettings(w http.ResponseWriter) {
+	h.store.mutex.Lock()
+	current := h.current()
+	h.store.mutex.Unlock()
+
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(current.Redacted()); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// current returns the current settings, obtaining each section
+// from its looper where possible. It must be called with the
+// store mutex locked.
+func (h *allSettingsHandler) current() (current settings.Settings) {
+	return currentSettings(h.store, h.loopers)
+}
+
+// currentSettings returns the current settings, obtaining each section
+// from its looper where possible, and the other sections from the store.
+// It must be called with the store mutex locked.
+func currentSettings(store *settingsStore, loopers settingsLoopers) (
+	current settings.Settings) {
+	current = store.settings
+	current.VPN = loopers.vpn.GetSettings()
+	current.DNS = loopers.dns.GetSettings()
+	current.HTTPProxy = loopers.httpProxy.GetSettings()
+	current.Shadowsocks = loopers.shadowsocks.GetSettings()
+	current.Updater = loopers.updater.GetSettings()
+	firewallState := loopers.firewall.GetState()
+	current.Firewall.Enabled = &firewallState.Enabled
+	current.Firewall.OutboundSubnets = firewallState.OutboundSubnets
+	return current
+}
+
+var errSettingNotRuntime = errors.New("setting cannot be changed at runtime")
+
+func (h *allSettingsHandler) patchSettings(w http.ResponseWriter, r *http.Request) {
+	var dryRun bool
+	if s := r.URL.Query().Get("dry_run"); s != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(s)
+		if err != nil {
+			err = fmt.Errorf("%w: dry_run: %w", errQueryParameterNotValid, err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	var patch settingsPatch
+	err := decoder.Decode(&patch)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if patch.Firewall != nil {
+		switch {
+		case patch.Firewall.VPNInputPorts != nil:
+			err = fmt.Errorf("%w: firewall vpn_input_ports", errSettingNotRuntime)
+		case patch.Firewall.Debug != nil:
+			err = fmt.Errorf("%w: firewall debug", errSettingNotRuntime)
+		case patch.Firewall.OutboundDomains != nil:
+			err = fmt.Errorf("%w: firewall outbound_domains", errSettingNotRuntime)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if patch.VPN != nil && patch.VPN.Tunnels != nil {
+		err = fmt.Errorf("%w: vpn tunnels", errSettingNotRuntime)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.store.mutex.Lock()
+	defer h.store.mutex.Unlock()
+
+	current := h.current()
+	updated := current
+	err = updated.OverrideWith(patch.toSettings(), h.storage, h.ipv6Supported, h.warner)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if dryRun {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, err = w.Write([]byte(updated.String()))
+		if err != nil {
+			h.warner.Warn("writing response: " + err.Error())
+		}
+		return
+	}
+
+	outcomes, err := h.apply(patch, current, updated)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.store.settings = updated
+	// Input ports already allowed through all the interfaces
+	// are not recorded as allowed by the user.
+	h.store.updateInputPortsSettings()
+
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomesWrapper{Outcomes: outcomes}); err != nil {
+		h.warner.Warn(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+// apply routes each settings section present in the patch to
+// its looper, and returns the outcome for each section.
+func (h *allSettingsHandler) apply(patch settingsPatch,
+	current, updated settings.Settings) (outcomes map[string]string, err error) {
+	outcomes = make(map[string]string)
+	if patch.VPN != nil {
+		outcomes["vpn"] = h.loopers.vpn.SetSettings(h.ctx, updated.VPN)
+	}
+	if patch.DNS != nil {
+		outcomes["dns"] = h.loopers.dns.SetSettings(h.ctx, updated.DNS)
+	}
+	if patch.HTTPProxy != nil {
+		outcomes["http_proxy"] = h.loopers.httpProxy.SetSettings(h.ctx, updated.HTTPProxy)
+	}
+	if patch.Shadowsocks != nil {
+		outcomes["shadowsocks"] = h.loopers.shadowsocks.SetSettings(h.ctx, updated.Shadowsocks)
+	}
+	if patch.Updater != nil {
+		outcomes["updater"] = h.loopers.updater.SetSettings(updated.Updater)
+	}
+	if patch.PublicIP != nil {
+		err = h.loopers.publicIP.UpdateWith(updated.PublicIP)
+		if err != nil {
+			return nil, fmt.Errorf("updating public ip settings: %w", err)
+		}
+		outcomes["public_ip"] = "settings updated"
+	}
+	if patch.Firewall != nil {
+		outcomes["firewall"], err = h.applyFirewall(current.Firewall, updated.Firewall)
+		if err != nil {
+			return nil, fmt.Errorf("updating firewall settings: %w", err)
+		}
+	}
+	return outcomes, nil
+}
+
+func (h *allSettingsHandler) applyFirewall(current,
+	updated settings.Firewall) (outcome string, err error) {
+	firewall := h.loopers.firewall
+	changed := false
+
+	if *current.Enabled != *updated.Enabled {
+		changed = true
+		err = firewall.SetEnabled(h.ctx, *updated.Enabled)
+		if err != nil {
+			return "", fmt.Errorf("setting enabled: %w", err)
+		}
+	}
+
+	if !reflect.DeepEqual(current.OutboundSubnets, updated.OutboundSubnets) {
+		changed = true
+		err = firewall.SetOutboundSubnets(h.ctx, updated.OutboundSubnets)
+		if err != nil {
+			return "", fmt.Errorf("setting outbound subnets: %w", err)
+		}
+		err = h.loopers.routing.SetOutboundRoutes(updated.OutboundSubnets)
+		if err != nil {
+			// Restore the previous firewall outbound subnets so the
+			// firewall and the routing stay consistent.
+			rollbackErr := firewall.SetOutboundSubnets(h.ctx, current.OutboundSubnets)
+			if rollbackErr != nil {
+				h.warner.Warn("restoring firewall outbound subnets: " + rollbackErr.Error())
+			}
+			return "", fmt.Errorf("setting outbound routes: %w", err)
+		}
+	}
+
+	// The current input ports are the ports allowed by the user, so only
+	// the interfaces recorded for them are removed, leaving untouched the
+	// same ports allowed for the VPN input ports or the forwarded port.
+	for _, port := range current.InputPorts {
+		if slices.Contains(updated.InputPorts, port) {
+			continue
+		}
+		changed = true
+		for _, intf := range h.store.inputPorts[port] {
+			err = firewall.RemoveAllowedPortOnInterface(h.ctx, port, intf)
+			if err != nil {
+				return "", fmt.Errorf("removing allowed port: %w", err)
+			}
+		}
+		h.store.removeInputPort(port)
+	}
+
+	state := firewall.GetState()
+	for _, port := range updated.InputPorts {
+		if slices.Contains(current.InputPorts, port) {
+			continue
+		}
+		changed = true
+		alreadyAllowed := inputPortInterfaces(state, port)
+		newInterfaces := make([]string, 0, len(state.DefaultInterfaces))
+		for _, intf := range state.DefaultInterfaces {
+			if slices.Contains(alreadyAllowed, intf) {
+				continue
+			}
+			err = firewall.SetAllowedPort(h.ctx, port, intf)
+			if err != nil {
+				h.store.addInputPort(port, newInterfaces)
+				return "", fmt.Errorf("setting allowed port: %w", err)
+			}
+			newInterfaces = append(newInterfaces, intf)
+		}
+		h.store.addInputPort(port, newInterfaces)
+	}
+
+	if !changed {
+		return "settings left unchanged", nil
+	}
+	return "settings updated", nil
+}
+
+// settingsPatch contains the settings sections which can be
+// patched at runtime. Sections left to nil are not patched.
+type settingsPatch struct {
+	VPN         *settings.VPN         `json:"vpn,omitempty"`
+	DNS         *settings.DNS         `json:"dns,omitempty"`
+	Firewall    *settings.Firewall    `json:"firewall,omitempty"`
+	HTTPProxy   *settings.HTTPProxy   `json:"http_proxy,omitempty"`
+	Shadowsocks *settings.Shadowsocks `json:"shadowsocks,omitempty"`
+	PublicIP    *settings.PublicIP    `json:"public_ip,omitempty"`
+	Updater     *settings.Updater     `json:"updater,omitempty"`
+}
+
+func (p settingsPatch) toSettings() (patch settings.Settings) {
+	if p.VPN != nil {
+		patch.VPN = *p.VPN
+	}
+	if p.DNS != nil {
+		patch.DNS = *p.DNS
+	}
+	if p.Firewall != nil {
+		patch.Firewall = *p.Firewall
+	}
+	if p.HTTPProxy != nil {
+		patch.HTTPProxy = *p.HTTPProxy
+	}
+	if p.Shadowsocks != nil {
+		patch.Shadowsocks = *p.Shadowsocks
+	}
+	if p.PublicIP != nil {
+		patch.PublicIP = *p.PublicIP
+	}
+	if p.Updater != nil {
+		patch.Updater = *p.Updater
+	}
+	return patch
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/firewall"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_allSettingsHandler_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	var allSettings settings.Settings
+	allSettings.VPN.OpenVPN.User = ptrTo("user")
+	allSettings.VPN.OpenVPN.Password = ptrTo("password")
+	allSettings.Firewall.InputPorts = []uint16{8000}
+	allSettings.SetDefaults()
+	redactedJSON, err := json.Marshal(allSettings.Redacted())
+	require.NoError(t, err)
+
+	firewallState := firewall.State{
+		Enabled:         true,
+		OutboundSubnets: allSettings.Firewall.OutboundSubnets,
+		InputPorts: []firewall.InputPort{
+			{Port: 8000, Interfaces: []string{"eth0"}},
+			{Port: 1000, Interfaces: []string{"tun0"}},
+		},
+		DefaultInterfaces: []string{"eth0"},
+	}
+
+	testCases := map[string]struct {
+		method           string
+		path             string
+		body             string
+		makeFirewallCall func(firewall *MockFirewall)
+		statusCode       int
+		responseBody     string
+		inputPorts       map[uint16][]string
+	}{
+		"get_settings": {
+			method:       http.MethodGet,
+			path:         "/settings",
+			statusCode:   http.StatusOK,
+			responseBody: string(redactedJSON) + "\n",
+			inputPorts:   map[uint16][]string{8000: {"eth0"}},
+		},
+		"patch_input_ports": {
+			method: http.MethodPatch,
+			path:   "/settings",
+			body:   `{"firewall":{"input_ports":[1000,2000]}}`,
+			makeFirewallCall: func(firewall *MockFirewall) {
+				firewall.EXPECT().RemoveAllowedPortOnInterface(gomock.Any(), uint16(8000), "eth0").Return(nil)
+				firewall.EXPECT().SetAllowedPort(gomock.Any(), uint16(1000), "eth0").Return(nil)
+				firewall.EXPECT().SetAllowedPort(gomock.Any(), uint16(2000), "eth0").Return(nil)
+			},
+			statusCode:   http.StatusOK,
+			responseBody: `{"outcomes":{"firewall":"settings updated"}}` + "\n",
+			inputPorts:   map[uint16][]string{1000: {"eth0"}, 2000: {"eth0"}},
+		},
+		"patch_invalid_dry_run": {
+			method:       http.MethodPatch,
+			path:         "/settings?dry_run=x",
+			body:         `{}`,
+			statusCode:   http.StatusBadRequest,
+			responseBody: `query parameter is not valid: dry_run: strconv.ParseBool: parsing "x": invalid syntax` + "\n",
+			inputPorts:   map[uint16][]string{8000: {"eth0"}},
+		},
+		"patch_malformed_body": {
+			method:       http.MethodPatch,
+			path:         "/settings",
+			body:         `{`,
+			statusCode:   http.StatusBadRequest,
+			responseBody: "unexpected EOF\n",
+			inputPorts:   map[uint16][]string{8000: {"eth0"}},
+		},
+		"patch_setting_not_runtime": {
+			method:       http.MethodPatch,
+			path:         "/settings",
+			body:         `{"firewall":{"vpn_input_ports":[1000]}}`,
+			statusCode:   http.StatusBadRequest,
+			responseBody: "setting cannot be changed at runtime: firewall vpn_input_ports\n",
+			inputPorts:   map[uint16][]string{8000: {"eth0"}},
+		},
+		"patch_settings_not_valid": {
+			method:     http.MethodPatch,
+			path:       "/settings",
+			body:       `{"firewall":{"outbound_subnets":["0.0.0.0/0"]}}`,
+			statusCode: http.StatusBadRequest,
+			responseBody: "firewall settings: outbound subnet has an " +
+				"unspecified address: 0.0.0.0/0\n",
+			inputPorts: map[uint16][]string{8000: {"eth0"}},
+		},
+		"method_not_supported": {
+			method:       http.MethodPost,
+			path:         "/settings",
+			statusCode:   http.StatusBadRequest,
+			responseBody: "method POST not supported\n",
+			inputPorts:   map[uint16][]string{8000: {"eth0"}},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			vpn := NewMockVPNLooper(ctrl)
+			vpn.EXPECT().GetSettings().Return(allSettings.VPN).AnyTimes()
+			dns := NewMockDNSLoop(ctrl)
+			dns.EXPECT().GetSettings().Return(allSettings.DNS).AnyTimes()
+			httpProxy := NewMockHTTPProxyLooper(ctrl)
+			httpProxy.EXPECT().GetSettings().Return(allSettings.HTTPProxy).AnyTimes()
+			shadowsocks := NewMockShadowsocksLooper(ctrl)
+			shadowsocks.EXPECT().GetSettings().Return(allSettings.Shadowsocks).AnyTimes()
+			updater := NewMockUpdaterLooper(ctrl)
+			updater.EXPECT().GetSettings().Return(allSettings.Updater).AnyTimes()
+			firewall := NewMockFirewall(ctrl)
+			firewall.EXPECT().GetState().Return(firewallState).AnyTimes()
+			if testCase.makeFirewallCall != nil {
+				testCase.makeFirewallCall(firewall)
+			}
+			storage := NewMockStorage(ctrl)
+			storage.EXPECT().GetFilterChoices(gomock.Any()).Return(models.FilterChoices{}).AnyTimes()
+			logger := NewMockLogger(ctrl)
+			logger.EXPECT().Warn(gomock.Any()).AnyTimes()
+
+			store := newSettingsStore(allSettings, []string{"eth0"})
+			loopers := settingsLoopers{
+				vpn:         vpn,
+				dns:         dns,
+				httpProxy:   httpProxy,
+				shadowsocks: shadowsocks,
+				updater:     updater,
+				firewall:    firewall,
+			}
+			handler := newAllSettingsHandler(context.Background(), store,
+				loopers, storage, false, logger)
+
+			request := httptest.NewRequest(testCase.method, testCase.path,
+				strings.NewReader(testCase.body))
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, request)
+
+			response := recorder.Result()
+			defer response.Body.Close()
+			assert.Equal(t, testCase.statusCode, response.StatusCode)
+			responseBody, err := io.ReadAll(response.Body)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.responseBody, string(responseBody))
+			assert.Equal(t, testCase.inputPorts, store.inputPorts)
+		})
+	}
+}
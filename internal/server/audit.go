@@ -0,0 +1,129 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/server/middlewares/audit"
+)
+
+func newAuditHandler(auditLog AuditLog, w warner) http.Handler {
+	return &auditHandler{
+		auditLog: auditLog,
+		warner:   w,
+	}
+}
+
+type auditHandler struct {
+	// auditLog is nil if the audit log is disabled.
+	auditLog AuditLog
+	warner   warner
+}
+
+func (h *auditHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.RequestURI = strings.TrimPrefix(r.RequestURI, "/audit")
+	// Query parameters are parsed from r.URL
+	r.RequestURI, _, _ = strings.Cut(r.RequestURI, "?")
+	switch r.RequestURI {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			h.getEntries(w, r)
+		default:
+			errMethodNotSupported(w, r.Method)
+		}
+	default:
+		errRouteNotSupported(w, r.RequestURI)
+	}
+}
+
+const auditDefaultLimit = 100
+
+func (h *auditHandler) getEntries(w http.ResponseWriter, r *http.Request) {
+	if h.auditLog == nil {
+		http.Error(w, "audit log is disabled", http.StatusNotFound)
+		return
+	}
+
+	query, err := parseAuditQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.auditLog.Query(query)
+	if err != nil {
+		h.warner.Warn("querying audit log: " + err.Error())
+		http.Error(w, "querying audit log", http.StatusInternalServerError)
+		return
+	}
+
+	encoder := json.NewEncoder(w)
+	data := auditWrapper{Entries: entries}
+	if err := encoder.Encode(data); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func parseAuditQuery(values url.Values) (query audit.Query, err error) {
+	query.Role = values.Get("role")
+	query.Route = values.Get("route")
+
+	if since := values.Get("since"); since != "" {
+		query.Since, err = time.Parse(time.RFC3339, since)
+		if err != nil {
+			return query, fmt.Errorf("%w: since: %w", errQueryParameterNotValid, err)
+		}
+	}
+
+	query.Limit, err = queryNonNegativeInt(values, "limit", auditDefaultLimit)
+	if err != nil {
+		return query, err
+	}
+
+	return query, nil
+}
+
+func newAuditSnapshotter(store *settingsStore, loopers settingsLoopers) *auditSnapshotter {
+	return &auditSnapshotter{
+		store:   store,
+		loopers: loopers,
+	}
+}
+
+// auditSnapshotter obtains the state compared by the audit
+// middleware before and after each state changing request.
+type auditSnapshotter struct {
+	store   *settingsStore
+	loopers settingsLoopers
+}
+
+// auditState is the state compared by the audit middleware.
+type auditState struct {
+	Settings      settings.Settings `json:"settings"`
+	VPNStatus     models.LoopStatus `json:"vpn_status"`
+	DNSStatus     models.LoopStatus `json:"dns_status"`
+	UpdaterStatus models.LoopStatus `json:"updater_status"`
+}
+
+// Snapshot returns the current state, with the secret
+// values of the settings redacted.
+func (s *auditSnapshotter) Snapshot() (state any) {
+	s.store.mutex.Lock()
+	current := currentSettings(s.store, s.loopers)
+	s.store.mutex.Unlock()
+	return auditState{
+		Settings:      current.Redacted(),
+		VPNStatus:     s.loopers.vpn.GetStatus(),
+		DNSStatus:     s.loopers.dns.GetStatus(),
+		UpdaterStatus: s.loopers.updater.GetStatus(),
+	}
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/firewall"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_auditSnapshotter_Snapshot(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	var allSettings settings.Settings
+	allSettings.VPN.OpenVPN.User = ptrTo("account-number")
+	allSettings.VPN.OpenVPN.Password = ptrTo("password")
+	allSettings.SetDefaults()
+
+	vpn := NewMockVPNLooper(ctrl)
+	vpn.EXPECT().GetSettings().Return(allSettings.VPN)
+	vpn.EXPECT().GetStatus().Return(constants.Running)
+	dns := NewMockDNSLoop(ctrl)
+	dns.EXPECT().GetSettings().Return(allSettings.DNS)
+	dns.EXPECT().GetStatus().Return(constants.Stopped)
+	httpProxy := NewMockHTTPProxyLooper(ctrl)
+	httpProxy.EXPECT().GetSettings().Return(allSettings.HTTPProxy)
+	shadowsocks := NewMockShadowsocksLooper(ctrl)
+	shadowsocks.EXPECT().GetSettings().Return(allSettings.Shadowsocks)
+	updater := NewMockUpdaterLooper(ctrl)
+	updater.EXPECT().GetSettings().Return(allSettings.Updater)
+	updater.EXPECT().GetStatus().Return(constants.Completed)
+	firewallLooper := NewMockFirewall(ctrl)
+	firewallLooper.EXPECT().GetState().Return(firewall.State{Enabled: true})
+
+	store := newSettingsStore(allSettings, nil)
+	snapshotter := newAuditSnapshotter(store, settingsLoopers{
+		vpn:         vpn,
+		dns:         dns,
+		httpProxy:   httpProxy,
+		shadowsocks: shadowsocks,
+		updater:     updater,
+		firewall:    firewallLooper,
+	})
+
+	state := snapshotter.Snapshot()
+
+	auditState, ok := state.(auditState)
+	require.True(t, ok)
+	assert.Equal(t, settings.RedactedValue, *auditState.Settings.VPN.OpenVPN.User)
+	assert.Equal(t, settings.RedactedValue, *auditState.Settings.VPN.OpenVPN.Password)
+	assert.Equal(t, constants.Running, auditState.VPNStatus)
+	assert.Equal(t, constants.Stopped, auditState.DNSStatus)
+	assert.Equal(t, constants.Completed, auditState.UpdaterStatus)
+}
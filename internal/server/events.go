@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func newEventsHandler(ctx context.Context, subscriber EventSubscriber,
+	w warner) http.Handler {
+	return &eventsHandler{
+		ctx:        ctx,
+		subscriber: subscriber,
+		warner:     w,
+	}
+}
+
+type eventsHandler struct {
+	ctx        context.Context //nolint:containedctx
+	subscriber EventSubscriber
+	warner     warner
+}
+
+func (h *eventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.RequestURI = strings.TrimPrefix(r.RequestURI, "/events")
+	switch r.RequestURI {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			h.streamEvents(w, r)
+		default:
+			errMethodNotSupported(w, r.Method)
+		}
+	default:
+		errRouteNotSupported(w, r.RequestURI)
+	}
+}
+
+// streamEvents streams events to the client using server-sent events,
+// until either the client disconnects or the server is shut down.
+func (h *eventsHandler) streamEvents(w http.ResponseWriter, r *http.Request) {
+	controller := http.NewResponseController(w)
+	// Clear the server read deadline since this is a long lived connection.
+	err := controller.SetReadDeadline(time.Time{})
+	if err != nil {
+		h.warner.Warn("clearing read deadline: " + err.Error())
+	}
+
+	events, unsubscribe := h.subscriber.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	err = controller.Flush()
+	if err != nil {
+		h.warner.Warn("flushing response: " + err.Error())
+		return
+	}
+
+	const keepAlivePeriod = 30 * time.Second
+	keepAliveTicker := time.NewTicker(keepAlivePeriod)
+	defer keepAliveTicker.Stop()
+
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-r.Context().Done():
+			return
+		case <-keepAliveTicker.C:
+			_, err = fmt.Fprint(w, ": keep-alive\n\n")
+		case event := <-events:
+			var data []byte
+			data, err = json.Marshal(event)
+			if err != nil {
+				h.warner.Warn("encoding event: " + err.Error())
+				continue
+			}
+			_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+		}
+
+		if err == nil {
+			err = controller.Flush()
+		}
+		if err != nil {
+			// client most likely disconnected
+			return
+		}
+	}
+}
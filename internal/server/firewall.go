@@ -0,0 +1,222 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/firewall"
+)
+
+func newFirewallHandler(ctx context.Context, firewall Firewall,
+	routing Routing, store *settingsStore, w warner) http.Handler {
+	return &firewallHandler{
+		ctx:      ctx,
+		firewall: firewall,
+		routing:  routing,
+		store:    store,
+		warner:   w,
+	}
+}
+
+type firewallHandler struct {
+	ctx      context.Context //nolint:containedctx
+	firewall Firewall
+	routing  Routing
+	store    *settingsStore
+	warner   warner
+}
+
+func (h *firewallHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.RequestURI = strings.TrimPrefix(r.RequestURI, "/firewall")
+	switch r.RequestURI {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			h.getState(w)
+		default:
+			errMethodNotSupported(w, r.Method)
+		}
+	case "/ports":
+		switch r.Method {
+		case http.MethodPut:
+			h.allowPort(w, r)
+		case http.MethodDelete:
+			h.removePort(w, r)
+		default:
+			errMethodNotSupported(w, r.Method)
+		}
+	case "/outbound_subnets":
+		switch r.Method {
+		case http.MethodPut:
+			h.setOutboundSubnets(w, r)
+		default:
+			errMethodNotSupported(w, r.Method)
+		}
+	default:
+		errRouteNotSupported(w, r.RequestURI)
+	}
+}
+
+func (h *firewallHandler) getState(w http.ResponseWriter) {
+	state := h.firewall.GetState()
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(state); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+var (
+	errPortNotSet       = errors.New("port is not set")
+	errPortNotRemovable = errors.New("port cannot be removed")
+)
+
+func inputPortInterfaces(state firewall.State, port uint16) (interfaces []string) {
+	for _, inputPort := range state.InputPorts {
+		if inputPort.Port == port {
+			return inputPort.Interfaces
+		}
+	}
+	return nil
+}
+
+func (h *firewallHandler) allowPort(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	var data firewallPortWrapper
+	if err := decoder.Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if data.Port == 0 {
+		http.Error(w, errPortNotSet.Error(), http.StatusBadRequest)
+		return
+	}
+
+	state := h.firewall.GetState()
+	interfaces := state.DefaultInterfaces
+	if data.Interface != "" {
+		interfaces = []string{data.Interface}
+	}
+
+	h.store.mutex.Lock()
+	defer h.store.mutex.Unlock()
+
+	// Only record the interfaces the port was not already allowed
+	// through, so removing the port later does not remove a port
+	// allowed for the VPN input ports or the forwarded port.
+	alreadyAllowed := inputPortInterfaces(state, data.Port)
+	newInterfaces := make([]string, 0, len(interfaces))
+	for _, intf := range interfaces {
+		if slices.Contains(alreadyAllowed, intf) {
+			continue
+		}
+		err := h.firewall.SetAllowedPort(h.ctx, data.Port, intf)
+		if err != nil {
+			h.store.addInputPort(data.Port, newInterfaces)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		newInterfaces = append(newInterfaces, intf)
+	}
+	h.store.addInputPort(data.Port, newInterfaces)
+
+	outcome := fmt.Sprintf("port %d allowed through %s",
+		data.Port, strings.Join(interfaces, ", "))
+	h.encodeOutcome(w, outcome)
+}
+
+func (h *firewallHandler) removePort(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	var data portWrapper
+	if err := decoder.Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if data.Port == 0 {
+		http.Error(w, errPortNotSet.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.store.mutex.Lock()
+	defer h.store.mutex.Unlock()
+
+	interfaces, ok := h.store.inputPorts[data.Port]
+	if !ok {
+		err := fmt.Errorf("%w: port %d is not allowed by the firewall input ports "+
+			"settings nor through the control server", errPortNotRemovable, data.Port)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	for _, intf := range interfaces {
+		err := h.firewall.RemoveAllowedPortOnInterface(h.ctx, data.Port, intf)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	h.store.removeInputPort(data.Port)
+
+	h.encodeOutcome(w, fmt.Sprintf("port %d removed", data.Port))
+}
+
+func (h *firewallHandler) setOutboundSubnets(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	var data subnetsWrapper
+	if err := decoder.Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	for _, subnet := range data.Subnets {
+		if subnet.Addr().IsUnspecified() {
+			err := fmt.Errorf("%w: %s", settings.ErrFirewallPublicOutboundSubnet, subnet)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	h.store.mutex.Lock()
+	defer h.store.mutex.Unlock()
+
+	err := h.firewall.SetOutboundSubnets(h.ctx, data.Subnets)
+	if err != nil {
+		http.Error(w, "setting firewall outbound subnets: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	err = h.routing.SetOutboundRoutes(data.Subnets)
+	if err != nil {
+		// Restore the previous firewall outbound subnets so the
+		// firewall and the routing stay consistent.
+		previous := h.store.settings.Firewall.OutboundSubnets
+		rollbackErr := h.firewall.SetOutboundSubnets(h.ctx, previous)
+		if rollbackErr != nil {
+			h.warner.Warn("restoring firewall outbound subnets: " + rollbackErr.Error())
+		}
+		http.Error(w, "setting outbound routes: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	h.store.settings.Firewall.OutboundSubnets = data.Subnets
+
+	h.encodeOutcome(w, fmt.Sprintf("%d outbound subnets set", len(data.Subnets)))
+}
+
+func (h *firewallHandler) encodeOutcome(w http.ResponseWriter, outcome string) {
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.warner.Warn(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,249 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/firewall"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_firewallHandler_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+	state := firewall.State{
+		Enabled: true,
+		InputPorts: []firewall.InputPort{
+			{Port: 8000, Interfaces: []string{"eth0"}},
+			{Port: 9000, Interfaces: []string{"tun0"}},
+		},
+		DefaultInterfaces: []string{"eth0"},
+	}
+	stateJSON, err := json.Marshal(state)
+	require.NoError(t, err)
+	subnet := netip.MustParsePrefix("10.0.0.0/8")
+
+	testCases := map[string]struct {
+		method          string
+		path            string
+		body            string
+		makeFirewall    func(ctrl *gomock.Controller) *MockFirewall
+		makeRouting     func(ctrl *gomock.Controller) *MockRouting
+		statusCode      int
+		responseBody    string
+		inputPorts      map[uint16][]string
+		outboundSubnets []netip.Prefix
+	}{
+		"get_state": {
+			method: http.MethodGet,
+			path:   "/firewall",
+			makeFirewall: func(ctrl *gomock.Controller) *MockFirewall {
+				firewall := NewMockFirewall(ctrl)
+				firewall.EXPECT().GetState().Return(state)
+				return firewall
+			},
+			statusCode:   http.StatusOK,
+			responseBody: string(stateJSON) + "\n",
+			inputPorts:   map[uint16][]string{8000: {"eth0"}},
+		},
+		"allow_port_default_interfaces": {
+			method: http.MethodPut,
+			path:   "/firewall/ports",
+			body:   `{"port":1000}`,
+			makeFirewall: func(ctrl *gomock.Controller) *MockFirewall {
+				firewall := NewMockFirewall(ctrl)
+				firewall.EXPECT().GetState().Return(state)
+				firewall.EXPECT().SetAllowedPort(gomock.Any(), uint16(1000), "eth0").Return(nil)
+				return firewall
+			},
+			statusCode:   http.StatusOK,
+			responseBody: `{"outcome":"port 1000 allowed through eth0"}` + "\n",
+			inputPorts:   map[uint16][]string{8000: {"eth0"}, 1000: {"eth0"}},
+		},
+		"allow_port_already_allowed_on_interface": {
+			method: http.MethodPut,
+			path:   "/firewall/ports",
+			body:   `{"port":9000,"interface":"tun0"}`,
+			makeFirewall: func(ctrl *gomock.Controller) *MockFirewall {
+				firewall := NewMockFirewall(ctrl)
+				firewall.EXPECT().GetState().Return(state)
+				return firewall
+			},
+			statusCode:   http.StatusOK,
+			responseBody: `{"outcome":"port 9000 allowed through tun0"}` + "\n",
+			inputPorts:   map[uint16][]string{8000: {"eth0"}},
+		},
+		"allow_port_error": {
+			method: http.MethodPut,
+			path:   "/firewall/ports",
+			body:   `{"port":1000,"interface":"eth1"}`,
+			makeFirewall: func(ctrl *gomock.Controller) *MockFirewall {
+				firewall := NewMockFirewall(ctrl)
+				firewall.EXPECT().GetState().Return(state)
+				firewall.EXPECT().SetAllowedPort(gomock.Any(), uint16(1000), "eth1").Return(errTest)
+				return firewall
+			},
+			statusCode:   http.StatusInternalServerError,
+			responseBody: "test error\n",
+			inputPorts:   map[uint16][]string{8000: {"eth0"}},
+		},
+		"allow_port_not_set": {
+			method:       http.MethodPut,
+			path:         "/firewall/ports",
+			body:         `{"port":0}`,
+			statusCode:   http.StatusBadRequest,
+			responseBody: "port is not set\n",
+			inputPorts:   map[uint16][]string{8000: {"eth0"}},
+		},
+		"remove_port": {
+			method: http.MethodDelete,
+			path:   "/firewall/ports",
+			body:   `{"port":8000}`,
+			makeFirewall: func(ctrl *gomock.Controller) *MockFirewall {
+				firewall := NewMockFirewall(ctrl)
+				firewall.EXPECT().RemoveAllowedPortOnInterface(gomock.Any(), uint16(8000), "eth0").Return(nil)
+				return firewall
+			},
+			statusCode:   http.StatusOK,
+			responseBody: `{"outcome":"port 8000 removed"}` + "\n",
+			inputPorts:   map[uint16][]string{},
+		},
+		"remove_port_not_removable": {
+			method:     http.MethodDelete,
+			path:       "/firewall/ports",
+			body:       `{"port":9000}`,
+			statusCode: http.StatusBadRequest,
+			responseBody: "port cannot be removed: port 9000 is not allowed by the firewall " +
+				"input ports settings nor through the control server\n",
+			inputPorts: map[uint16][]string{8000: {"eth0"}},
+		},
+		"remove_port_error": {
+			method: http.MethodDelete,
+			path:   "/firewall/ports",
+			body:   `{"port":8000}`,
+			makeFirewall: func(ctrl *gomock.Controller) *MockFirewall {
+				firewall := NewMockFirewall(ctrl)
+				firewall.EXPECT().RemoveAllowedPortOnInterface(gomock.Any(), uint16(8000), "eth0").
+					Return(errTest)
+				return firewall
+			},
+			statusCode:   http.StatusInternalServerError,
+			responseBody: "test error\n",
+			inputPorts:   map[uint16][]string{8000: {"eth0"}},
+		},
+		"set_outbound_subnets": {
+			method: http.MethodPut,
+			path:   "/firewall/outbound_subnets",
+			body:   `{"subnets":["10.0.0.0/8"]}`,
+			makeFirewall: func(ctrl *gomock.Controller) *MockFirewall {
+				firewall := NewMockFirewall(ctrl)
+				firewall.EXPECT().SetOutboundSubnets(gomock.Any(), []netip.Prefix{subnet}).Return(nil)
+				return firewall
+			},
+			makeRouting: func(ctrl *gomock.Controller) *MockRouting {
+				routing := NewMockRouting(ctrl)
+				routing.EXPECT().SetOutboundRoutes([]netip.Prefix{subnet}).Return(nil)
+				return routing
+			},
+			statusCode:      http.StatusOK,
+			responseBody:    `{"outcome":"1 outbound subnets set"}` + "\n",
+			inputPorts:      map[uint16][]string{8000: {"eth0"}},
+			outboundSubnets: []netip.Prefix{subnet},
+		},
+		"set_outbound_subnets_unspecified": {
+			method:       http.MethodPut,
+			path:         "/firewall/outbound_subnets",
+			body:         `{"subnets":["10.0.0.0/8","::/0"]}`,
+			statusCode:   http.StatusBadRequest,
+			responseBody: "outbound subnet has an unspecified address: ::/0\n",
+			inputPorts:   map[uint16][]string{8000: {"eth0"}},
+		},
+		"set_outbound_subnets_error": {
+			method: http.MethodPut,
+			path:   "/firewall/outbound_subnets",
+			body:   `{"subnets":["10.0.0.0/8"]}`,
+			makeFirewall: func(ctrl *gomock.Controller) *MockFirewall {
+				firewall := NewMockFirewall(ctrl)
+				firewall.EXPECT().SetOutboundSubnets(gomock.Any(), []netip.Prefix{subnet}).Return(errTest)
+				return firewall
+			},
+			statusCode:   http.StatusInternalServerError,
+			responseBody: "setting firewall outbound subnets: test error\n",
+			inputPorts:   map[uint16][]string{8000: {"eth0"}},
+		},
+		"set_outbound_subnets_routing_error": {
+			method: http.MethodPut,
+			path:   "/firewall/outbound_subnets",
+			body:   `{"subnets":["10.0.0.0/8"]}`,
+			makeFirewall: func(ctrl *gomock.Controller) *MockFirewall {
+				firewall := NewMockFirewall(ctrl)
+				firewall.EXPECT().SetOutboundSubnets(gomock.Any(), []netip.Prefix{subnet}).Return(nil)
+				firewall.EXPECT().SetOutboundSubnets(gomock.Any(), []netip.Prefix(nil)).Return(nil)
+				return firewall
+			},
+			makeRouting: func(ctrl *gomock.Controller) *MockRouting {
+				routing := NewMockRouting(ctrl)
+				routing.EXPECT().SetOutboundRoutes([]netip.Prefix{subnet}).Return(errTest)
+				return routing
+			},
+			statusCode:   http.StatusInternalServerError,
+			responseBody: "setting outbound routes: test error\n",
+			inputPorts:   map[uint16][]string{8000: {"eth0"}},
+		},
+		"route_not_supported": {
+			method:       http.MethodGet,
+			path:         "/firewall/x",
+			statusCode:   http.StatusBadRequest,
+			responseBody: "route /x not supported\n",
+			inputPorts:   map[uint16][]string{8000: {"eth0"}},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			var firewall Firewall
+			if testCase.makeFirewall != nil {
+				firewall = testCase.makeFirewall(ctrl)
+			}
+			var routing Routing
+			if testCase.makeRouting != nil {
+				routing = testCase.makeRouting(ctrl)
+			}
+			store := newSettingsStore(settings.Settings{
+				Firewall: settings.Firewall{InputPorts: []uint16{8000}},
+			}, []string{"eth0"})
+			handler := newFirewallHandler(context.Background(), firewall,
+				routing, store, NewMockLogger(ctrl))
+
+			request := httptest.NewRequest(testCase.method, testCase.path,
+				strings.NewReader(testCase.body))
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, request)
+
+			response := recorder.Result()
+			defer response.Body.Close()
+			assert.Equal(t, testCase.statusCode, response.StatusCode)
+			responseBody, err := io.ReadAll(response.Body)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.responseBody, string(responseBody))
+			assert.Equal(t, testCase.inputPorts, store.inputPorts)
+			assert.Equal(t, testCase.outboundSubnets, store.settings.Firewall.OutboundSubnets)
+		})
+	}
+}
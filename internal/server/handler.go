@@ -6,42 +6,87 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/server/middlewares/audit"
 	"github.com/qdm12/gluetun/internal/server/middlewares/auth"
 	"github.com/qdm12/gluetun/internal/server/middlewares/log"
+	"github.com/qdm12/gluetun/internal/server/middlewares/validate"
 )
 
 func newHandler(ctx context.Context, logger Logger, logging bool,
-	authSettings auth.Settings,
+	allSettings settings.Settings,
 	buildInfo models.BuildInformation,
 	vpnLooper VPNLooper,
-	pfGetter PortForwardedGetter,
+	pfLooper PortForwardLooper,
 	dnsLooper DNSLoop,
 	updaterLooper UpdaterLooper,
 	publicIPLooper PublicIPLoop,
+	httpProxyLooper HTTPProxyLooper,
+	shadowsocksLooper ShadowsocksLooper,
+	healthChecker HealthChecker,
+	eventSubscriber EventSubscriber,
+	firewall Firewall,
+	routing Routing,
 	storage Storage,
 	ipv6Supported bool,
 ) (httpHandler http.Handler, err error) {
 	handler := &handler{}
 
 	vpn := newVPNHandler(ctx, vpnLooper, storage, ipv6Supported, logger)
-	openvpn := newOpenvpnHandler(ctx, vpnLooper, pfGetter, logger)
+	openvpn := newOpenvpnHandler(ctx, vpnLooper, pfLooper, logger)
+	portForward := newPortForwardHandler(pfLooper, logger)
+	servers := newServersHandler(vpnLooper, storage, logger)
+	store := newSettingsStore(allSettings, firewall.GetState().DefaultInterfaces)
+	firewallHandler := newFirewallHandler(ctx, firewall, routing, store, logger)
+	loopers := settingsLoopers{
+		vpn:         vpnLooper,
+		dns:         dnsLooper,
+		httpProxy:   httpProxyLooper,
+		shadowsocks: shadowsocksLooper,
+		publicIP:    publicIPLooper,
+		updater:     updaterLooper,
+		firewall:    firewall,
+		routing:     routing,
+	}
+	settingsHandler := newAllSettingsHandler(ctx, store, loopers,
+		storage, ipv6Supported, logger)
 	dns := newDNSHandler(ctx, dnsLooper, logger)
 	updater := newUpdaterHandler(ctx, updaterLooper, logger)
 	publicip := newPublicIPHandler(publicIPLooper, logger)
+	events := newEventsHandler(ctx, eventSubscriber, logger)
+	health := newHealthHandler(healthChecker, logger)
+
+	var auditFile *audit.File
+	var auditLog AuditLog // nil interface if the audit log is disabled
+	if *allSettings.ControlServer.AuditFilepath != "" {
+		auditFile = audit.NewFile(*allSettings.ControlServer.AuditFilepath)
+		auditLog = auditFile
+	}
+	auditHandler := newAuditHandler(auditLog, logger)
 
 	handler.v0 = newHandlerV0(ctx, logger, vpnLooper, dnsLooper, updaterLooper)
-	handler.v1 = newHandlerV1(logger, buildInfo, vpn, openvpn, dns, updater, publicip)
+	openAPI := newOpenAPIDocument(buildInfo)
+	handler.v1 = newHandlerV1(logger, buildInfo, openAPI,
+		vpn, openvpn, portForward, servers, firewallHandler, settingsHandler, dns, updater, publicip,
+		auditHandler, events, health)
 
-	authMiddleware, err := auth.New(authSettings, logger)
+	authMiddleware, err := auth.New(allSettings.ControlServer.Auth, logger)
 	if err != nil {
 		return nil, fmt.Errorf("creating auth middleware: %w", err)
 	}
 
 	middlewares := []func(http.Handler) http.Handler{
+		validate.New(openAPI),
 		authMiddleware,
-		log.New(logger, logging),
 	}
+	if auditFile != nil {
+		// The audit middleware is placed before the authentication
+		// middleware so requests denied are recorded as well.
+		snapshotter := newAuditSnapshotter(store, loopers)
+		middlewares = append(middlewares, audit.New(auditFile, snapshotter, logger))
+	}
+	middlewares = append(middlewares, log.New(logger, logging))
 	httpHandler = handler
 	for _, middleware := range middlewares {
 		httpHandler = middleware(httpHandler)
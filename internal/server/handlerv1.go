@@ -7,45 +7,80 @@ import (
 	"strings"
 
 	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/server/openapi"
 )
 
 func newHandlerV1(w warner, buildInfo models.BuildInformation,
-	vpn, openvpn, dns, updater, publicip http.Handler) http.Handler {
+	openAPI *openapi.Document,
+	vpn, openvpn, portForward, servers, firewall, settings, dns, updater, publicip,
+	audit, events, health http.Handler) http.Handler {
 	return &handlerV1{
-		warner:    w,
-		buildInfo: buildInfo,
-		vpn:       vpn,
-		openvpn:   openvpn,
-		dns:       dns,
-		updater:   updater,
-		publicip:  publicip,
+		warner:      w,
+		buildInfo:   buildInfo,
+		openAPI:     openAPI,
+		vpn:         vpn,
+		openvpn:     openvpn,
+		portForward: portForward,
+		servers:     servers,
+		firewall:    firewall,
+		settings:    settings,
+		dns:         dns,
+		updater:     updater,
+		publicip:    publicip,
+		audit:       audit,
+		events:      events,
+		health:      health,
 	}
 }
 
 type handlerV1 struct {
-	warner    warner
-	buildInfo models.BuildInformation
-	vpn       http.Handler
-	openvpn   http.Handler
-	dns       http.Handler
-	updater   http.Handler
-	publicip  http.Handler
+	warner      warner
+	buildInfo   models.BuildInformation
+	openAPI     *openapi.Document
+	vpn         http.Handler
+	openvpn     http.Handler
+	portForward http.Handler
+	servers     http.Handler
+	firewall    http.Handler
+	settings    http.Handler
+	dns         http.Handler
+	updater     http.Handler
+	publicip    http.Handler
+	audit       http.Handler
+	events      http.Handler
+	health      http.Handler
 }
 
 func (h *handlerV1) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	switch {
 	case r.RequestURI == "/version" && r.Method == http.MethodGet:
 		h.getVersion(w)
+	case r.RequestURI == "/openapi.json" && r.Method == http.MethodGet:
+		h.getOpenAPI(w)
 	case strings.HasPrefix(r.RequestURI, "/vpn"):
 		h.vpn.ServeHTTP(w, r)
 	case strings.HasPrefix(r.RequestURI, "/openvpn"):
 		h.openvpn.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/portforward"):
+		h.portForward.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/servers"):
+		h.servers.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/firewall"):
+		h.firewall.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/settings"):
+		h.settings.ServeHTTP(w, r)
 	case strings.HasPrefix(r.RequestURI, "/dns"):
 		h.dns.ServeHTTP(w, r)
 	case strings.HasPrefix(r.RequestURI, "/updater"):
 		h.updater.ServeHTTP(w, r)
 	case strings.HasPrefix(r.RequestURI, "/publicip"):
 		h.publicip.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/audit"):
+		h.audit.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/events"):
+		h.events.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/health"):
+		h.health.ServeHTTP(w, r)
 	default:
 		errString := fmt.Sprintf("%s %s not found", r.Method, r.RequestURI)
 		http.Error(w, errString, http.StatusBadRequest)
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+func newHealthHandler(healthChecker HealthChecker, w warner) http.Handler {
+	return &healthHandler{
+		healthChecker: healthChecker,
+		warner:        w,
+	}
+}
+
+type healthHandler struct {
+	healthChecker HealthChecker
+	warner        warner
+}
+
+func (h *healthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.RequestURI = strings.TrimPrefix(r.RequestURI, "/health")
+	switch r.RequestURI {
+	case "/history":
+		switch r.Method {
+		case http.MethodGet:
+			h.getHistory(w)
+		default:
+			errMethodNotSupported(w, r.Method)
+		}
+	default:
+		errRouteNotSupported(w, r.RequestURI)
+	}
+}
+
+func (h *healthHandler) getHistory(w http.ResponseWriter) {
+	data := healthHistoryWrapper{Results: h.healthChecker.GetHistory()}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(data); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
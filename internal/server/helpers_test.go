@@ -0,0 +1,3 @@
+package server
+
+func ptrTo[T any](value T) *T { return &value }
@@ -2,9 +2,14 @@ package server
 
 import (
 	"context"
+	"net/netip"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/events"
+	"github.com/qdm12/gluetun/internal/firewall"
 	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/portforward/service"
+	"github.com/qdm12/gluetun/internal/server/middlewares/audit"
 )
 
 type VPNLooper interface {
@@ -12,23 +17,73 @@ type VPNLooper interface {
 	ApplyStatus(ctx context.Context, status models.LoopStatus) (
 		outcome string, err error)
 	GetSettings() (settings settings.VPN)
+	GetActiveSettings() (settings settings.VPN)
 	SetSettings(ctx context.Context, settings settings.VPN) (outcome string)
+	GetConnection() (connection models.Connection, ok bool)
+	RotateConnection(ctx context.Context) (outcome string, err error)
+	GetFailureHistory() (failures []models.ServerFailure)
+	GetEscalation() (escalation models.Escalation)
 }
 
 type DNSLoop interface {
 	ApplyStatus(ctx context.Context, status models.LoopStatus) (
 		outcome string, err error)
 	GetStatus() (status models.LoopStatus)
+	GetSettings() (settings settings.DNS)
+	SetSettings(ctx context.Context, settings settings.DNS) (outcome string)
+}
+
+type HTTPProxyLooper interface {
+	GetSettings() (settings settings.HTTPProxy)
+	SetSettings(ctx context.Context, settings settings.HTTPProxy) (outcome string)
+}
+
+type ShadowsocksLooper interface {
+	GetSettings() (settings settings.Shadowsocks)
+	SetSettings(ctx context.Context, settings settings.Shadowsocks) (outcome string)
 }
 
 type PortForwardedGetter interface {
 	GetPortsForwarded() (ports []uint16)
 }
 
+type PortForwardLooper interface {
+	GetPortsForwarded() (ports []uint16)
+	GetDetails() (details service.Details)
+	Refresh(requestNewPort bool) (err error)
+}
+
 type PublicIPLoop interface {
 	GetData() (data models.PublicIP)
+	UpdateWith(partialUpdate settings.PublicIP) (err error)
+}
+
+type EventSubscriber interface {
+	Subscribe() (events <-chan events.Event, unsubscribe func())
+}
+
+type Firewall interface {
+	GetState() (state firewall.State)
+	SetEnabled(ctx context.Context, enabled bool) (err error)
+	SetAllowedPort(ctx context.Context, port uint16, intf string) (err error)
+	RemoveAllowedPortOnInterface(ctx context.Context, port uint16, intf string) (err error)
+	SetOutboundSubnets(ctx context.Context, subnets []netip.Prefix) (err error)
+}
+
+type Routing interface {
+	SetOutboundRoutes(outboundSubnets []netip.Prefix) (err error)
 }
 
 type Storage interface {
 	GetFilterChoices(provider string) models.FilterChoices
+	FilterServers(provider string, selection settings.ServerSelection) (
+		servers []models.Server, err error)
+}
+
+type AuditLog interface {
+	Query(query audit.Query) (entries []audit.Entry, err error)
+}
+
+type HealthChecker interface {
+	GetHistory() (results []models.HealthResult)
 }
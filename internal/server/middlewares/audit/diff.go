@@ -0,0 +1,74 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// diff returns the changes between the JSON encoded state
+// before and the JSON encoded state after a request.
+// Nested objects are compared field by field, whereas
+// arrays are compared as a whole. It returns no change if
+// either state is not a valid JSON object. Secret values
+// must be redacted from the states given.
+func diff(before, after []byte) (changes []Change) {
+	beforeValues, ok := flattenJSON(before)
+	if !ok {
+		return nil
+	}
+	afterValues, ok := flattenJSON(after)
+	if !ok {
+		return nil
+	}
+
+	for path, beforeValue := range beforeValues {
+		afterValue, exists := afterValues[path]
+		if exists && reflect.DeepEqual(beforeValue, afterValue) {
+			continue
+		}
+		changes = append(changes, Change{Path: path, Before: beforeValue, After: afterValue})
+	}
+
+	for path, afterValue := range afterValues {
+		_, exists := beforeValues[path]
+		if exists {
+			continue
+		}
+		changes = append(changes, Change{Path: path, After: afterValue})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+func flattenJSON(data []byte) (values map[string]any, ok bool) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var object map[string]any
+	err := decoder.Decode(&object)
+	if err != nil || object == nil {
+		return nil, false
+	}
+	values = make(map[string]any)
+	flattenObject("", object, values)
+	return values, true
+}
+
+func flattenObject(prefix string, object map[string]any, values map[string]any) {
+	for key, value := range object {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		child, isObject := value.(map[string]any)
+		if isObject && len(child) > 0 {
+			flattenObject(path, child, values)
+			continue
+		}
+		values[path] = value
+	}
+}
@@ -0,0 +1,54 @@
+package audit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_diff(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		before  string
+		after   string
+		changes []Change
+	}{
+		"before_not_json": {
+			before: "stopped",
+			after:  `{"status":"running"}`,
+		},
+		"after_not_object": {
+			before: `{"status":"running"}`,
+			after:  `["running"]`,
+		},
+		"no_change": {
+			before: `{"status":"running","list":[1,2]}`,
+			after:  `{"list":[1,2],"status":"running"}`,
+		},
+		"changes": {
+			before: `{"status":"stopped","a":{"b":1,"c":[1],"removed":true}}`,
+			after:  `{"status":"running","a":{"b":2,"c":[1,2],"added":"x"}}`,
+			changes: []Change{
+				{Path: "a.added", After: "x"},
+				{Path: "a.b", Before: json.Number("1"), After: json.Number("2")},
+				{Path: "a.c", Before: []any{json.Number("1")},
+					After: []any{json.Number("1"), json.Number("2")}},
+				{Path: "a.removed", Before: true},
+				{Path: "status", Before: "stopped", After: "running"},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			changes := diff([]byte(testCase.before), []byte(testCase.after))
+
+			assert.Equal(t, testCase.changes, changes)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package audit
+
+import "time"
+
+// Entry is a structured audit entry recorded for each
+// state changing request made to the control server.
+type Entry struct {
+	// Time is the time the request was received.
+	Time time.Time `json:"time"`
+	// Role is the name of the authentication role
+	// authorized for the request, and is empty if
+	// the request was not authorized.
+	Role string `json:"role"`
+	// RemoteAddress is the remote address of the client.
+	RemoteAddress string `json:"remote_address"`
+	// Route is the request method and path, for example
+	// "PUT /v1/vpn/status".
+	Route string `json:"route"`
+	// Changes is the list of values changed by the request.
+	// It is empty if nothing changed, or if the state
+	// cannot be obtained. Secret values are redacted.
+	Changes []Change `json:"changes"`
+	// Outcome is the outcome of the request.
+	Outcome Outcome `json:"outcome"`
+}
+
+// Change is a value changed by a request.
+type Change struct {
+	// Path is the dot separated path of the JSON field changed,
+	// for example "settings.vpn.provider.server_selection.countries".
+	Path string `json:"path"`
+	// Before is the value before the request, and is nil
+	// if the field did not exist.
+	Before any `json:"before"`
+	// After is the value after the request, and is nil
+	// if the field no longer exists.
+	After any `json:"after"`
+}
+
+// Outcome is the outcome of a request.
+type Outcome struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int `json:"status_code"`
+	// Message is the response body, possibly truncated.
+	Message string `json:"message"`
+}
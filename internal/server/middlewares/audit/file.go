@@ -0,0 +1,187 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// File is a rotating file of audit entries, where each
+// line is a JSON encoded entry. Once the file exceeds its
+// maximum size, it is renamed with a .1 suffix, previous
+// backups are shifted and the oldest backup is removed.
+type File struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	mutex      sync.Mutex
+}
+
+// NewFile creates a rotating audit file at the given path,
+// rotating at 1MiB and keeping 3 backup files.
+func NewFile(path string) *File {
+	const defaultMaxSize = 1 << 20
+	const defaultMaxBackups = 3
+	return &File{
+		path:       path,
+		maxSize:    defaultMaxSize,
+		maxBackups: defaultMaxBackups,
+	}
+}
+
+var ErrEntryTooLarge = errors.New("entry is larger than the maximum file size")
+
+// Record appends the entry to the file, rotating
+// the file first if it would exceed its maximum size.
+func (f *File) Record(entry Entry) (err error) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encoding entry: %w", err)
+	}
+	line = append(line, '\n')
+	if int64(len(line)) > f.maxSize {
+		return fmt.Errorf("%w: %d bytes exceed %d bytes",
+			ErrEntryTooLarge, len(line), f.maxSize)
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	const directoryPermission = 0o700
+	err = os.MkdirAll(filepath.Dir(f.path), directoryPermission)
+	if err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+
+	fileInfo, err := os.Stat(f.path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("checking file size: %w", err)
+	case fileInfo.Size() > 0 && fileInfo.Size()+int64(len(line)) > f.maxSize:
+		err = f.rotate()
+		if err != nil {
+			return fmt.Errorf("rotating file: %w", err)
+		}
+	}
+
+	const filePermission = 0o600
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, filePermission)
+	if err != nil {
+		return fmt.Errorf("opening file: %w", err)
+	}
+
+	_, err = file.Write(line)
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("writing entry: %w", err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return fmt.Errorf("closing file: %w", err)
+	}
+	return nil
+}
+
+func (f *File) rotate() (err error) {
+	if f.maxBackups == 0 {
+		return os.Remove(f.path)
+	}
+
+	for i := f.maxBackups - 1; i > 0; i-- {
+		err = os.Rename(f.backupPath(i), f.backupPath(i+1))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return os.Rename(f.path, f.backupPath(1))
+}
+
+func (f *File) backupPath(index int) string {
+	return f.path + "." + strconv.Itoa(index)
+}
+
+// Query contains optional filters to select audit entries.
+type Query struct {
+	// Role, if not empty, only selects entries for this role name.
+	Role string
+	// Route, if not empty, only selects entries whose route
+	// contains this string, for example "/v1/vpn".
+	Route string
+	// Since, if not the zero time, only selects entries
+	// recorded at or after this time.
+	Since time.Time
+	// Limit, if not zero, only selects the most recent
+	// entries up to this number.
+	Limit int
+}
+
+func (q Query) matches(entry Entry) bool {
+	return (q.Role == "" || entry.Role == q.Role) &&
+		(q.Route == "" || strings.Contains(entry.Route, q.Route)) &&
+		(q.Since.IsZero() || !entry.Time.Before(q.Since))
+}
+
+// Query returns the entries matching the query from the file
+// and its backups, ordered from the oldest to the most recent.
+func (f *File) Query(query Query) (entries []Entry, err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	paths := make([]string, 0, f.maxBackups+1)
+	for i := f.maxBackups; i > 0; i-- {
+		paths = append(paths, f.backupPath(i))
+	}
+	paths = append(paths, f.path)
+
+	for _, path := range paths {
+		entries, err = f.readEntries(path, query, entries)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+	}
+
+	if query.Limit > 0 && len(entries) > query.Limit {
+		entries = entries[len(entries)-query.Limit:]
+	}
+	return entries, nil
+}
+
+func (f *File) readEntries(path string, query Query, entries []Entry) (
+	updatedEntries []Entry, err error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return entries, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, int(f.maxSize))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		var entry Entry
+		err = json.Unmarshal(scanner.Bytes(), &entry)
+		if err != nil {
+			return nil, fmt.Errorf("decoding line %d: %w", lineNumber, err)
+		}
+		if query.matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("scanning lines: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,62 @@
+package audit
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_File(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit", "audit.log")
+	file := NewFile(path)
+	file.maxSize = 400
+	file.maxBackups = 1
+
+	baseTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := make([]Entry, 5)
+	for i := range entries {
+		entries[i] = Entry{
+			Time:          baseTime.Add(time.Duration(i) * time.Minute),
+			Role:          "admin",
+			RemoteAddress: "1.2.3.4:5678",
+			Route:         "PUT /v1/vpn/status",
+			Outcome:       Outcome{StatusCode: 200},
+		}
+		if i%2 == 1 {
+			entries[i].Role = "other"
+			entries[i].Route = "PUT /v1/dns/status"
+		}
+		err := file.Record(entries[i])
+		require.NoError(t, err)
+	}
+
+	// Each entry is 166 bytes long, so the file is rotated
+	// every 2 entries and the oldest entry is dropped.
+	all, err := file.Query(Query{})
+	require.NoError(t, err)
+	assert.Equal(t, entries[2:], all)
+
+	filtered, err := file.Query(Query{Role: "admin"})
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{entries[2], entries[4]}, filtered)
+
+	filtered, err = file.Query(Query{Route: "/v1/dns"})
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{entries[3]}, filtered)
+
+	filtered, err = file.Query(Query{Since: entries[3].Time})
+	require.NoError(t, err)
+	assert.Equal(t, entries[3:], filtered)
+
+	filtered, err = file.Query(Query{Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, entries[4:], filtered)
+
+	err = file.Record(Entry{Outcome: Outcome{Message: string(make([]byte, 400))}})
+	assert.ErrorIs(t, err, ErrEntryTooLarge)
+}
@@ -0,0 +1,15 @@
+package audit
+
+type Recorder interface {
+	Record(entry Entry) error
+}
+
+type Warner interface {
+	Warn(message string)
+}
+
+// Snapshotter returns the current state, which must be JSON encodable
+// and have its secret values already redacted.
+type Snapshotter interface {
+	Snapshot() (state any)
+}
@@ -0,0 +1,141 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/server/middlewares/auth"
+)
+
+// New returns a middleware recording an audit entry with the recorder
+// given for each state changing request. It must be placed before the
+// authentication middleware, so requests denied are recorded as well.
+// Changes are found by comparing the state obtained from the snapshotter
+// given before and after the request is handled.
+func New(recorder Recorder, snapshotter Snapshotter, warner Warner) (
+	middleware func(http.Handler) http.Handler) {
+	return func(handler http.Handler) http.Handler {
+		return &auditMiddleware{
+			childHandler: handler,
+			recorder:     recorder,
+			snapshotter:  snapshotter,
+			warner:       warner,
+			timeNow:      time.Now,
+		}
+	}
+}
+
+type auditMiddleware struct {
+	childHandler http.Handler
+	recorder     Recorder
+	snapshotter  Snapshotter
+	warner       Warner
+	timeNow      func() time.Time
+}
+
+func (m *auditMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !isStateChanging(r) {
+		m.childHandler.ServeHTTP(w, r)
+		return
+	}
+
+	entry := Entry{
+		Time:          m.timeNow(),
+		RemoteAddress: r.RemoteAddr,
+		Route:         r.Method + " " + r.URL.Path,
+	}
+
+	ctx, role := auth.WithRoleRecorder(r.Context())
+	r = r.WithContext(ctx)
+
+	before := m.snapshot()
+
+	outcomeWriter := &outcomeResponseWriter{httpWriter: w}
+	m.childHandler.ServeHTTP(outcomeWriter, r)
+	entry.Outcome = outcomeWriter.outcome()
+	entry.Role, _ = role()
+
+	if before != nil {
+		after := m.snapshot()
+		entry.Changes = diff(before, after)
+	}
+
+	err := m.recorder.Record(entry)
+	if err != nil {
+		m.warner.Warn("recording audit entry: " + err.Error())
+	}
+}
+
+func isStateChanging(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet:
+		switch r.URL.Path {
+		case "/openvpn/actions/restart", "/unbound/actions/restart", "/updater/restart":
+			return true
+		default:
+			return false
+		}
+	case http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
+// snapshot returns the JSON encoded state obtained from the
+// snapshotter, or nil if it cannot be encoded.
+func (m *auditMiddleware) snapshot() (state []byte) {
+	state, err := json.Marshal(m.snapshotter.Snapshot())
+	if err != nil {
+		m.warner.Warn("encoding audit state: " + err.Error())
+		return nil
+	}
+	return state
+}
+
+type outcomeResponseWriter struct {
+	httpWriter http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (w *outcomeResponseWriter) Header() http.Header {
+	return w.httpWriter.Header()
+}
+
+func (w *outcomeResponseWriter) Write(b []byte) (n int, err error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	const maxMessageLength = 512
+	remaining := maxMessageLength - w.body.Len()
+	if remaining > 0 {
+		w.body.Write(b[:min(remaining, len(b))])
+	}
+	return w.httpWriter.Write(b)
+}
+
+func (w *outcomeResponseWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+	w.httpWriter.WriteHeader(statusCode)
+}
+
+// Unwrap returns the underlying response writer,
+// for use by http.ResponseController.
+func (w *outcomeResponseWriter) Unwrap() http.ResponseWriter {
+	return w.httpWriter
+}
+
+func (w *outcomeResponseWriter) outcome() Outcome {
+	statusCode := w.statusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	return Outcome{
+		StatusCode: statusCode,
+		Message:    strings.TrimSpace(w.body.String()),
+	}
+}
@@ -0,0 +1,107 @@
+package audit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/server/middlewares/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRecorder struct {
+	entries []Entry
+}
+
+func (r *testRecorder) Record(entry Entry) error {
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+type testSnapshotter struct {
+	status *string
+}
+
+func (s *testSnapshotter) Snapshot() (state any) {
+	return map[string]string{"status": *s.status}
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Debugf(string, ...any) {}
+func (noopLogger) Warnf(string, ...any)  {}
+
+func Test_auditMiddleware_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	status := "stopped"
+	childHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/v1/vpn/status":
+			body, _ := io.ReadAll(r.Body)
+			status = string(body)
+			_, _ = w.Write([]byte(`{"outcome":"` + status + `"}` + "\n"))
+		case r.Method == http.MethodPut && r.URL.Path == "/v1/other":
+			http.Error(w, "bad request", http.StatusBadRequest)
+		default:
+			http.Error(w, "route not supported", http.StatusBadRequest)
+		}
+	})
+
+	authMiddleware, err := auth.New(auth.Settings{
+		Roles: []auth.Role{{
+			Name:   "admin",
+			Auth:   auth.AuthNone,
+			Routes: []string{"GET /v1/vpn", "PUT /v1/vpn/status", "PUT /v1/other"},
+		}},
+	}, noopLogger{})
+	require.NoError(t, err)
+
+	recorder := &testRecorder{}
+	middleware := New(recorder, &testSnapshotter{status: &status}, nil)
+	handler := middleware(authMiddleware(childHandler)).(*auditMiddleware) //nolint:forcetypeassert
+	timeNow := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	handler.timeNow = func() time.Time { return timeNow }
+
+	requests := []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/v1/vpn", nil),
+		httptest.NewRequest(http.MethodPut, "/v1/vpn/status", strings.NewReader("running")),
+		httptest.NewRequest(http.MethodPut, "/v1/other", nil),
+		httptest.NewRequest(http.MethodPut, "/v1/denied", nil),
+	}
+	for _, request := range requests {
+		request = request.WithContext(context.Background())
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+	}
+
+	expectedEntries := []Entry{
+		{
+			Time:          timeNow,
+			Role:          "admin",
+			RemoteAddress: "192.0.2.1:1234",
+			Route:         "PUT /v1/vpn/status",
+			Changes:       []Change{{Path: "status", Before: "stopped", After: "running"}},
+			Outcome:       Outcome{StatusCode: http.StatusOK, Message: `{"outcome":"running"}`},
+		},
+		{
+			Time:          timeNow,
+			Role:          "admin",
+			RemoteAddress: "192.0.2.1:1234",
+			Route:         "PUT /v1/other",
+			Outcome:       Outcome{StatusCode: http.StatusBadRequest, Message: "bad request"},
+		},
+		{
+			Time:          timeNow,
+			RemoteAddress: "192.0.2.1:1234",
+			Route:         "PUT /v1/denied",
+			Outcome:       Outcome{StatusCode: http.StatusUnauthorized, Message: "Unauthorized"},
+		},
+	}
+	require.Len(t, recorder.entries, len(expectedEntries))
+	assert.Equal(t, expectedEntries, recorder.entries)
+}
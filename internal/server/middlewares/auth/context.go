@@ -0,0 +1,44 @@
+package auth
+
+import "context"
+
+type roleContextKey struct{}
+
+// RoleFromContext returns the name of the role authorized for the
+// request, given the request context. It returns false if the
+// request did not go through the authentication middleware.
+func RoleFromContext(ctx context.Context) (name string, ok bool) {
+	name, ok = ctx.Value(roleContextKey{}).(string)
+	return name, ok
+}
+
+func withRole(ctx context.Context, name string) context.Context {
+	recorder, ok := ctx.Value(roleRecorderContextKey{}).(*roleRecorder)
+	if ok {
+		recorder.name = name
+		recorder.authorized = true
+	}
+	return context.WithValue(ctx, roleContextKey{}, name)
+}
+
+type roleRecorderContextKey struct{}
+
+type roleRecorder struct {
+	name       string
+	authorized bool
+}
+
+// WithRoleRecorder returns a copy of the context given in which the
+// authentication middleware records the role it authorizes, for use by
+// middlewares placed before the authentication middleware. The role
+// function returned gives the name of the role authorized, and false
+// if the request was not authorized.
+func WithRoleRecorder(ctx context.Context) (recorderCtx context.Context,
+	role func() (name string, authorized bool)) {
+	recorder := &roleRecorder{}
+	recorderCtx = context.WithValue(ctx, roleRecorderContextKey{}, recorder)
+	role = func() (name string, authorized bool) {
+		return recorder.name, recorder.authorized
+	}
+	return recorderCtx, role
+}
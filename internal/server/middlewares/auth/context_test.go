@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WithRoleRecorder(t *testing.T) {
+	t.Parallel()
+
+	ctx, role := WithRoleRecorder(context.Background())
+
+	name, authorized := role()
+	assert.Empty(t, name)
+	assert.False(t, authorized)
+
+	ctx = withRole(ctx, "admin")
+
+	name, authorized = role()
+	assert.Equal(t, "admin", name)
+	assert.True(t, authorized)
+	name, ok := RoleFromContext(ctx)
+	assert.Equal(t, "admin", name)
+	assert.True(t, ok)
+}
@@ -20,6 +20,8 @@ func settingsToLookupMap(settings Settings) (routeToRoles map[string][]internalR
 			checker = newAPIKeyMethod(role.APIKey)
 		case AuthBasic:
 			checker = newBasicAuthMethod(role.Username, role.Password)
+		case AuthMTLS:
+			checker = newMTLSMethod(role.Subjects)
 		default:
 			return nil, fmt.Errorf("%w: %s", ErrMethodNotSupported, role.Auth)
 		}
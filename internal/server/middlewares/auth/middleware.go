@@ -66,6 +66,7 @@ func (h *authHandler) ServeHTTP(writer http.ResponseWriter, request *http.Reques
 		h.warnIfUnprotectedByDefault(role, route) // TODO v3.41.0 remove
 
 		h.logger.Debugf("access to route %s authorized for role %s", route, role.name)
+		request = request.WithContext(withRole(request.Context(), role.name))
 		h.childHandler.ServeHTTP(writer, request)
 		return
 	}
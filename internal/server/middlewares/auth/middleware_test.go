@@ -60,6 +60,7 @@ func Test_authHandler_ServeHTTP(t *testing.T) {
 			requestMethod: http.MethodGet,
 			requestPath:   "/v1/vpn/status",
 			statusCode:    http.StatusOK,
+			responseBody:  "public",
 		},
 		"authorized_none": {
 			settings: Settings{
@@ -76,6 +77,7 @@ func Test_authHandler_ServeHTTP(t *testing.T) {
 			requestMethod: http.MethodGet,
 			requestPath:   "/a",
 			statusCode:    http.StatusOK,
+			responseBody:  "role1",
 		},
 	}
 
@@ -92,8 +94,9 @@ func Test_authHandler_ServeHTTP(t *testing.T) {
 			middleware, err := New(testCase.settings, debugLogger)
 			require.NoError(t, err)
 
-			childHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
-				w.WriteHeader(http.StatusOK)
+			childHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				role, _ := RoleFromContext(r.Context())
+				_, _ = w.Write([]byte(role))
 			})
 			handler := middleware(childHandler)
 
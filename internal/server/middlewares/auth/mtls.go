@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"net/http"
+)
+
+type mtlsMethod struct {
+	subjects map[string]struct{}
+}
+
+func newMTLSMethod(subjects []string) *mtlsMethod {
+	subjectsSet := make(map[string]struct{}, len(subjects))
+	for _, subject := range subjects {
+		subjectsSet[subject] = struct{}{}
+	}
+	return &mtlsMethod{
+		subjects: subjectsSet,
+	}
+}
+
+// equal returns true if another auth checker is equal.
+// This is used to deduplicate checkers for a particular route.
+func (m *mtlsMethod) equal(other authorizationChecker) bool {
+	otherMTLSMethod, ok := other.(*mtlsMethod)
+	if !ok || len(m.subjects) != len(otherMTLSMethod.subjects) {
+		return false
+	}
+	for subject := range m.subjects {
+		_, ok := otherMTLSMethod.subjects[subject]
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// isAuthorized returns true if the request was made over TLS
+// with a client certificate verified by the server, and whose
+// subject distinguished name or common name is one of the
+// method subjects.
+func (m *mtlsMethod) isAuthorized(request *http.Request) bool {
+	if request.TLS == nil || len(request.TLS.VerifiedChains) == 0 ||
+		len(request.TLS.VerifiedChains[0]) == 0 {
+		return false
+	}
+	clientCertificate := request.TLS.VerifiedChains[0][0]
+	_, ok := m.subjects[clientCertificate.Subject.String()]
+	if ok {
+		return true
+	}
+	if clientCertificate.Subject.CommonName == "" {
+		return false
+	}
+	_, ok = m.subjects[clientCertificate.Subject.CommonName]
+	return ok
+}
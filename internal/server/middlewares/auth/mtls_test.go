@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_mtlsMethod_isAuthorized(t *testing.T) {
+	t.Parallel()
+
+	makeTLSState := func(subject pkix.Name) *tls.ConnectionState {
+		return &tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{{Subject: subject}}},
+		}
+	}
+
+	testCases := map[string]struct {
+		subjects   []string
+		tlsState   *tls.ConnectionState
+		authorized bool
+	}{
+		"no_tls": {
+			subjects: []string{"alice"},
+		},
+		"no_verified_chain": {
+			subjects: []string{"alice"},
+			tlsState: &tls.ConnectionState{},
+		},
+		"common_name_match": {
+			subjects:   []string{"bob", "alice"},
+			tlsState:   makeTLSState(pkix.Name{CommonName: "alice", Organization: []string{"Acme"}}),
+			authorized: true,
+		},
+		"distinguished_name_match": {
+			subjects:   []string{"CN=alice,O=Acme"},
+			tlsState:   makeTLSState(pkix.Name{CommonName: "alice", Organization: []string{"Acme"}}),
+			authorized: true,
+		},
+		"no_match": {
+			subjects: []string{"CN=alice,O=Other"},
+			tlsState: makeTLSState(pkix.Name{CommonName: "alice", Organization: []string{"Acme"}}),
+		},
+		"empty_common_name": {
+			subjects: []string{""},
+			tlsState: makeTLSState(pkix.Name{Organization: []string{"Acme"}}),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			method := newMTLSMethod(testCase.subjects)
+			request := &http.Request{TLS: testCase.tlsState}
+
+			authorized := method.isAuthorized(request)
+
+			assert.Equal(t, testCase.authorized, authorized)
+		})
+	}
+}
@@ -13,7 +13,7 @@ import (
 type Settings struct {
 	// Roles is a list of roles with their associated authentication
 	// and routes.
-	Roles []Role
+	Roles []Role `json:"roles"`
 }
 
 func (s *Settings) SetDefaults() {
@@ -63,6 +63,17 @@ func (s *Settings) OverrideWith(other Settings) {
 	s.Roles = gosettings.OverrideWithSlice(s.Roles, other.Roles)
 }
 
+// UsesMTLS returns true if at least one role uses
+// the mutual TLS authentication method.
+func (s Settings) UsesMTLS() bool {
+	for _, role := range s.Roles {
+		if role.Auth == AuthMTLS {
+			return true
+		}
+	}
+	return false
+}
+
 func (s Settings) ToLinesNode() (node *gotree.Node) {
 	node = gotree.New("Authentication middleware settings:")
 
@@ -79,6 +90,7 @@ const (
 	AuthNone   = "none"
 	AuthAPIKey = "apikey"
 	AuthBasic  = "basic"
+	AuthMTLS   = "mtls"
 )
 
 // Role contains the role name, authentication method name and
@@ -86,18 +98,24 @@ const (
 type Role struct {
 	// Name is the role name and is only used for documentation
 	// and in the authentication middleware debug logs.
-	Name string
-	// Auth is the authentication method to use, which can be 'none' or 'apikey'.
-	Auth string
+	Name string `json:"name"`
+	// Auth is the authentication method to use, which can be
+	// 'none', 'apikey', 'basic' or 'mtls'.
+	Auth string `json:"auth"`
 	// APIKey is the API key to use when using the 'apikey' authentication.
-	APIKey string
+	APIKey string `json:"api_key"`
 	// Username for HTTP Basic authentication method.
-	Username string
+	Username string `json:"username"`
 	// Password for HTTP Basic authentication method.
-	Password string
+	Password string `json:"password"`
+	// Subjects is the list of client certificate subjects allowed
+	// when using the 'mtls' authentication. Each subject can be
+	// either the full distinguished name such as "CN=alice,O=Acme"
+	// or only the common name such as "alice".
+	Subjects []string `json:"subjects"`
 	// Routes is a list of routes that the role can access in the format
 	// "HTTP_METHOD PATH", for example "GET /v1/vpn/status"
-	Routes []string
+	Routes []string `json:"routes"`
 }
 
 var (
@@ -105,11 +123,12 @@ var (
 	ErrAPIKeyEmpty        = errors.New("api key is empty")
 	ErrBasicUsernameEmpty = errors.New("username is empty")
 	ErrBasicPasswordEmpty = errors.New("password is empty")
+	ErrMTLSSubjectsEmpty  = errors.New("client certificate subjects are empty")
 	ErrRouteNotSupported  = errors.New("route not supported by the control server")
 )
 
 func (r Role) validate() (err error) {
-	err = validate.IsOneOf(r.Auth, AuthNone, AuthAPIKey, AuthBasic)
+	err = validate.IsOneOf(r.Auth, AuthNone, AuthAPIKey, AuthBasic, AuthMTLS)
 	if err != nil {
 		return fmt.Errorf("%w: %s", ErrMethodNotSupported, r.Auth)
 	}
@@ -121,6 +140,8 @@ func (r Role) validate() (err error) {
 		return fmt.Errorf("for role %s: %w", r.Name, ErrBasicUsernameEmpty)
 	case r.Auth == AuthBasic && r.Password == "":
 		return fmt.Errorf("for role %s: %w", r.Name, ErrBasicPasswordEmpty)
+	case r.Auth == AuthMTLS && len(r.Subjects) == 0:
+		return fmt.Errorf("for role %s: %w", r.Name, ErrMTLSSubjectsEmpty)
 	}
 
 	for i, route := range r.Routes {
@@ -136,28 +157,50 @@ func (r Role) validate() (err error) {
 
 // WARNING: do not mutate programmatically.
 var validRoutes = map[string]struct{}{ //nolint:gochecknoglobals
-	http.MethodGet + " /openvpn/actions/restart":  {},
-	http.MethodGet + " /unbound/actions/restart":  {},
-	http.MethodGet + " /updater/restart":          {},
-	http.MethodGet + " /v1/version":               {},
-	http.MethodGet + " /v1/vpn/status":            {},
-	http.MethodPut + " /v1/vpn/status":            {},
-	http.MethodGet + " /v1/vpn/settings":          {},
-	http.MethodPut + " /v1/vpn/settings":          {},
-	http.MethodGet + " /v1/openvpn/status":        {},
-	http.MethodPut + " /v1/openvpn/status":        {},
-	http.MethodGet + " /v1/openvpn/portforwarded": {},
-	http.MethodGet + " /v1/openvpn/settings":      {},
-	http.MethodGet + " /v1/dns/status":            {},
-	http.MethodPut + " /v1/dns/status":            {},
-	http.MethodGet + " /v1/updater/status":        {},
-	http.MethodPut + " /v1/updater/status":        {},
-	http.MethodGet + " /v1/publicip/ip":           {},
+	http.MethodGet + " /openvpn/actions/restart":      {},
+	http.MethodGet + " /unbound/actions/restart":      {},
+	http.MethodGet + " /updater/restart":              {},
+	http.MethodGet + " /v1/version":                   {},
+	http.MethodGet + " /v1/openapi.json":              {},
+	http.MethodGet + " /v1/vpn/status":                {},
+	http.MethodPut + " /v1/vpn/status":                {},
+	http.MethodGet + " /v1/vpn/settings":              {},
+	http.MethodPut + " /v1/vpn/settings":              {},
+	http.MethodGet + " /v1/vpn/connection":            {},
+	http.MethodPost + " /v1/vpn/connection/rotate":    {},
+	http.MethodGet + " /v1/vpn/failures":              {},
+	http.MethodGet + " /v1/vpn/escalation":            {},
+	http.MethodGet + " /v1/health/history":            {},
+	http.MethodGet + " /v1/openvpn/status":            {},
+	http.MethodPut + " /v1/openvpn/status":            {},
+	http.MethodGet + " /v1/openvpn/portforwarded":     {},
+	http.MethodGet + " /v1/openvpn/settings":          {},
+	http.MethodGet + " /v1/portforward":               {},
+	http.MethodPut + " /v1/portforward":               {},
+	http.MethodGet + " /v1/servers":                   {},
+	http.MethodGet + " /v1/firewall":                  {},
+	http.MethodPut + " /v1/firewall/ports":            {},
+	http.MethodDelete + " /v1/firewall/ports":         {},
+	http.MethodPut + " /v1/firewall/outbound_subnets": {},
+	http.MethodGet + " /v1/settings":                  {},
+	http.MethodPatch + " /v1/settings":                {},
+	http.MethodGet + " /v1/dns/status":                {},
+	http.MethodPut + " /v1/dns/status":                {},
+	http.MethodGet + " /v1/updater/status":            {},
+	http.MethodPut + " /v1/updater/status":            {},
+	http.MethodGet + " /v1/publicip/ip":               {},
+	http.MethodGet + " /v1/audit":                     {},
+	http.MethodGet + " /v1/events":                    {},
 }
 
 func (r Role) copy() (copied Role) {
 	copied.Name = r.Name
 	copied.Auth = r.Auth
+	copied.APIKey = r.APIKey
+	copied.Username = r.Username
+	copied.Password = r.Password
+	copied.Subjects = make([]string, len(r.Subjects))
+	copy(copied.Subjects, r.Subjects)
 	copied.Routes = make([]string, len(r.Routes))
 	copy(copied.Routes, r.Routes)
 	return copied
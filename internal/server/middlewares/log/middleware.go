@@ -77,3 +77,9 @@ func (w *statefulResponseWriter) WriteHeader(statusCode int) {
 func (w *statefulResponseWriter) Header() http.Header {
 	return w.httpWriter.Header()
 }
+
+// Unwrap returns the underlying response writer, which is notably
+// used by [http.ResponseController] to flush streamed responses.
+func (w *statefulResponseWriter) Unwrap() http.ResponseWriter {
+	return w.httpWriter
+}
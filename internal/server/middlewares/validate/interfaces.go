@@ -0,0 +1,7 @@
+package validate
+
+import "github.com/qdm12/gluetun/internal/server/openapi"
+
+type SchemaGetter interface {
+	RequestSchema(method, path string) (schema *openapi.Schema)
+}
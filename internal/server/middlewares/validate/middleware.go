@@ -0,0 +1,54 @@
+package validate
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// New returns a middleware validating JSON request bodies
+// against the request schema found in the schema getter,
+// for the request method and path. Requests without a
+// matching schema are passed through unchanged.
+func New(schemaGetter SchemaGetter) (
+	middleware func(http.Handler) http.Handler) {
+	return func(handler http.Handler) http.Handler {
+		return &validateHandler{
+			childHandler: handler,
+			schemaGetter: schemaGetter,
+		}
+	}
+}
+
+type validateHandler struct {
+	childHandler http.Handler
+	schemaGetter SchemaGetter
+}
+
+func (h *validateHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	// The path is normalized the same way the control server
+	// handler does before routing the request.
+	path := strings.TrimSuffix(request.URL.Path, "/")
+	schema := h.schemaGetter.RequestSchema(request.Method, path)
+	if schema == nil {
+		h.childHandler.ServeHTTP(writer, request)
+		return
+	}
+
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		http.Error(writer, "reading body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	_ = request.Body.Close()
+
+	err = schema.Validate(body)
+	if err != nil {
+		http.Error(writer, "validating body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	request.Body = io.NopCloser(bytes.NewReader(body))
+	h.childHandler.ServeHTTP(writer, request)
+}
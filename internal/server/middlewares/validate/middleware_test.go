@@ -0,0 +1,87 @@
+package validate
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/server/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateHandler_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	type body struct {
+		Status string `json:"status"`
+	}
+	document := openapi.New("test", "1", []openapi.Route{
+		{Method: http.MethodPut, Path: "/a", Request: body{}},
+		{Method: http.MethodGet, Path: "/b"},
+	})
+
+	testCases := map[string]struct {
+		method       string
+		path         string
+		body         string
+		statusCode   int
+		responseBody string
+	}{
+		"no_schema_for_route": {
+			method:       http.MethodGet,
+			path:         "/b",
+			body:         "not json",
+			statusCode:   http.StatusOK,
+			responseBody: "not json",
+		},
+		"valid_body": {
+			method:       http.MethodPut,
+			path:         "/a",
+			body:         `{"status":"running"}`,
+			statusCode:   http.StatusOK,
+			responseBody: `{"status":"running"}`,
+		},
+		"invalid_body": {
+			method:       http.MethodPut,
+			path:         "/a",
+			body:         `{"state":"running"}`,
+			statusCode:   http.StatusBadRequest,
+			responseBody: "validating body: property is unknown: state\n",
+		},
+		"invalid_body_trailing_slash": {
+			method:       http.MethodPut,
+			path:         "/a/",
+			body:         `{"state":"running"}`,
+			statusCode:   http.StatusBadRequest,
+			responseBody: "validating body: property is unknown: state\n",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			childHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.Copy(w, r.Body)
+			})
+			handler := New(document)(childHandler)
+
+			request := httptest.NewRequest(testCase.method, testCase.path,
+				strings.NewReader(testCase.body))
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, request)
+
+			response := recorder.Result()
+			defer response.Body.Close()
+			assert.Equal(t, testCase.statusCode, response.StatusCode)
+			responseBody, err := io.ReadAll(response.Body)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.responseBody, string(responseBody))
+		})
+	}
+}
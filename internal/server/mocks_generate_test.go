@@ -0,0 +1,3 @@
+package server
+
+//go:generate mockgen -destination=mocks_test.go -package=$GOPACKAGE . DNSLoop,Firewall,HTTPProxyLooper,Logger,PortForwardLooper,PublicIPLoop,Routing,ShadowsocksLooper,Storage,UpdaterLooper,VPNLooper
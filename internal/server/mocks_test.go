@@ -0,0 +1,822 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/qdm12/gluetun/internal/server (interfaces: DNSLoop,Firewall,HTTPProxyLooper,Logger,PortForwardLooper,PublicIPLoop,Routing,ShadowsocksLooper,Storage,UpdaterLooper,VPNLooper)
+
+// Package server is a generated GoMock package.
+package server
+
+import (
+	context "context"
+	netip "net/netip"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	settings "github.com/qdm12/gluetun/internal/configuration/settings"
+	firewall "github.com/qdm12/gluetun/internal/firewall"
+	models "github.com/qdm12/gluetun/internal/models"
+	service "github.com/qdm12/gluetun/internal/portforward/service"
+)
+
+// MockDNSLoop is a mock of DNSLoop interface.
+type MockDNSLoop struct {
+	ctrl     *gomock.Controller
+	recorder *MockDNSLoopMockRecorder
+}
+
+// MockDNSLoopMockRecorder is the mock recorder for MockDNSLoop.
+type MockDNSLoopMockRecorder struct {
+	mock *MockDNSLoop
+}
+
+// NewMockDNSLoop creates a new mock instance.
+func NewMockDNSLoop(ctrl *gomock.Controller) *MockDNSLoop {
+	mock := &MockDNSLoop{ctrl: ctrl}
+	mock.recorder = &MockDNSLoopMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDNSLoop) EXPECT() *MockDNSLoopMockRecorder {
+	return m.recorder
+}
+
+// ApplyStatus mocks base method.
+func (m *MockDNSLoop) ApplyStatus(arg0 context.Context, arg1 models.LoopStatus) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyStatus", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyStatus indicates an expected call of ApplyStatus.
+func (mr *MockDNSLoopMockRecorder) ApplyStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyStatus", reflect.TypeOf((*MockDNSLoop)(nil).ApplyStatus), arg0, arg1)
+}
+
+// GetSettings mocks base method.
+func (m *MockDNSLoop) GetSettings() settings.DNS {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSettings")
+	ret0, _ := ret[0].(settings.DNS)
+	return ret0
+}
+
+// GetSettings indicates an expected call of GetSettings.
+func (mr *MockDNSLoopMockRecorder) GetSettings() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettings", reflect.TypeOf((*MockDNSLoop)(nil).GetSettings))
+}
+
+// GetStatus mocks base method.
+func (m *MockDNSLoop) GetStatus() models.LoopStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatus")
+	ret0, _ := ret[0].(models.LoopStatus)
+	return ret0
+}
+
+// GetStatus indicates an expected call of GetStatus.
+func (mr *MockDNSLoopMockRecorder) GetStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatus", reflect.TypeOf((*MockDNSLoop)(nil).GetStatus))
+}
+
+// SetSettings mocks base method.
+func (m *MockDNSLoop) SetSettings(arg0 context.Context, arg1 settings.DNS) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSettings", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SetSettings indicates an expected call of SetSettings.
+func (mr *MockDNSLoopMockRecorder) SetSettings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSettings", reflect.TypeOf((*MockDNSLoop)(nil).SetSettings), arg0, arg1)
+}
+
+// MockFirewall is a mock of Firewall interface.
+type MockFirewall struct {
+	ctrl     *gomock.Controller
+	recorder *MockFirewallMockRecorder
+}
+
+// MockFirewallMockRecorder is the mock recorder for MockFirewall.
+type MockFirewallMockRecorder struct {
+	mock *MockFirewall
+}
+
+// NewMockFirewall creates a new mock instance.
+func NewMockFirewall(ctrl *gomock.Controller) *MockFirewall {
+	mock := &MockFirewall{ctrl: ctrl}
+	mock.recorder = &MockFirewallMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFirewall) EXPECT() *MockFirewallMockRecorder {
+	return m.recorder
+}
+
+// GetState mocks base method.
+func (m *MockFirewall) GetState() firewall.State {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetState")
+	ret0, _ := ret[0].(firewall.State)
+	return ret0
+}
+
+// GetState indicates an expected call of GetState.
+func (mr *MockFirewallMockRecorder) GetState() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetState", reflect.TypeOf((*MockFirewall)(nil).GetState))
+}
+
+// RemoveAllowedPortOnInterface mocks base method.
+func (m *MockFirewall) RemoveAllowedPortOnInterface(arg0 context.Context, arg1 uint16, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAllowedPortOnInterface", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAllowedPortOnInterface indicates an expected call of RemoveAllowedPortOnInterface.
+func (mr *MockFirewallMockRecorder) RemoveAllowedPortOnInterface(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAllowedPortOnInterface", reflect.TypeOf((*MockFirewall)(nil).RemoveAllowedPortOnInterface), arg0, arg1, arg2)
+}
+
+// SetAllowedPort mocks base method.
+func (m *MockFirewall) SetAllowedPort(arg0 context.Context, arg1 uint16, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAllowedPort", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAllowedPort indicates an expected call of SetAllowedPort.
+func (mr *MockFirewallMockRecorder) SetAllowedPort(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAllowedPort", reflect.TypeOf((*MockFirewall)(nil).SetAllowedPort), arg0, arg1, arg2)
+}
+
+// SetEnabled mocks base method.
+func (m *MockFirewall) SetEnabled(arg0 context.Context, arg1 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEnabled", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetEnabled indicates an expected call of SetEnabled.
+func (mr *MockFirewallMockRecorder) SetEnabled(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEnabled", reflect.TypeOf((*MockFirewall)(nil).SetEnabled), arg0, arg1)
+}
+
+// SetOutboundSubnets mocks base method.
+func (m *MockFirewall) SetOutboundSubnets(arg0 context.Context, arg1 []netip.Prefix) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOutboundSubnets", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOutboundSubnets indicates an expected call of SetOutboundSubnets.
+func (mr *MockFirewallMockRecorder) SetOutboundSubnets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOutboundSubnets", reflect.TypeOf((*MockFirewall)(nil).SetOutboundSubnets), arg0, arg1)
+}
+
+// MockHTTPProxyLooper is a mock of HTTPProxyLooper interface.
+type MockHTTPProxyLooper struct {
+	ctrl     *gomock.Controller
+	recorder *MockHTTPProxyLooperMockRecorder
+}
+
+// MockHTTPProxyLooperMockRecorder is the mock recorder for MockHTTPProxyLooper.
+type MockHTTPProxyLooperMockRecorder struct {
+	mock *MockHTTPProxyLooper
+}
+
+// NewMockHTTPProxyLooper creates a new mock instance.
+func NewMockHTTPProxyLooper(ctrl *gomock.Controller) *MockHTTPProxyLooper {
+	mock := &MockHTTPProxyLooper{ctrl: ctrl}
+	mock.recorder = &MockHTTPProxyLooperMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHTTPProxyLooper) EXPECT() *MockHTTPProxyLooperMockRecorder {
+	return m.recorder
+}
+
+// GetSettings mocks base method.
+func (m *MockHTTPProxyLooper) GetSettings() settings.HTTPProxy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSettings")
+	ret0, _ := ret[0].(settings.HTTPProxy)
+	return ret0
+}
+
+// GetSettings indicates an expected call of GetSettings.
+func (mr *MockHTTPProxyLooperMockRecorder) GetSettings() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettings", reflect.TypeOf((*MockHTTPProxyLooper)(nil).GetSettings))
+}
+
+// SetSettings mocks base method.
+func (m *MockHTTPProxyLooper) SetSettings(arg0 context.Context, arg1 settings.HTTPProxy) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSettings", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SetSettings indicates an expected call of SetSettings.
+func (mr *MockHTTPProxyLooperMockRecorder) SetSettings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSettings", reflect.TypeOf((*MockHTTPProxyLooper)(nil).SetSettings), arg0, arg1)
+}
+
+// MockLogger is a mock of Logger interface.
+type MockLogger struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoggerMockRecorder
+}
+
+// MockLoggerMockRecorder is the mock recorder for MockLogger.
+type MockLoggerMockRecorder struct {
+	mock *MockLogger
+}
+
+// NewMockLogger creates a new mock instance.
+func NewMockLogger(ctrl *gomock.Controller) *MockLogger {
+	mock := &MockLogger{ctrl: ctrl}
+	mock.recorder = &MockLoggerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLogger) EXPECT() *MockLoggerMockRecorder {
+	return m.recorder
+}
+
+// Debugf mocks base method.
+func (m *MockLogger) Debugf(arg0 string, arg1 ...interface{}) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Debugf", varargs...)
+}
+
+// Debugf indicates an expected call of Debugf.
+func (mr *MockLoggerMockRecorder) Debugf(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Debugf", reflect.TypeOf((*MockLogger)(nil).Debugf), varargs...)
+}
+
+// Error mocks base method.
+func (m *MockLogger) Error(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Error", arg0)
+}
+
+// Error indicates an expected call of Error.
+func (mr *MockLoggerMockRecorder) Error(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockLogger)(nil).Error), arg0)
+}
+
+// Info mocks base method.
+func (m *MockLogger) Info(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Info", arg0)
+}
+
+// Info indicates an expected call of Info.
+func (mr *MockLoggerMockRecorder) Info(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockLogger)(nil).Info), arg0)
+}
+
+// Warn mocks base method.
+func (m *MockLogger) Warn(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Warn", arg0)
+}
+
+// Warn indicates an expected call of Warn.
+func (mr *MockLoggerMockRecorder) Warn(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Warn", reflect.TypeOf((*MockLogger)(nil).Warn), arg0)
+}
+
+// Warnf mocks base method.
+func (m *MockLogger) Warnf(arg0 string, arg1 ...interface{}) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Warnf", varargs...)
+}
+
+// Warnf indicates an expected call of Warnf.
+func (mr *MockLoggerMockRecorder) Warnf(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Warnf", reflect.TypeOf((*MockLogger)(nil).Warnf), varargs...)
+}
+
+// MockPortForwardLooper is a mock of PortForwardLooper interface.
+type MockPortForwardLooper struct {
+	ctrl     *gomock.Controller
+	recorder *MockPortForwardLooperMockRecorder
+}
+
+// MockPortForwardLooperMockRecorder is the mock recorder for MockPortForwardLooper.
+type MockPortForwardLooperMockRecorder struct {
+	mock *MockPortForwardLooper
+}
+
+// NewMockPortForwardLooper creates a new mock instance.
+func NewMockPortForwardLooper(ctrl *gomock.Controller) *MockPortForwardLooper {
+	mock := &MockPortForwardLooper{ctrl: ctrl}
+	mock.recorder = &MockPortForwardLooperMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPortForwardLooper) EXPECT() *MockPortForwardLooperMockRecorder {
+	return m.recorder
+}
+
+// GetDetails mocks base method.
+func (m *MockPortForwardLooper) GetDetails() service.Details {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDetails")
+	ret0, _ := ret[0].(service.Details)
+	return ret0
+}
+
+// GetDetails indicates an expected call of GetDetails.
+func (mr *MockPortForwardLooperMockRecorder) GetDetails() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDetails", reflect.TypeOf((*MockPortForwardLooper)(nil).GetDetails))
+}
+
+// GetPortsForwarded mocks base method.
+func (m *MockPortForwardLooper) GetPortsForwarded() []uint16 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPortsForwarded")
+	ret0, _ := ret[0].([]uint16)
+	return ret0
+}
+
+// GetPortsForwarded indicates an expected call of GetPortsForwarded.
+func (mr *MockPortForwardLooperMockRecorder) GetPortsForwarded() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPortsForwarded", reflect.TypeOf((*MockPortForwardLooper)(nil).GetPortsForwarded))
+}
+
+// Refresh mocks base method.
+func (m *MockPortForwardLooper) Refresh(arg0 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockPortForwardLooperMockRecorder) Refresh(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockPortForwardLooper)(nil).Refresh), arg0)
+}
+
+// MockPublicIPLoop is a mock of PublicIPLoop interface.
+type MockPublicIPLoop struct {
+	ctrl     *gomock.Controller
+	recorder *MockPublicIPLoopMockRecorder
+}
+
+// MockPublicIPLoopMockRecorder is the mock recorder for MockPublicIPLoop.
+type MockPublicIPLoopMockRecorder struct {
+	mock *MockPublicIPLoop
+}
+
+// NewMockPublicIPLoop creates a new mock instance.
+func NewMockPublicIPLoop(ctrl *gomock.Controller) *MockPublicIPLoop {
+	mock := &MockPublicIPLoop{ctrl: ctrl}
+	mock.recorder = &MockPublicIPLoopMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPublicIPLoop) EXPECT() *MockPublicIPLoopMockRecorder {
+	return m.recorder
+}
+
+// GetData mocks base method.
+func (m *MockPublicIPLoop) GetData() models.PublicIP {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetData")
+	ret0, _ := ret[0].(models.PublicIP)
+	return ret0
+}
+
+// GetData indicates an expected call of GetData.
+func (mr *MockPublicIPLoopMockRecorder) GetData() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetData", reflect.TypeOf((*MockPublicIPLoop)(nil).GetData))
+}
+
+// UpdateWith mocks base method.
+func (m *MockPublicIPLoop) UpdateWith(arg0 settings.PublicIP) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWith", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWith indicates an expected call of UpdateWith.
+func (mr *MockPublicIPLoopMockRecorder) UpdateWith(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWith", reflect.TypeOf((*MockPublicIPLoop)(nil).UpdateWith), arg0)
+}
+
+// MockRouting is a mock of Routing interface.
+type MockRouting struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoutingMockRecorder
+}
+
+// MockRoutingMockRecorder is the mock recorder for MockRouting.
+type MockRoutingMockRecorder struct {
+	mock *MockRouting
+}
+
+// NewMockRouting creates a new mock instance.
+func NewMockRouting(ctrl *gomock.Controller) *MockRouting {
+	mock := &MockRouting{ctrl: ctrl}
+	mock.recorder = &MockRoutingMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRouting) EXPECT() *MockRoutingMockRecorder {
+	return m.recorder
+}
+
+// SetOutboundRoutes mocks base method.
+func (m *MockRouting) SetOutboundRoutes(arg0 []netip.Prefix) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOutboundRoutes", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOutboundRoutes indicates an expected call of SetOutboundRoutes.
+func (mr *MockRoutingMockRecorder) SetOutboundRoutes(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOutboundRoutes", reflect.TypeOf((*MockRouting)(nil).SetOutboundRoutes), arg0)
+}
+
+// MockShadowsocksLooper is a mock of ShadowsocksLooper interface.
+type MockShadowsocksLooper struct {
+	ctrl     *gomock.Controller
+	recorder *MockShadowsocksLooperMockRecorder
+}
+
+// MockShadowsocksLooperMockRecorder is the mock recorder for MockShadowsocksLooper.
+type MockShadowsocksLooperMockRecorder struct {
+	mock *MockShadowsocksLooper
+}
+
+// NewMockShadowsocksLooper creates a new mock instance.
+func NewMockShadowsocksLooper(ctrl *gomock.Controller) *MockShadowsocksLooper {
+	mock := &MockShadowsocksLooper{ctrl: ctrl}
+	mock.recorder = &MockShadowsocksLooperMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShadowsocksLooper) EXPECT() *MockShadowsocksLooperMockRecorder {
+	return m.recorder
+}
+
+// GetSettings mocks base method.
+func (m *MockShadowsocksLooper) GetSettings() settings.Shadowsocks {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSettings")
+	ret0, _ := ret[0].(settings.Shadowsocks)
+	return ret0
+}
+
+// GetSettings indicates an expected call of GetSettings.
+func (mr *MockShadowsocksLooperMockRecorder) GetSettings() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettings", reflect.TypeOf((*MockShadowsocksLooper)(nil).GetSettings))
+}
+
+// SetSettings mocks base method.
+func (m *MockShadowsocksLooper) SetSettings(arg0 context.Context, arg1 settings.Shadowsocks) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSettings", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SetSettings indicates an expected call of SetSettings.
+func (mr *MockShadowsocksLooperMockRecorder) SetSettings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSettings", reflect.TypeOf((*MockShadowsocksLooper)(nil).SetSettings), arg0, arg1)
+}
+
+// MockStorage is a mock of Storage interface.
+type MockStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockStorageMockRecorder
+}
+
+// MockStorageMockRecorder is the mock recorder for MockStorage.
+type MockStorageMockRecorder struct {
+	mock *MockStorage
+}
+
+// NewMockStorage creates a new mock instance.
+func NewMockStorage(ctrl *gomock.Controller) *MockStorage {
+	mock := &MockStorage{ctrl: ctrl}
+	mock.recorder = &MockStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
+	return m.recorder
+}
+
+// FilterServers mocks base method.
+func (m *MockStorage) FilterServers(arg0 string, arg1 settings.ServerSelection) ([]models.Server, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterServers", arg0, arg1)
+	ret0, _ := ret[0].([]models.Server)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FilterServers indicates an expected call of FilterServers.
+func (mr *MockStorageMockRecorder) FilterServers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterServers", reflect.TypeOf((*MockStorage)(nil).FilterServers), arg0, arg1)
+}
+
+// GetFilterChoices mocks base method.
+func (m *MockStorage) GetFilterChoices(arg0 string) models.FilterChoices {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFilterChoices", arg0)
+	ret0, _ := ret[0].(models.FilterChoices)
+	return ret0
+}
+
+// GetFilterChoices indicates an expected call of GetFilterChoices.
+func (mr *MockStorageMockRecorder) GetFilterChoices(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilterChoices", reflect.TypeOf((*MockStorage)(nil).GetFilterChoices), arg0)
+}
+
+// MockUpdaterLooper is a mock of UpdaterLooper interface.
+type MockUpdaterLooper struct {
+	ctrl     *gomock.Controller
+	recorder *MockUpdaterLooperMockRecorder
+}
+
+// MockUpdaterLooperMockRecorder is the mock recorder for MockUpdaterLooper.
+type MockUpdaterLooperMockRecorder struct {
+	mock *MockUpdaterLooper
+}
+
+// NewMockUpdaterLooper creates a new mock instance.
+func NewMockUpdaterLooper(ctrl *gomock.Controller) *MockUpdaterLooper {
+	mock := &MockUpdaterLooper{ctrl: ctrl}
+	mock.recorder = &MockUpdaterLooperMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUpdaterLooper) EXPECT() *MockUpdaterLooperMockRecorder {
+	return m.recorder
+}
+
+// GetSettings mocks base method.
+func (m *MockUpdaterLooper) GetSettings() settings.Updater {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSettings")
+	ret0, _ := ret[0].(settings.Updater)
+	return ret0
+}
+
+// GetSettings indicates an expected call of GetSettings.
+func (mr *MockUpdaterLooperMockRecorder) GetSettings() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettings", reflect.TypeOf((*MockUpdaterLooper)(nil).GetSettings))
+}
+
+// GetStatus mocks base method.
+func (m *MockUpdaterLooper) GetStatus() models.LoopStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatus")
+	ret0, _ := ret[0].(models.LoopStatus)
+	return ret0
+}
+
+// GetStatus indicates an expected call of GetStatus.
+func (mr *MockUpdaterLooperMockRecorder) GetStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatus", reflect.TypeOf((*MockUpdaterLooper)(nil).GetStatus))
+}
+
+// SetSettings mocks base method.
+func (m *MockUpdaterLooper) SetSettings(arg0 settings.Updater) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSettings", arg0)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SetSettings indicates an expected call of SetSettings.
+func (mr *MockUpdaterLooperMockRecorder) SetSettings(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSettings", reflect.TypeOf((*MockUpdaterLooper)(nil).SetSettings), arg0)
+}
+
+// SetStatus mocks base method.
+func (m *MockUpdaterLooper) SetStatus(arg0 context.Context, arg1 models.LoopStatus) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStatus", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetStatus indicates an expected call of SetStatus.
+func (mr *MockUpdaterLooperMockRecorder) SetStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStatus", reflect.TypeOf((*MockUpdaterLooper)(nil).SetStatus), arg0, arg1)
+}
+
+// MockVPNLooper is a mock of VPNLooper interface.
+type MockVPNLooper struct {
+	ctrl     *gomock.Controller
+	recorder *MockVPNLooperMockRecorder
+}
+
+// MockVPNLooperMockRecorder is the mock recorder for MockVPNLooper.
+type MockVPNLooperMockRecorder struct {
+	mock *MockVPNLooper
+}
+
+// NewMockVPNLooper creates a new mock instance.
+func NewMockVPNLooper(ctrl *gomock.Controller) *MockVPNLooper {
+	mock := &MockVPNLooper{ctrl: ctrl}
+	mock.recorder = &MockVPNLooperMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVPNLooper) EXPECT() *MockVPNLooperMockRecorder {
+	return m.recorder
+}
+
+// ApplyStatus mocks base method.
+func (m *MockVPNLooper) ApplyStatus(arg0 context.Context, arg1 models.LoopStatus) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyStatus", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyStatus indicates an expected call of ApplyStatus.
+func (mr *MockVPNLooperMockRecorder) ApplyStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyStatus", reflect.TypeOf((*MockVPNLooper)(nil).ApplyStatus), arg0, arg1)
+}
+
+// GetActiveSettings mocks base method.
+func (m *MockVPNLooper) GetActiveSettings() settings.VPN {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveSettings")
+	ret0, _ := ret[0].(settings.VPN)
+	return ret0
+}
+
+// GetActiveSettings indicates an expected call of GetActiveSettings.
+func (mr *MockVPNLooperMockRecorder) GetActiveSettings() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveSettings", reflect.TypeOf((*MockVPNLooper)(nil).GetActiveSettings))
+}
+
+// GetConnection mocks base method.
+func (m *MockVPNLooper) GetConnection() (models.Connection, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConnection")
+	ret0, _ := ret[0].(models.Connection)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetConnection indicates an expected call of GetConnection.
+func (mr *MockVPNLooperMockRecorder) GetConnection() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConnection", reflect.TypeOf((*MockVPNLooper)(nil).GetConnection))
+}
+
+// GetEscalation mocks base method.
+func (m *MockVPNLooper) GetEscalation() models.Escalation {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEscalation")
+	ret0, _ := ret[0].(models.Escalation)
+	return ret0
+}
+
+// GetEscalation indicates an expected call of GetEscalation.
+func (mr *MockVPNLooperMockRecorder) GetEscalation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEscalation", reflect.TypeOf((*MockVPNLooper)(nil).GetEscalation))
+}
+
+// GetFailureHistory mocks base method.
+func (m *MockVPNLooper) GetFailureHistory() []models.ServerFailure {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFailureHistory")
+	ret0, _ := ret[0].([]models.ServerFailure)
+	return ret0
+}
+
+// GetFailureHistory indicates an expected call of GetFailureHistory.
+func (mr *MockVPNLooperMockRecorder) GetFailureHistory() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFailureHistory", reflect.TypeOf((*MockVPNLooper)(nil).GetFailureHistory))
+}
+
+// GetSettings mocks base method.
+func (m *MockVPNLooper) GetSettings() settings.VPN {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSettings")
+	ret0, _ := ret[0].(settings.VPN)
+	return ret0
+}
+
+// GetSettings indicates an expected call of GetSettings.
+func (mr *MockVPNLooperMockRecorder) GetSettings() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettings", reflect.TypeOf((*MockVPNLooper)(nil).GetSettings))
+}
+
+// GetStatus mocks base method.
+func (m *MockVPNLooper) GetStatus() models.LoopStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatus")
+	ret0, _ := ret[0].(models.LoopStatus)
+	return ret0
+}
+
+// GetStatus indicates an expected call of GetStatus.
+func (mr *MockVPNLooperMockRecorder) GetStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatus", reflect.TypeOf((*MockVPNLooper)(nil).GetStatus))
+}
+
+// RotateConnection mocks base method.
+func (m *MockVPNLooper) RotateConnection(arg0 context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateConnection", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateConnection indicates an expected call of RotateConnection.
+func (mr *MockVPNLooperMockRecorder) RotateConnection(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateConnection", reflect.TypeOf((*MockVPNLooper)(nil).RotateConnection), arg0)
+}
+
+// SetSettings mocks base method.
+func (m *MockVPNLooper) SetSettings(arg0 context.Context, arg1 settings.VPN) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSettings", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SetSettings indicates an expected call of SetSettings.
+func (mr *MockVPNLooperMockRecorder) SetSettings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSettings", reflect.TypeOf((*MockVPNLooper)(nil).SetSettings), arg0, arg1)
+}
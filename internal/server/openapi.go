@@ -0,0 +1,188 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/constants/providers"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/events"
+	"github.com/qdm12/gluetun/internal/firewall"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/server/openapi"
+)
+
+func newOpenAPIDocument(buildInfo models.BuildInformation) *openapi.Document {
+	statusRequest := openapi.SchemaOf(statusWrapper{})
+	statusRequest.Properties["status"].Enum = []string{
+		string(constants.Running), string(constants.Stopped),
+	}
+
+	portForwardActionRequest := openapi.SchemaOf(portForwardActionWrapper{})
+	portForwardActionRequest.Properties["action"].Enum = []string{
+		portForwardActionRefresh, portForwardActionRenew,
+	}
+
+	portForwarded := &openapi.Schema{
+		OneOf: []*openapi.Schema{
+			openapi.SchemaOf(portWrapper{}),
+			openapi.SchemaOf(portsWrapper{}),
+		},
+	}
+
+	routes := []openapi.Route{
+		{Method: http.MethodGet, Path: "/openvpn/actions/restart", Deprecated: true,
+			Summary: "Restart the VPN"},
+		{Method: http.MethodGet, Path: "/unbound/actions/restart", Deprecated: true,
+			Summary: "Restart the DNS server"},
+		{Method: http.MethodGet, Path: "/updater/restart", Deprecated: true,
+			Summary: "Restart the servers data updater"},
+		{Method: http.MethodGet, Path: "/v1/openapi.json",
+			Summary: "Get this OpenAPI document", Response: &openapi.Schema{Type: "object"}},
+		{Method: http.MethodGet, Path: "/v1/version",
+			Summary: "Get the build information", Response: models.BuildInformation{}},
+		{Method: http.MethodGet, Path: "/v1/vpn/status",
+			Summary: "Get the VPN status", Response: statusWrapper{}},
+		{Method: http.MethodPut, Path: "/v1/vpn/status",
+			Summary: "Start or stop the VPN", Request: statusRequest, Response: outcomeWrapper{}},
+		{Method: http.MethodGet, Path: "/v1/vpn/settings",
+			Summary: "Get the VPN settings", Response: settings.VPN{}},
+		{Method: http.MethodPut, Path: "/v1/vpn/settings",
+			Summary: "Update the VPN settings with the fields set", Request: settings.VPN{}},
+		{Method: http.MethodGet, Path: "/v1/vpn/connection",
+			Summary: "Get the VPN connection in use", Response: connectionWrapper{}},
+		{Method: http.MethodPost, Path: "/v1/vpn/connection/rotate",
+			Summary:  "Reconnect the VPN to a different server",
+			Response: outcomeWrapper{}},
+		{Method: http.MethodGet, Path: "/v1/vpn/failures",
+			Summary:  "Get the failure history of VPN server endpoints",
+			Response: failuresWrapper{}},
+		{Method: http.MethodGet, Path: "/v1/vpn/escalation",
+			Summary:  "Get the unhealthy VPN escalation step",
+			Response: models.Escalation{}},
+		{Method: http.MethodGet, Path: "/v1/openvpn/status",
+			Summary: "Get the VPN status", Response: statusWrapper{}},
+		{Method: http.MethodPut, Path: "/v1/openvpn/status",
+			Summary: "Start or stop the VPN", Request: statusRequest, Response: outcomeWrapper{}},
+		{Method: http.MethodGet, Path: "/v1/openvpn/settings",
+			Summary: "Get the OpenVPN settings", Response: settings.OpenVPN{}},
+		{Method: http.MethodGet, Path: "/v1/openvpn/portforwarded",
+			Summary: "Get the forwarded ports", Response: portForwarded},
+		{Method: http.MethodGet, Path: "/v1/portforward",
+			Summary: "Get the port forwarding details", Response: portForwardWrapper{}},
+		{Method: http.MethodPut, Path: "/v1/portforward",
+			Summary: "Refresh the port forwarding or request a new port",
+			Request: portForwardActionRequest, Response: outcomeWrapper{}},
+		{Method: http.MethodGet, Path: "/v1/servers",
+			Summary:    "List the servers matching the filters given",
+			Parameters: serversQueryParameters(), Response: serversWrapper{}},
+		{Method: http.MethodGet, Path: "/v1/firewall",
+			Summary: "Get the firewall state", Response: firewall.State{}},
+		{Method: http.MethodPut, Path: "/v1/firewall/ports",
+			Summary: "Allow an input port", Request: firewallPortWrapper{},
+			Response: outcomeWrapper{}},
+		{Method: http.MethodDelete, Path: "/v1/firewall/ports",
+			Summary: "Remove an allowed input port", Request: portWrapper{},
+			Response: outcomeWrapper{}},
+		{Method: http.MethodPut, Path: "/v1/firewall/outbound_subnets",
+			Summary: "Set the outbound subnets allowed", Request: subnetsWrapper{},
+			Response: outcomeWrapper{}},
+		{Method: http.MethodGet, Path: "/v1/health/history",
+			Summary:  "Get the latest health check results",
+			Response: healthHistoryWrapper{}},
+		{Method: http.MethodGet, Path: "/v1/settings",
+			Summary: "Get all the settings, with secrets redacted", Response: settings.Settings{}},
+		{Method: http.MethodPatch, Path: "/v1/settings",
+			Summary: "Patch settings sections, or only validate them with dry_run",
+			Parameters: []openapi.Parameter{
+				openapi.QueryParameter("dry_run", "validate the patched settings and "+
+					"return them as a text tree without applying them",
+					&openapi.Schema{Type: "boolean"}),
+			},
+			Request: settingsPatch{}, Response: outcomesWrapper{}},
+		{Method: http.MethodGet, Path: "/v1/dns/status",
+			Summary: "Get the DNS server status", Response: statusWrapper{}},
+		{Method: http.MethodPut, Path: "/v1/dns/status",
+			Summary: "Start or stop the DNS server", Request: statusRequest, Response: outcomeWrapper{}},
+		{Method: http.MethodGet, Path: "/v1/updater/status",
+			Summary: "Get the servers data updater status", Response: statusWrapper{}},
+		{Method: http.MethodPut, Path: "/v1/updater/status",
+			Summary: "Start or stop the servers data updater", Request: statusRequest,
+			Response: outcomeWrapper{}},
+		{Method: http.MethodGet, Path: "/v1/publicip/ip",
+			Summary: "Get the public IP address information", Response: models.PublicIP{}},
+		{Method: http.MethodGet, Path: "/v1/audit",
+			Summary:    "List the audit entries of state changing requests",
+			Parameters: auditQueryParameters(), Response: auditWrapper{}},
+		{Method: http.MethodGet, Path: "/v1/events",
+			Summary: "Stream state changes as server-sent events", Response: events.Event{},
+			ResponseContentType: "text/event-stream"},
+	}
+
+	return openapi.New("Gluetun control server", buildInfo.Version, routes)
+}
+
+func (h *handlerV1) getOpenAPI(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(h.openAPI); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func serversQueryParameters() (parameters []openapi.Parameter) {
+	stringSchema := &openapi.Schema{Type: "string"}
+	booleanSchema := &openapi.Schema{Type: "boolean"}
+	integerSchema := &openapi.Schema{Type: "integer"}
+	listSchema := &openapi.Schema{Type: "array", Items: stringSchema}
+
+	parameters = []openapi.Parameter{
+		openapi.QueryParameter("provider", "VPN provider, defaults to the current provider",
+			&openapi.Schema{Type: "string", Enum: providers.AllWithCustom()}),
+		openapi.QueryParameter("vpn", "VPN type, defaults to openvpn",
+			&openapi.Schema{Type: "string", Enum: []string{vpn.OpenVPN, vpn.Wireguard}}),
+		openapi.QueryParameter("protocol", "OpenVPN network protocol, defaults to udp",
+			&openapi.Schema{Type: "string", Enum: []string{constants.UDP, constants.TCP}}),
+	}
+
+	for _, name := range []string{"countries", "categories", "regions", "cities",
+		"isps", "names", "hostnames"} {
+		parameters = append(parameters, openapi.QueryParameter(name,
+			"comma separated or repeated values to filter servers with", listSchema))
+	}
+	parameters = append(parameters, openapi.QueryParameter("numbers",
+		"comma separated or repeated server numbers to filter servers with",
+		&openapi.Schema{Type: "array", Items: integerSchema}))
+
+	for _, name := range []string{"owned_only", "free_only", "premium_only",
+		"stream_only", "multi_hop_only", "port_forward_only",
+		"secure_core_only", "tor_only"} {
+		parameters = append(parameters, openapi.QueryParameter(name, "", booleanSchema))
+	}
+
+	return append(parameters,
+		openapi.QueryParameter("sort", "field to sort servers by",
+			&openapi.Schema{Type: "string", Enum: serversSortFields()}),
+		openapi.QueryParameter("order", "sort order, defaults to asc",
+			&openapi.Schema{Type: "string", Enum: []string{serversOrderAsc, serversOrderDesc}}),
+		openapi.QueryParameter("offset", "number of servers to skip", integerSchema),
+		openapi.QueryParameter("limit", "maximum number of servers to return, "+
+			"defaults to 100 and 0 means no limit", integerSchema),
+	)
+}
+
+func auditQueryParameters() (parameters []openapi.Parameter) {
+	return []openapi.Parameter{
+		openapi.QueryParameter("role", "only list entries for this role name",
+			&openapi.Schema{Type: "string"}),
+		openapi.QueryParameter("route", "only list entries whose route contains this string",
+			&openapi.Schema{Type: "string"}),
+		openapi.QueryParameter("since", "only list entries recorded at or after this time",
+			&openapi.Schema{Type: "string", Format: "date-time"}),
+		openapi.QueryParameter("limit", "maximum number of most recent entries to list, "+
+			"defaults to 100 and 0 means no limit", &openapi.Schema{Type: "integer"}),
+	}
+}
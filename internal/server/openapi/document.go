@@ -0,0 +1,169 @@
+// Package openapi generates an OpenAPI 3 document describing
+// the control server routes, and validates JSON request bodies
+// against it.
+package openapi
+
+import (
+	"strings"
+)
+
+// Document is a subset of the OpenAPI 3 document object.
+type Document struct {
+	OpenAPI string               `json:"openapi"`
+	Info    Info                 `json:"info"`
+	Paths   map[string]*PathItem `json:"paths"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem maps lower cased HTTP methods to their operation.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	Summary     string               `json:"summary,omitempty"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter describes a query parameter.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// QueryParameter returns a query parameter with the name,
+// description and schema given.
+func QueryParameter(name, description string, schema *Schema) Parameter {
+	return Parameter{
+		Name:        name,
+		In:          "query",
+		Description: description,
+		Schema:      schema,
+	}
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// Route describes a single route of the control server.
+type Route struct {
+	Method  string
+	Path    string
+	Summary string
+	// Deprecated is true if the route is kept for backward
+	// compatibility only.
+	Deprecated bool
+	// Parameters are the query parameters accepted.
+	Parameters []Parameter
+	// Request is a value of the type of the JSON request body,
+	// or its *Schema, and can be left to nil if the route has
+	// no request body.
+	Request any
+	// Response is a value of the type of the JSON response body,
+	// or its *Schema, and can be left to nil if the response
+	// is not JSON.
+	Response any
+	// ResponseContentType is the response content type,
+	// and defaults to application/json if Response is set
+	// and to text/plain otherwise.
+	ResponseContentType string
+}
+
+// New creates an OpenAPI document from the routes given.
+func New(title, version string, routes []Route) *Document {
+	document := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:   title,
+			Version: version,
+		},
+		Paths: make(map[string]*PathItem, len(routes)),
+	}
+
+	for _, route := range routes {
+		pathItem, ok := document.Paths[route.Path]
+		if !ok {
+			pathItem = &PathItem{}
+			document.Paths[route.Path] = pathItem
+		}
+		(*pathItem)[strings.ToLower(route.Method)] = newOperation(route)
+	}
+
+	return document
+}
+
+func newOperation(route Route) (operation *Operation) {
+	operation = &Operation{
+		Summary:    route.Summary,
+		Deprecated: route.Deprecated,
+		Parameters: route.Parameters,
+		Responses: map[string]*Response{
+			"400": {Description: "Bad request"},
+			"401": {Description: "Unauthorized"},
+		},
+	}
+
+	if route.Request != nil {
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]*MediaType{
+				"application/json": {Schema: SchemaOf(route.Request)},
+			},
+		}
+	}
+
+	contentType := route.ResponseContentType
+	var schema *Schema
+	if route.Response != nil {
+		schema = SchemaOf(route.Response)
+		if contentType == "" {
+			contentType = "application/json"
+		}
+	} else if contentType == "" {
+		contentType = "text/plain"
+	}
+	operation.Responses["200"] = &Response{
+		Description: "Success",
+		Content: map[string]*MediaType{
+			contentType: {Schema: schema},
+		},
+	}
+
+	return operation
+}
+
+// RequestSchema returns the JSON request body schema for the
+// method and path given, or nil if there is no such schema.
+func (d *Document) RequestSchema(method, path string) (schema *Schema) {
+	pathItem, ok := d.Paths[path]
+	if !ok {
+		return nil
+	}
+	operation, ok := (*pathItem)[strings.ToLower(method)]
+	if !ok || operation.RequestBody == nil {
+		return nil
+	}
+	mediaType, ok := operation.RequestBody.Content["application/json"]
+	if !ok {
+		return nil
+	}
+	return mediaType.Schema
+}
@@ -0,0 +1,139 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Schema is a subset of the OpenAPI 3 schema object,
+// sufficient to describe the control server JSON payloads.
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties any                `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	rawJSONMessageType  = reflect.TypeOf(json.RawMessage{})
+	emptyInterfaceType  = reflect.TypeOf((*any)(nil)).Elem()
+	stringSchemaFormats = map[reflect.Type]string{ //nolint:gochecknoglobals
+		timeType: "date-time",
+	}
+)
+
+// SchemaOf generates a schema from the Go value given, following the
+// rules of the encoding/json package for field names and omissions.
+// Structs do not allow additional properties, such that validating a
+// request body against the schema detects unknown fields.
+// If the value is already a *Schema, it is returned as is.
+func SchemaOf(value any) (schema *Schema) {
+	if schema, ok := value.(*Schema); ok {
+		return schema
+	}
+	return schemaOfType(reflect.TypeOf(value))
+}
+
+func schemaOfType(t reflect.Type) (schema *Schema) {
+	if t == nil || t == emptyInterfaceType || t == rawJSONMessageType {
+		return &Schema{}
+	}
+
+	if t.Kind() == reflect.Pointer {
+		schema = schemaOfType(t.Elem())
+		schema.Nullable = true
+		return schema
+	}
+
+	switch {
+	case t == durationType:
+		return &Schema{Type: "integer", Format: "int64",
+			Description: "duration in nanoseconds"}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return &Schema{Type: "string", Format: stringSchemaFormats[t]}
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return &Schema{}
+	}
+
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: integerFormat(t)}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: schemaOfType(t.Elem()),
+			Nullable: t.Kind() == reflect.Slice}
+	case reflect.Map:
+		return &Schema{Type: "object", Nullable: true,
+			AdditionalProperties: schemaOfType(t.Elem())}
+	case reflect.Struct:
+		return schemaOfStruct(t)
+	default:
+		return &Schema{}
+	}
+}
+
+func integerFormat(t reflect.Type) (format string) {
+	const bitsIn32 = 32
+	if t.Bits() <= bitsIn32 {
+		return "int32"
+	}
+	return "int64"
+}
+
+func schemaOfStruct(t reflect.Type) (schema *Schema) {
+	schema = &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema, t.NumField()),
+		AdditionalProperties: false,
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		tagName, _, _ := strings.Cut(tag, ",")
+		if tagName != "" {
+			name = tagName
+		}
+
+		if field.Anonymous && tagName == "" && field.Type.Kind() == reflect.Struct {
+			embedded := schemaOfStruct(field.Type)
+			for embeddedName, embeddedSchema := range embedded.Properties {
+				schema.Properties[embeddedName] = embeddedSchema
+			}
+			continue
+		}
+
+		schema.Properties[name] = schemaOfType(field.Type)
+	}
+
+	return schema
+}
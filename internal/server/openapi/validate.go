@@ -0,0 +1,186 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrBodyNotValidJSON      = errors.New("body is not valid JSON")
+	ErrTypeMismatch          = errors.New("type mismatch")
+	ErrValueNotInEnum        = errors.New("value is not one of the allowed values")
+	ErrPropertyUnknown       = errors.New("property is unknown")
+	ErrNoOneOfSchemaMatching = errors.New("value does not match any of the schemas")
+)
+
+// Validate validates the JSON data given against the schema.
+func (s *Schema) Validate(data []byte) (err error) {
+	var value any
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	err = decoder.Decode(&value)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrBodyNotValidJSON, err)
+	}
+
+	return s.validateValue(value, "")
+}
+
+func (s *Schema) validateValue(value any, path string) (err error) {
+	if value == nil {
+		if s.Nullable || s.Type == "" {
+			return nil
+		}
+		return fmt.Errorf("%s%w: expected %s, got null", pathPrefix(path), ErrTypeMismatch, s.Type)
+	}
+
+	if len(s.OneOf) > 0 {
+		for _, schema := range s.OneOf {
+			if schema.validateValue(value, path) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s%w", pathPrefix(path), ErrNoOneOfSchemaMatching)
+	}
+
+	switch s.Type {
+	case "":
+		return nil
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return typeMismatchError(path, s.Type, value)
+		}
+	case "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			return typeMismatchError(path, s.Type, value)
+		}
+		float, err := number.Float64()
+		if err != nil || float != math.Trunc(float) {
+			return typeMismatchError(path, s.Type, value)
+		}
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			return typeMismatchError(path, s.Type, value)
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return typeMismatchError(path, s.Type, value)
+		}
+		if len(s.Enum) > 0 && !isOneOf(text, s.Enum) {
+			return fmt.Errorf("%s%w: %q must be one of %s",
+				pathPrefix(path), ErrValueNotInEnum, text, strings.Join(s.Enum, ", "))
+		}
+	case "array":
+		values, ok := value.([]any)
+		if !ok {
+			return typeMismatchError(path, s.Type, value)
+		}
+		for i, element := range values {
+			err = s.Items.validateValue(element, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return err
+			}
+		}
+	case "object":
+		return s.validateObject(value, path)
+	}
+	return nil
+}
+
+func (s *Schema) validateObject(value any, path string) (err error) {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return typeMismatchError(path, s.Type, value)
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		propertyPath := key
+		if path != "" {
+			propertyPath = path + "." + key
+		}
+
+		propertySchema, ok := s.property(key)
+		if !ok {
+			switch additional := s.AdditionalProperties.(type) {
+			case *Schema:
+				propertySchema = additional
+			case bool:
+				if !additional {
+					return fmt.Errorf("%w: %s", ErrPropertyUnknown, propertyPath)
+				}
+				continue
+			default:
+				continue
+			}
+		}
+
+		err = propertySchema.validateValue(object[key], propertyPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// property returns the schema of the property matching the key given.
+// Like encoding/json, an exact match is preferred and a case-insensitive
+// match is used otherwise.
+func (s *Schema) property(key string) (schema *Schema, ok bool) {
+	schema, ok = s.Properties[key]
+	if ok {
+		return schema, true
+	}
+	for name, schema := range s.Properties {
+		if strings.EqualFold(name, key) {
+			return schema, true
+		}
+	}
+	return nil, false
+}
+
+func typeMismatchError(path, expectedType string, value any) error {
+	var actualType string
+	switch value.(type) {
+	case bool:
+		actualType = "boolean"
+	case json.Number:
+		actualType = "number"
+	case string:
+		actualType = "string"
+	case []any:
+		actualType = "array"
+	case map[string]any:
+		actualType = "object"
+	}
+	return fmt.Errorf("%s%w: expected %s, got %s",
+		pathPrefix(path), ErrTypeMismatch, expectedType, actualType)
+}
+
+func pathPrefix(path string) string {
+	if path == "" {
+		return ""
+	}
+	return path + ": "
+}
+
+func isOneOf(value string, choices []string) bool {
+	for _, choice := range choices {
+		if value == choice {
+			return true
+		}
+	}
+	return false
+}
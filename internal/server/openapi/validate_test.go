@@ -0,0 +1,135 @@
+package openapi
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Schema_Validate(t *testing.T) {
+	t.Parallel()
+
+	type nested struct {
+		Enabled *bool          `json:"enabled"`
+		Period  *time.Duration `json:"period"`
+	}
+	type body struct {
+		Name    string         `json:"name"`
+		Port    *uint16        `json:"port,omitempty"`
+		Address netip.Addr     `json:"address"`
+		Ports   []uint16       `json:"ports"`
+		Nested  nested         `json:"nested"`
+		Labels  map[string]int `json:"labels"`
+		Ignored string         `json:"-"`
+	}
+	schema := SchemaOf(body{})
+
+	enumSchema := SchemaOf(struct {
+		Status string `json:"status"`
+	}{})
+	enumSchema.Properties["status"].Enum = []string{"running", "stopped"}
+
+	testCases := map[string]struct {
+		schema     *Schema
+		data       string
+		errMessage string
+	}{
+		"empty_object": {
+			schema: schema,
+			data:   `{}`,
+		},
+		"all_fields_set": {
+			schema: schema,
+			data: `{"name":"x","port":1,"address":"1.2.3.4","ports":[1,2],` +
+				`"nested":{"enabled":true,"period":1000},"labels":{"a":1}}`,
+		},
+		"null_pointer_field": {
+			schema: schema,
+			data:   `{"port":null,"nested":{"enabled":null}}`,
+		},
+		"malformed_json": {
+			schema:     schema,
+			data:       `{`,
+			errMessage: "body is not valid JSON: unexpected EOF",
+		},
+		"not_an_object": {
+			schema:     schema,
+			data:       `[]`,
+			errMessage: "type mismatch: expected object, got array",
+		},
+		"unknown_field": {
+			schema:     schema,
+			data:       `{"nested":{"unknown":1}}`,
+			errMessage: "property is unknown: nested.unknown",
+		},
+		"case_insensitive_keys": {
+			schema: schema,
+			data:   `{"Name":"x","NESTED":{"Enabled":true}}`,
+		},
+		"case_insensitive_key_wrong_type": {
+			schema:     schema,
+			data:       `{"PORT":"x"}`,
+			errMessage: "PORT: type mismatch: expected integer, got string",
+		},
+		"json_ignored_field": {
+			schema:     schema,
+			data:       `{"Ignored":"x"}`,
+			errMessage: "property is unknown: Ignored",
+		},
+		"null_non_pointer_field": {
+			schema:     schema,
+			data:       `{"name":null}`,
+			errMessage: "name: type mismatch: expected string, got null",
+		},
+		"wrong_array_element_type": {
+			schema:     schema,
+			data:       `{"ports":[1,"2"]}`,
+			errMessage: "ports[1]: type mismatch: expected integer, got string",
+		},
+		"non_integer_number": {
+			schema:     schema,
+			data:       `{"nested":{"period":1.5}}`,
+			errMessage: "nested.period: type mismatch: expected integer, got number",
+		},
+		"wrong_map_value_type": {
+			schema:     schema,
+			data:       `{"labels":{"a":true}}`,
+			errMessage: "labels.a: type mismatch: expected integer, got boolean",
+		},
+		"enum_valid": {
+			schema: enumSchema,
+			data:   `{"status":"running"}`,
+		},
+		"enum_invalid": {
+			schema:     enumSchema,
+			data:       `{"status":"crashed"}`,
+			errMessage: `status: value is not one of the allowed values: "crashed" must be one of running, stopped`,
+		},
+		"one_of_matching": {
+			schema: &Schema{OneOf: []*Schema{{Type: "string"}, {Type: "boolean"}}},
+			data:   `true`,
+		},
+		"one_of_not_matching": {
+			schema:     &Schema{OneOf: []*Schema{{Type: "string"}, {Type: "boolean"}}},
+			data:       `1`,
+			errMessage: "value does not match any of the schemas",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := testCase.schema.Validate([]byte(testCase.data))
+
+			if testCase.errMessage == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
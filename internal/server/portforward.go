@@ -0,0 +1,92 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func newPortForwardHandler(looper PortForwardLooper, w warner) http.Handler {
+	return &portForwardHandler{
+		looper: looper,
+		warner: w,
+	}
+}
+
+type portForwardHandler struct {
+	looper PortForwardLooper
+	warner warner
+}
+
+func (h *portForwardHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.RequestURI = strings.TrimPrefix(r.RequestURI, "/portforward")
+	switch r.RequestURI {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			h.getDetails(w)
+		case http.MethodPut:
+			h.applyAction(w, r)
+		default:
+			errMethodNotSupported(w, r.Method)
+		}
+	default:
+		errRouteNotSupported(w, r.RequestURI)
+	}
+}
+
+func (h *portForwardHandler) getDetails(w http.ResponseWriter) {
+	details := h.looper.GetDetails()
+	data := newPortForwardWrapper(details)
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(data); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+const (
+	portForwardActionRefresh = "refresh"
+	portForwardActionRenew   = "renew"
+)
+
+var errPortForwardActionNotValid = errors.New("port forward action is not valid")
+
+func (h *portForwardHandler) applyAction(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	var data portForwardActionWrapper
+	if err := decoder.Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var requestNewPort bool
+	var outcome string
+	switch data.Action {
+	case portForwardActionRefresh:
+		outcome = "port forwarding refreshed"
+	case portForwardActionRenew:
+		requestNewPort = true
+		outcome = "port forwarding renewed"
+	default:
+		err := fmt.Errorf("%w: %s: possible values are: %s, %s",
+			errPortForwardActionNotValid, data.Action,
+			portForwardActionRefresh, portForwardActionRenew)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err := h.looper.Refresh(requestNewPort)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.warner.Warn(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/portforward"
+	"github.com/qdm12/gluetun/internal/portforward/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_portForwardHandler_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	testCases := map[string]struct {
+		method       string
+		path         string
+		body         string
+		makeLooper   func(ctrl *gomock.Controller) *MockPortForwardLooper
+		statusCode   int
+		responseBody string
+	}{
+		"get_details_no_port": {
+			method: http.MethodGet,
+			path:   "/portforward",
+			makeLooper: func(ctrl *gomock.Controller) *MockPortForwardLooper {
+				looper := NewMockPortForwardLooper(ctrl)
+				looper.EXPECT().GetDetails().Return(service.Details{})
+				return looper
+			},
+			statusCode: http.StatusOK,
+			responseBody: `{"ports":[],"protocols":[],"gateway":"","provider":"",` +
+				`"expiry":null,"last_keep_alive":null}` + "\n",
+		},
+		"get_details": {
+			method: http.MethodGet,
+			path:   "/portforward",
+			makeLooper: func(ctrl *gomock.Controller) *MockPortForwardLooper {
+				looper := NewMockPortForwardLooper(ctrl)
+				looper.EXPECT().GetDetails().Return(service.Details{
+					Ports:         []uint16{1234},
+					Protocols:     []string{"tcp", "udp"},
+					Gateway:       netip.AddrFrom4([4]byte{10, 0, 0, 1}),
+					Provider:      "private internet access",
+					Expiry:        time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+					LastKeepAlive: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				})
+				return looper
+			},
+			statusCode: http.StatusOK,
+			responseBody: `{"ports":[1234],"protocols":["tcp","udp"],"gateway":"10.0.0.1",` +
+				`"provider":"private internet access","expiry":"2024-01-02T00:00:00Z",` +
+				`"last_keep_alive":"2024-01-01T00:00:00Z"}` + "\n",
+		},
+		"refresh": {
+			method: http.MethodPut,
+			path:   "/portforward",
+			body:   `{"action":"refresh"}`,
+			makeLooper: func(ctrl *gomock.Controller) *MockPortForwardLooper {
+				looper := NewMockPortForwardLooper(ctrl)
+				looper.EXPECT().Refresh(false).Return(nil)
+				return looper
+			},
+			statusCode:   http.StatusOK,
+			responseBody: `{"outcome":"port forwarding refreshed"}` + "\n",
+		},
+		"renew": {
+			method: http.MethodPut,
+			path:   "/portforward",
+			body:   `{"action":"renew"}`,
+			makeLooper: func(ctrl *gomock.Controller) *MockPortForwardLooper {
+				looper := NewMockPortForwardLooper(ctrl)
+				looper.EXPECT().Refresh(true).Return(nil)
+				return looper
+			},
+			statusCode:   http.StatusOK,
+			responseBody: `{"outcome":"port forwarding renewed"}` + "\n",
+		},
+		"refresh_error": {
+			method: http.MethodPut,
+			path:   "/portforward",
+			body:   `{"action":"refresh"}`,
+			makeLooper: func(ctrl *gomock.Controller) *MockPortForwardLooper {
+				looper := NewMockPortForwardLooper(ctrl)
+				looper.EXPECT().Refresh(false).Return(errTest)
+				return looper
+			},
+			statusCode:   http.StatusBadRequest,
+			responseBody: "test error\n",
+		},
+		"renew_not_supported": {
+			method: http.MethodPut,
+			path:   "/portforward",
+			body:   `{"action":"renew"}`,
+			makeLooper: func(ctrl *gomock.Controller) *MockPortForwardLooper {
+				looper := NewMockPortForwardLooper(ctrl)
+				err := fmt.Errorf("%w: for provider protonvpn", portforward.ErrNewPortNotSupported)
+				looper.EXPECT().Refresh(true).Return(err)
+				return looper
+			},
+			statusCode: http.StatusBadRequest,
+			responseBody: "requesting a new port is not supported: " +
+				"for provider protonvpn\n",
+		},
+		"invalid_action": {
+			method:     http.MethodPut,
+			path:       "/portforward",
+			body:       `{"action":"reset"}`,
+			statusCode: http.StatusBadRequest,
+			responseBody: "port forward action is not valid: reset: " +
+				"possible values are: refresh, renew\n",
+		},
+		"malformed_body": {
+			method:       http.MethodPut,
+			path:         "/portforward",
+			body:         `{`,
+			statusCode:   http.StatusBadRequest,
+			responseBody: "unexpected EOF\n",
+		},
+		"method_not_supported": {
+			method:       http.MethodDelete,
+			path:         "/portforward",
+			statusCode:   http.StatusBadRequest,
+			responseBody: "method DELETE not supported\n",
+		},
+		"route_not_supported": {
+			method:       http.MethodGet,
+			path:         "/portforward/x",
+			statusCode:   http.StatusBadRequest,
+			responseBody: "route /x not supported\n",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			var looper PortForwardLooper
+			if testCase.makeLooper != nil {
+				looper = testCase.makeLooper(ctrl)
+			}
+			handler := newPortForwardHandler(looper, NewMockLogger(ctrl))
+
+			request := httptest.NewRequest(testCase.method, testCase.path,
+				strings.NewReader(testCase.body))
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, request)
+
+			response := recorder.Result()
+			defer response.Body.Close()
+			assert.Equal(t, testCase.statusCode, response.StatusCode)
+			responseBody, err := io.ReadAll(response.Body)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.responseBody, string(responseBody))
+		})
+	}
+}
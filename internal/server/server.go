@@ -4,28 +4,38 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/httpserver"
 	"github.com/qdm12/gluetun/internal/models"
-	"github.com/qdm12/gluetun/internal/server/middlewares/auth"
 )
 
 func New(ctx context.Context, address string, logEnabled bool, logger Logger,
-	authSettings auth.Settings, buildInfo models.BuildInformation, openvpnLooper VPNLooper,
-	pfGetter PortForwardedGetter, dnsLooper DNSLoop,
-	updaterLooper UpdaterLooper, publicIPLooper PublicIPLoop, storage Storage,
-	ipv6Supported bool) (
+	allSettings settings.Settings, buildInfo models.BuildInformation, openvpnLooper VPNLooper,
+	pfLooper PortForwardLooper, dnsLooper DNSLoop,
+	updaterLooper UpdaterLooper, publicIPLooper PublicIPLoop,
+	httpProxyLooper HTTPProxyLooper, shadowsocksLooper ShadowsocksLooper,
+	healthChecker HealthChecker, eventSubscriber EventSubscriber, firewall Firewall, routing Routing,
+	storage Storage, ipv6Supported bool) (
 	server *httpserver.Server, err error) {
-	handler, err := newHandler(ctx, logger, logEnabled, authSettings, buildInfo,
-		openvpnLooper, pfGetter, dnsLooper, updaterLooper, publicIPLooper,
-		storage, ipv6Supported)
+	handler, err := newHandler(ctx, logger, logEnabled, allSettings, buildInfo,
+		openvpnLooper, pfLooper, dnsLooper, updaterLooper, publicIPLooper,
+		httpProxyLooper, shadowsocksLooper, healthChecker,
+		eventSubscriber, firewall, routing, storage, ipv6Supported)
 	if err != nil {
 		return nil, fmt.Errorf("creating handler: %w", err)
 	}
 
+	uid, gid := int(*allSettings.System.PUID), int(*allSettings.System.PGID)
 	httpServerSettings := httpserver.Settings{
-		Address: address,
-		Handler: handler,
-		Logger:  logger,
+		Address:                address,
+		UnixSocketMode:         *allSettings.ControlServer.UnixSocketMode,
+		UnixSocketUID:          &uid,
+		UnixSocketGID:          &gid,
+		TLSCertificateFilepath: allSettings.ControlServer.TLSCertificateFilepath,
+		TLSKeyFilepath:         allSettings.ControlServer.TLSKeyFilepath,
+		TLSClientCAFilepath:    allSettings.ControlServer.TLSClientCAFilepath,
+		Handler:                handler,
+		Logger:                 logger,
 	}
 
 	server, err = httpserver.New(httpServerSettings)
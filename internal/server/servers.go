@@ -0,0 +1,308 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/constants/providers"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/storage"
+)
+
+func newServersHandler(looper VPNLooper, storage Storage, w warner) http.Handler {
+	return &serversHandler{
+		looper:  looper,
+		storage: storage,
+		warner:  w,
+	}
+}
+
+type serversHandler struct {
+	looper  VPNLooper
+	storage Storage
+	warner  warner
+}
+
+func (h *serversHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.RequestURI = strings.TrimPrefix(r.RequestURI, "/servers")
+	// Query parameters are parsed from r.URL
+	r.RequestURI, _, _ = strings.Cut(r.RequestURI, "?")
+	switch r.RequestURI {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			h.getServers(w, r)
+		default:
+			errMethodNotSupported(w, r.Method)
+		}
+	default:
+		errRouteNotSupported(w, r.RequestURI)
+	}
+}
+
+func (h *serversHandler) getServers(w http.ResponseWriter, r *http.Request) {
+	query, err := parseServersQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if query.provider == "" {
+		query.provider = h.looper.GetSettings().Provider.Name
+	}
+
+	servers, err := h.storage.FilterServers(query.provider, query.selection)
+	if err != nil && !errors.Is(err, storage.ErrNoServerFound) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	sortServers(servers, query.sortBy, query.descending)
+
+	data := serversWrapper{
+		Provider: query.provider,
+		Total:    len(servers),
+		Offset:   query.offset,
+		Limit:    query.limit,
+		Servers:  paginate(servers, query.offset, query.limit),
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(data); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+type serversQuery struct {
+	provider   string
+	selection  settings.ServerSelection
+	sortBy     string
+	descending bool
+	offset     int
+	limit      int
+}
+
+const (
+	serversDefaultLimit = 100
+	serversOrderAsc     = "asc"
+	serversOrderDesc    = "desc"
+)
+
+func serversSortFields() []string {
+	return []string{"country", "region", "city", "isp",
+		"server_name", "hostname", "number"}
+}
+
+var (
+	errQueryParameterNotValid = errors.New("query parameter is not valid")
+)
+
+func parseServersQuery(values url.Values) (query serversQuery, err error) {
+	query.provider = values.Get("provider")
+	if query.provider != "" &&
+		!isOneOfInsensitive(query.provider, providers.AllWithCustom()) {
+		return query, fmt.Errorf("%w: provider %q must be one of %s",
+			errQueryParameterNotValid, query.provider,
+			strings.Join(providers.AllWithCustom(), ", "))
+	}
+	query.provider = strings.ToLower(query.provider)
+
+	selection := settings.ServerSelection{
+		VPN:        strings.ToLower(values.Get("vpn")),
+		Countries:  queryList(values, "countries"),
+		Categories: queryList(values, "categories"),
+		Regions:    queryList(values, "regions"),
+		Cities:     queryList(values, "cities"),
+		ISPs:       queryList(values, "isps"),
+		Names:      queryList(values, "names"),
+		Hostnames:  queryList(values, "hostnames"),
+		OpenVPN: settings.OpenVPNSelection{
+			Protocol: strings.ToLower(values.Get("protocol")),
+		},
+	}
+
+	if selection.VPN == "" {
+		selection.VPN = vpn.OpenVPN
+	} else if selection.VPN != vpn.OpenVPN && selection.VPN != vpn.Wireguard {
+		return query, fmt.Errorf("%w: vpn %q must be one of %s, %s",
+			errQueryParameterNotValid, selection.VPN, vpn.OpenVPN, vpn.Wireguard)
+	}
+
+	if selection.OpenVPN.Protocol == "" {
+		selection.OpenVPN.Protocol = constants.UDP
+	} else if selection.OpenVPN.Protocol != constants.UDP &&
+		selection.OpenVPN.Protocol != constants.TCP {
+		return query, fmt.Errorf("%w: protocol %q must be one of %s, %s",
+			errQueryParameterNotValid, selection.OpenVPN.Protocol,
+			constants.UDP, constants.TCP)
+	}
+
+	for _, number := range queryList(values, "numbers") {
+		const base, bitSize = 10, 16
+		parsed, err := strconv.ParseUint(number, base, bitSize)
+		if err != nil {
+			return query, fmt.Errorf("%w: numbers: %w", errQueryParameterNotValid, err)
+		}
+		selection.Numbers = append(selection.Numbers, uint16(parsed))
+	}
+
+	boolFields := []struct {
+		name  string
+		field **bool
+	}{
+		{name: "owned_only", field: &selection.OwnedOnly},
+		{name: "free_only", field: &selection.FreeOnly},
+		{name: "premium_only", field: &selection.PremiumOnly},
+		{name: "stream_only", field: &selection.StreamOnly},
+		{name: "multi_hop_only", field: &selection.MultiHopOnly},
+		{name: "port_forward_only", field: &selection.PortForwardOnly},
+		{name: "secure_core_only", field: &selection.SecureCoreOnly},
+		{name: "tor_only", field: &selection.TorOnly},
+	}
+	for _, boolField := range boolFields {
+		value := false
+		if s := values.Get(boolField.name); s != "" {
+			value, err = strconv.ParseBool(s)
+			if err != nil {
+				return query, fmt.Errorf("%w: %s: %w",
+					errQueryParameterNotValid, boolField.name, err)
+			}
+		}
+		*boolField.field = &value
+	}
+	query.selection = selection
+
+	query.sortBy = values.Get("sort")
+	if query.sortBy != "" && !isOneOfInsensitive(query.sortBy, serversSortFields()) {
+		return query, fmt.Errorf("%w: sort %q must be one of %s",
+			errQueryParameterNotValid, query.sortBy,
+			strings.Join(serversSortFields(), ", "))
+	}
+	query.sortBy = strings.ToLower(query.sortBy)
+
+	switch order := values.Get("order"); order {
+	case "", serversOrderAsc:
+	case serversOrderDesc:
+		query.descending = true
+	default:
+		return query, fmt.Errorf("%w: order %q must be one of %s, %s",
+			errQueryParameterNotValid, order, serversOrderAsc, serversOrderDesc)
+	}
+
+	query.offset, err = queryNonNegativeInt(values, "offset", 0)
+	if err != nil {
+		return query, err
+	}
+
+	query.limit, err = queryNonNegativeInt(values, "limit", serversDefaultLimit)
+	if err != nil {
+		return query, err
+	}
+
+	return query, nil
+}
+
+// queryList returns the values for the query parameter key, where
+// values can be given as repeated parameters and comma separated.
+func queryList(values url.Values, key string) (list []string) {
+	for _, value := range values[key] {
+		for _, element := range strings.Split(value, ",") {
+			element = strings.TrimSpace(element)
+			if element != "" {
+				list = append(list, element)
+			}
+		}
+	}
+	return list
+}
+
+func queryNonNegativeInt(values url.Values, key string,
+	defaultValue int) (n int, err error) {
+	s := values.Get(key)
+	if s == "" {
+		return defaultValue, nil
+	}
+	n, err = strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s: %w", errQueryParameterNotValid, key, err)
+	} else if n < 0 {
+		return 0, fmt.Errorf("%w: %s: %d must be positive or zero",
+			errQueryParameterNotValid, key, n)
+	}
+	return n, nil
+}
+
+func isOneOfInsensitive(value string, choices []string) bool {
+	for _, choice := range choices {
+		if strings.EqualFold(value, choice) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortServers(servers []models.Server, sortBy string, descending bool) {
+	if sortBy == "" {
+		if descending {
+			for i, j := 0, len(servers)-1; i < j; i, j = i+1, j-1 {
+				servers[i], servers[j] = servers[j], servers[i]
+			}
+		}
+		return
+	}
+
+	sort.SliceStable(servers, func(i, j int) bool {
+		a, b := servers[i], servers[j]
+		if descending {
+			a, b = b, a
+		}
+		switch sortBy {
+		case "number":
+			return a.Number < b.Number
+		default:
+			return serverSortKey(a, sortBy) < serverSortKey(b, sortBy)
+		}
+	})
+}
+
+func serverSortKey(server models.Server, sortBy string) (key string) {
+	switch sortBy {
+	case "country":
+		return server.Country
+	case "region":
+		return server.Region
+	case "city":
+		return server.City
+	case "isp":
+		return server.ISP
+	case "server_name":
+		return server.ServerName
+	case "hostname":
+		return server.Hostname
+	default:
+		panic("unknown sort field: " + sortBy)
+	}
+}
+
+// paginate returns the servers from offset, limited to limit
+// servers, or all remaining servers if limit is zero.
+func paginate(servers []models.Server, offset, limit int) (page []models.Server) {
+	page = []models.Server{}
+	if offset >= len(servers) {
+		return page
+	}
+	servers = servers[offset:]
+	if limit > 0 && limit < len(servers) {
+		servers = servers[:limit]
+	}
+	return append(page, servers...)
+}
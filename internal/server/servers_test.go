@@ -0,0 +1,129 @@
+package server
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseServersQuery(t *testing.T) {
+	t.Parallel()
+
+	ptrTo := func(value bool) *bool { return &value }
+	defaultSelection := func() settings.ServerSelection {
+		return settings.ServerSelection{
+			VPN:             "openvpn",
+			OwnedOnly:       ptrTo(false),
+			FreeOnly:        ptrTo(false),
+			PremiumOnly:     ptrTo(false),
+			StreamOnly:      ptrTo(false),
+			MultiHopOnly:    ptrTo(false),
+			PortForwardOnly: ptrTo(false),
+			SecureCoreOnly:  ptrTo(false),
+			TorOnly:         ptrTo(false),
+			OpenVPN:         settings.OpenVPNSelection{Protocol: "udp"},
+		}
+	}
+
+	testCases := map[string]struct {
+		query      string
+		expected   serversQuery
+		errMessage string
+	}{
+		"empty": {
+			expected: serversQuery{
+				selection: defaultSelection(),
+				limit:     serversDefaultLimit,
+			},
+		},
+		"all_set": {
+			query: "provider=Mullvad&vpn=wireguard&protocol=tcp" +
+				"&countries=Sweden,%20Norway&countries=Finland&numbers=1,2" +
+				"&owned_only=true&sort=City&order=desc&offset=10&limit=0",
+			expected: serversQuery{
+				provider: "mullvad",
+				selection: func() settings.ServerSelection {
+					selection := defaultSelection()
+					selection.VPN = "wireguard"
+					selection.OpenVPN.Protocol = "tcp"
+					selection.Countries = []string{"Sweden", "Norway", "Finland"}
+					selection.Numbers = []uint16{1, 2}
+					selection.OwnedOnly = ptrTo(true)
+					return selection
+				}(),
+				sortBy:     "city",
+				descending: true,
+				offset:     10,
+			},
+		},
+		"invalid_provider": {
+			query:      "provider=x",
+			errMessage: `query parameter is not valid: provider "x" must be one of `,
+		},
+		"invalid_boolean": {
+			query: "tor_only=x",
+			errMessage: `query parameter is not valid: tor_only: ` +
+				`strconv.ParseBool: parsing "x": invalid syntax`,
+		},
+		"invalid_sort": {
+			query: "sort=ips",
+			errMessage: `query parameter is not valid: sort "ips" must be one of ` +
+				`country, region, city, isp, server_name, hostname, number`,
+		},
+		"negative_limit": {
+			query:      "limit=-1",
+			errMessage: "query parameter is not valid: limit: -1 must be positive or zero",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			values, err := url.ParseQuery(testCase.query)
+			assert.NoError(t, err)
+
+			query, err := parseServersQuery(values)
+
+			if testCase.errMessage != "" {
+				assert.ErrorIs(t, err, errQueryParameterNotValid)
+				assert.Contains(t, err.Error(), testCase.errMessage)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, query)
+		})
+	}
+}
+
+func Test_sortServers_paginate(t *testing.T) {
+	t.Parallel()
+
+	servers := []models.Server{
+		{Hostname: "b", Number: 3},
+		{Hostname: "c", Number: 1},
+		{Hostname: "a", Number: 2},
+	}
+
+	sortServers(servers, "hostname", false)
+	assert.Equal(t, []models.Server{
+		{Hostname: "a", Number: 2},
+		{Hostname: "b", Number: 3},
+		{Hostname: "c", Number: 1},
+	}, servers)
+
+	sortServers(servers, "number", true)
+	assert.Equal(t, []models.Server{
+		{Hostname: "b", Number: 3},
+		{Hostname: "a", Number: 2},
+		{Hostname: "c", Number: 1},
+	}, servers)
+
+	assert.Equal(t, []models.Server{{Hostname: "a", Number: 2}}, paginate(servers, 1, 1))
+	assert.Equal(t, servers[1:], paginate(servers, 1, 0))
+	assert.Equal(t, []models.Server{}, paginate(servers, 5, 1))
+}
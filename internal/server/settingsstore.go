@@ -0,0 +1,68 @@
+package server
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+// settingsStore holds the last known settings, shared by the
+// handlers changing settings at runtime.
+type settingsStore struct {
+	// mutex must be locked to access the fields below.
+	mutex    sync.Mutex
+	settings settings.Settings
+	// inputPorts maps each input port allowed by the user, with the
+	// FIREWALL_INPUT_PORTS setting or through the control server, to
+	// the network interfaces it was allowed through. Only these ports
+	// can be removed through the control server, so the ports allowed
+	// for the VPN input ports or the forwarded port are left untouched.
+	inputPorts map[uint16][]string
+}
+
+func newSettingsStore(allSettings settings.Settings,
+	defaultInterfaces []string) *settingsStore {
+	inputPorts := make(map[uint16][]string, len(allSettings.Firewall.InputPorts))
+	for _, port := range allSettings.Firewall.InputPorts {
+		inputPorts[port] = slices.Clone(defaultInterfaces)
+	}
+	return &settingsStore{
+		settings:   allSettings,
+		inputPorts: inputPorts,
+	}
+}
+
+// addInputPort records the port as allowed by the user through
+// the interfaces given, if any, and updates the firewall input ports
+// settings. It must be called with the mutex locked.
+func (s *settingsStore) addInputPort(port uint16, interfaces []string) {
+	existing := s.inputPorts[port]
+	for _, intf := range interfaces {
+		if !slices.Contains(existing, intf) {
+			existing = append(existing, intf)
+		}
+	}
+	if len(existing) == 0 {
+		return
+	}
+	s.inputPorts[port] = existing
+	s.updateInputPortsSettings()
+}
+
+// removeInputPort removes the port from the ports allowed by the
+// user, and updates the firewall input ports settings.
+// It must be called with the mutex locked.
+func (s *settingsStore) removeInputPort(port uint16) {
+	delete(s.inputPorts, port)
+	s.updateInputPortsSettings()
+}
+
+func (s *settingsStore) updateInputPortsSettings() {
+	ports := make([]uint16, 0, len(s.inputPorts))
+	for port := range s.inputPorts {
+		ports = append(ports, port)
+	}
+	slices.Sort(ports)
+	s.settings.Firewall.InputPorts = ports
+}
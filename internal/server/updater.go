@@ -14,6 +14,7 @@ type UpdaterLooper interface {
 	GetStatus() (status models.LoopStatus)
 	SetStatus(ctx context.Context, status models.LoopStatus) (
 		outcome string, err error)
+	GetSettings() (settings settings.Updater)
 	SetSettings(settings settings.Updater) (outcome string)
 }
 
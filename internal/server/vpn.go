@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
 )
 
 func newVPNHandler(ctx context.Context, looper VPNLooper,
@@ -49,6 +51,34 @@ func (h *vpnHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		default:
 			errMethodNotSupported(w, r.Method)
 		}
+	case "/connection":
+		switch r.Method {
+		case http.MethodGet:
+			h.getConnection(w)
+		default:
+			errMethodNotSupported(w, r.Method)
+		}
+	case "/connection/rotate":
+		switch r.Method {
+		case http.MethodPost:
+			h.rotateConnection(w)
+		default:
+			errMethodNotSupported(w, r.Method)
+		}
+	case "/failures":
+		switch r.Method {
+		case http.MethodGet:
+			h.getFailures(w)
+		default:
+			errMethodNotSupported(w, r.Method)
+		}
+	case "/escalation":
+		switch r.Method {
+		case http.MethodGet:
+			h.getEscalation(w)
+		default:
+			errMethodNotSupported(w, r.Method)
+		}
 	default:
 		errRouteNotSupported(w, r.RequestURI)
 	}
@@ -128,3 +158,94 @@ func (h *vpnHandler) patchSettings(w http.ResponseWriter, r *http.Request) {
 		h.warner.Warn("writing response: " + err.Error())
 	}
 }
+
+func (h *vpnHandler) getConnection(w http.ResponseWriter) {
+	connection, ok := h.looper.GetConnection()
+	if !ok {
+		http.Error(w, "no VPN connection in use", http.StatusNotFound)
+		return
+	}
+
+	// Use the active settings, which are the fallback VPN
+	// provider settings if the fallback provider is in use.
+	settings := h.looper.GetActiveSettings()
+	data := connectionWrapper{
+		Connection: connection,
+		Provider:   settings.Provider.Name,
+	}
+	server, ok := findConnectionServer(h.storage, settings.Provider.Name,
+		settings.Provider.ServerSelection, connection)
+	if ok {
+		data.Country = server.Country
+		data.Region = server.Region
+		data.City = server.City
+	}
+
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(data); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// findConnectionServer returns the server matching the connection
+// from the servers filtered with the selection given, and false
+// if no server matches.
+func findConnectionServer(storage Storage, provider string,
+	selection settings.ServerSelection, connection models.Connection) (
+	server models.Server, ok bool) {
+	servers, err := storage.FilterServers(provider, selection)
+	if err != nil {
+		return server, false
+	}
+
+	for _, server := range servers {
+		switch {
+		case connection.ServerName != "":
+			ok = server.ServerName == connection.ServerName
+		case connection.Hostname != "":
+			ok = server.Hostname == connection.Hostname ||
+				server.OvpnX509 == connection.Hostname
+		default:
+			ok = slices.Contains(server.IPs, connection.IP)
+		}
+		if ok {
+			return server, true
+		}
+	}
+	return server, false
+}
+
+func (h *vpnHandler) rotateConnection(w http.ResponseWriter) {
+	outcome, err := h.looper.RotateConnection(h.ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.warner.Warn(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *vpnHandler) getFailures(w http.ResponseWriter) {
+	data := failuresWrapper{Failures: h.looper.GetFailureHistory()}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(data); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *vpnHandler) getEscalation(w http.ResponseWriter) {
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(h.looper.GetEscalation()); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_vpnHandler_getConnection(t *testing.T) {
+	t.Parallel()
+
+	connection := models.Connection{
+		Type:     "wireguard",
+		IP:       netip.AddrFrom4([4]byte{1, 2, 3, 4}),
+		Port:     51820,
+		Protocol: "udp",
+	}
+	// The active settings are the fallback provider settings.
+	activeSettings := settings.VPN{
+		Provider: settings.Provider{
+			Name:            "mullvad",
+			ServerSelection: settings.ServerSelection{VPN: "wireguard"},
+		},
+	}
+
+	testCases := map[string]struct {
+		makeLooper   func(ctrl *gomock.Controller) *MockVPNLooper
+		makeStorage  func(ctrl *gomock.Controller) *MockStorage
+		statusCode   int
+		responseBody string
+	}{
+		"no_connection": {
+			makeLooper: func(ctrl *gomock.Controller) *MockVPNLooper {
+				looper := NewMockVPNLooper(ctrl)
+				looper.EXPECT().GetConnection().Return(models.Connection{}, false)
+				return looper
+			},
+			statusCode:   http.StatusNotFound,
+			responseBody: "no VPN connection in use\n",
+		},
+		"fallback_provider_server": {
+			makeLooper: func(ctrl *gomock.Controller) *MockVPNLooper {
+				looper := NewMockVPNLooper(ctrl)
+				looper.EXPECT().GetConnection().Return(connection, true)
+				looper.EXPECT().GetActiveSettings().Return(activeSettings)
+				return looper
+			},
+			makeStorage: func(ctrl *gomock.Controller) *MockStorage {
+				storage := NewMockStorage(ctrl)
+				storage.EXPECT().FilterServers("mullvad", activeSettings.Provider.ServerSelection).
+					Return([]models.Server{
+						{Country: "Norway", IPs: []netip.Addr{netip.AddrFrom4([4]byte{5, 6, 7, 8})}},
+						{Country: "Sweden", City: "Malmo", IPs: []netip.Addr{connection.IP}},
+					}, nil)
+				return storage
+			},
+			statusCode: http.StatusOK,
+			responseBody: `{"type":"wireguard","ip":"1.2.3.4","port":51820,"protocol":"udp",` +
+				`"hostname":"","pubkey":"","port_forward":false,"provider":"mullvad",` +
+				`"country":"Sweden","city":"Malmo"}` + "\n",
+		},
+		"servers_filtering_error": {
+			makeLooper: func(ctrl *gomock.Controller) *MockVPNLooper {
+				looper := NewMockVPNLooper(ctrl)
+				looper.EXPECT().GetConnection().Return(connection, true)
+				looper.EXPECT().GetActiveSettings().Return(activeSettings)
+				return looper
+			},
+			makeStorage: func(ctrl *gomock.Controller) *MockStorage {
+				storage := NewMockStorage(ctrl)
+				storage.EXPECT().FilterServers("mullvad", activeSettings.Provider.ServerSelection).
+					Return(nil, errors.New("test error"))
+				return storage
+			},
+			statusCode: http.StatusOK,
+			responseBody: `{"type":"wireguard","ip":"1.2.3.4","port":51820,"protocol":"udp",` +
+				`"hostname":"","pubkey":"","port_forward":false,"provider":"mullvad"}` + "\n",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			looper := testCase.makeLooper(ctrl)
+			var storage Storage
+			if testCase.makeStorage != nil {
+				storage = testCase.makeStorage(ctrl)
+			}
+			handler := newVPNHandler(context.Background(), looper, storage,
+				false, NewMockLogger(ctrl))
+
+			request := httptest.NewRequest(http.MethodGet, "/vpn/connection", nil)
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, request)
+
+			response := recorder.Result()
+			defer response.Body.Close()
+			assert.Equal(t, testCase.statusCode, response.StatusCode)
+			responseBody, err := io.ReadAll(response.Body)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.responseBody, string(responseBody))
+		})
+	}
+}
@@ -3,9 +3,13 @@ package server
 import (
 	"errors"
 	"fmt"
+	"net/netip"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/portforward/service"
+	"github.com/qdm12/gluetun/internal/server/middlewares/audit"
 )
 
 type statusWrapper struct {
@@ -36,3 +40,81 @@ type portsWrapper struct {
 type outcomeWrapper struct {
 	Outcome string `json:"outcome"`
 }
+
+type portForwardWrapper struct {
+	Ports         []uint16   `json:"ports"`
+	Protocols     []string   `json:"protocols"`
+	Gateway       netip.Addr `json:"gateway"`
+	Provider      string     `json:"provider"`
+	Expiry        *time.Time `json:"expiry"`
+	LastKeepAlive *time.Time `json:"last_keep_alive"`
+}
+
+func newPortForwardWrapper(details service.Details) (wrapper portForwardWrapper) {
+	wrapper = portForwardWrapper{
+		Ports:     details.Ports,
+		Protocols: details.Protocols,
+		Gateway:   details.Gateway,
+		Provider:  details.Provider,
+	}
+	if wrapper.Ports == nil {
+		wrapper.Ports = []uint16{}
+	}
+	if wrapper.Protocols == nil {
+		wrapper.Protocols = []string{}
+	}
+	if !details.Expiry.IsZero() {
+		wrapper.Expiry = &details.Expiry
+	}
+	if !details.LastKeepAlive.IsZero() {
+		wrapper.LastKeepAlive = &details.LastKeepAlive
+	}
+	return wrapper
+}
+
+type portForwardActionWrapper struct {
+	Action string `json:"action"`
+}
+
+type serversWrapper struct {
+	Provider string          `json:"provider"`
+	Total    int             `json:"total"`
+	Offset   int             `json:"offset"`
+	Limit    int             `json:"limit"`
+	Servers  []models.Server `json:"servers"`
+}
+
+type firewallPortWrapper struct {
+	Port uint16 `json:"port"`
+	// Interface is the network interface to allow the port on,
+	// and defaults to all the default routes interfaces if empty.
+	Interface string `json:"interface,omitempty"`
+}
+
+type subnetsWrapper struct {
+	Subnets []netip.Prefix `json:"subnets"`
+}
+
+type outcomesWrapper struct {
+	Outcomes map[string]string `json:"outcomes"`
+}
+
+type auditWrapper struct {
+	Entries []audit.Entry `json:"entries"`
+}
+
+type connectionWrapper struct {
+	models.Connection
+	Provider string `json:"provider"`
+	Country  string `json:"country,omitempty"`
+	Region   string `json:"region,omitempty"`
+	City     string `json:"city,omitempty"`
+}
+
+type failuresWrapper struct {
+	Failures []models.ServerFailure `json:"failures"`
+}
+
+type healthHistoryWrapper struct {
+	Results []models.HealthResult `json:"results"`
+}
@@ -0,0 +1,19 @@
+package splittunnel
+
+import (
+	"context"
+	"net/netip"
+)
+
+type Routing interface {
+	SetOutboundDomainRoutes(addresses []netip.Addr) (err error)
+}
+
+type Firewall interface {
+	SetOutboundDomainIPs(ctx context.Context, addresses []netip.Addr) (err error)
+}
+
+type Logger interface {
+	Debug(s string)
+	Error(s string)
+}
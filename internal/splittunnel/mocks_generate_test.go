@@ -0,0 +1,3 @@
+package splittunnel
+
+//go:generate mockgen -destination=mocks_test.go -package=$GOPACKAGE . Routing,Firewall,Logger
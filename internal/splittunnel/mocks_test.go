@@ -0,0 +1,134 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/qdm12/gluetun/internal/splittunnel (interfaces: Routing,Firewall,Logger)
+
+// Package splittunnel is a generated GoMock package.
+package splittunnel
+
+import (
+	context "context"
+	netip "net/netip"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRouting is a mock of Routing interface.
+type MockRouting struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoutingMockRecorder
+}
+
+// MockRoutingMockRecorder is the mock recorder for MockRouting.
+type MockRoutingMockRecorder struct {
+	mock *MockRouting
+}
+
+// NewMockRouting creates a new mock instance.
+func NewMockRouting(ctrl *gomock.Controller) *MockRouting {
+	mock := &MockRouting{ctrl: ctrl}
+	mock.recorder = &MockRoutingMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRouting) EXPECT() *MockRoutingMockRecorder {
+	return m.recorder
+}
+
+// SetOutboundDomainRoutes mocks base method.
+func (m *MockRouting) SetOutboundDomainRoutes(arg0 []netip.Addr) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOutboundDomainRoutes", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOutboundDomainRoutes indicates an expected call of SetOutboundDomainRoutes.
+func (mr *MockRoutingMockRecorder) SetOutboundDomainRoutes(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOutboundDomainRoutes", reflect.TypeOf((*MockRouting)(nil).SetOutboundDomainRoutes), arg0)
+}
+
+// MockFirewall is a mock of Firewall interface.
+type MockFirewall struct {
+	ctrl     *gomock.Controller
+	recorder *MockFirewallMockRecorder
+}
+
+// MockFirewallMockRecorder is the mock recorder for MockFirewall.
+type MockFirewallMockRecorder struct {
+	mock *MockFirewall
+}
+
+// NewMockFirewall creates a new mock instance.
+func NewMockFirewall(ctrl *gomock.Controller) *MockFirewall {
+	mock := &MockFirewall{ctrl: ctrl}
+	mock.recorder = &MockFirewallMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFirewall) EXPECT() *MockFirewallMockRecorder {
+	return m.recorder
+}
+
+// SetOutboundDomainIPs mocks base method.
+func (m *MockFirewall) SetOutboundDomainIPs(arg0 context.Context, arg1 []netip.Addr) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOutboundDomainIPs", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOutboundDomainIPs indicates an expected call of SetOutboundDomainIPs.
+func (mr *MockFirewallMockRecorder) SetOutboundDomainIPs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOutboundDomainIPs", reflect.TypeOf((*MockFirewall)(nil).SetOutboundDomainIPs), arg0, arg1)
+}
+
+// MockLogger is a mock of Logger interface.
+type MockLogger struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoggerMockRecorder
+}
+
+// MockLoggerMockRecorder is the mock recorder for MockLogger.
+type MockLoggerMockRecorder struct {
+	mock *MockLogger
+}
+
+// NewMockLogger creates a new mock instance.
+func NewMockLogger(ctrl *gomock.Controller) *MockLogger {
+	mock := &MockLogger{ctrl: ctrl}
+	mock.recorder = &MockLoggerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLogger) EXPECT() *MockLoggerMockRecorder {
+	return m.recorder
+}
+
+// Debug mocks base method.
+func (m *MockLogger) Debug(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Debug", arg0)
+}
+
+// Debug indicates an expected call of Debug.
+func (mr *MockLoggerMockRecorder) Debug(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Debug", reflect.TypeOf((*MockLogger)(nil).Debug), arg0)
+}
+
+// Error mocks base method.
+func (m *MockLogger) Error(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Error", arg0)
+}
+
+// Error indicates an expected call of Error.
+func (mr *MockLoggerMockRecorder) Error(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockLogger)(nil).Error), arg0)
+}
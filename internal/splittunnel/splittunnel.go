@@ -0,0 +1,215 @@
+// Package splittunnel routes the traffic to the IP addresses resolved
+// for a list of domains through the default route instead of the VPN.
+package splittunnel
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SplitTunnel keeps track of the IP addresses resolved for the outbound
+// domains, together with their expiry time derived from the DNS TTL.
+type SplitTunnel struct {
+	routing  Routing
+	firewall Firewall
+	logger   Logger
+
+	domains         []string
+	addressToExpiry map[netip.Addr]time.Time
+	mutex           sync.Mutex
+
+	timeNow func() time.Time
+}
+
+func New(domains []string, routing Routing, firewall Firewall,
+	logger Logger) *SplitTunnel {
+	return &SplitTunnel{
+		routing:         routing,
+		firewall:        firewall,
+		logger:          logger,
+		domains:         normalizeDomains(domains),
+		addressToExpiry: make(map[netip.Addr]time.Time),
+		timeNow:         time.Now,
+	}
+}
+
+func normalizeDomains(domains []string) (normalized []string) {
+	normalized = make([]string, len(domains))
+	for i, domain := range domains {
+		normalized[i] = strings.ToLower(strings.TrimSuffix(domain, "."))
+	}
+	return normalized
+}
+
+// Match returns true if the name given matches one of the domains.
+// A domain starting with "*." matches all its subdomains, but not
+// the domain itself.
+func (s *SplitTunnel) Match(name string) (ok bool) {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	for _, domain := range s.domains {
+		if matchDomain(domain, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchDomain(domain, name string) (ok bool) {
+	parentDomain, isWildcard := strings.CutPrefix(domain, "*.")
+	if isWildcard {
+		return strings.HasSuffix(name, "."+parentDomain)
+	}
+	return name == domain
+}
+
+// minimumTTL is the minimum duration an IP address is kept
+// for, such that a client has time to connect to it even
+// if the DNS record TTL is zero or very small.
+const minimumTTL = time.Minute
+
+// Add routes the traffic to the IP addresses given through the default
+// route until the TTL given expires. If an IP address is already known,
+// its expiry time is extended if needed. The IP addresses are only
+// recorded once the firewall and the routes are set successfully.
+func (s *SplitTunnel) Add(ctx context.Context, addresses []netip.Addr,
+	ttl time.Duration) (err error) {
+	ttl = max(ttl, minimumTTL)
+	expiry := s.timeNow().Add(ttl)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var newAddresses []netip.Addr
+	for _, address := range addresses {
+		address = address.Unmap()
+		_, exists := s.addressToExpiry[address]
+		if !exists && !slices.Contains(newAddresses, address) {
+			newAddresses = append(newAddresses, address)
+		}
+	}
+
+	if len(newAddresses) > 0 {
+		err = s.allow(ctx, newAddresses)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, address := range addresses {
+		address = address.Unmap()
+		if expiry.After(s.addressToExpiry[address]) {
+			s.addressToExpiry[address] = expiry
+		}
+	}
+	return nil
+}
+
+// allow sets the firewall and the routes for the IP addresses currently
+// known and the new IP addresses given. The firewall is restored to the
+// IP addresses currently known if setting the routes fails.
+// It must be called with the mutex locked.
+func (s *SplitTunnel) allow(ctx context.Context, newAddresses []netip.Addr) (err error) {
+	newAddressStrings := make([]string, len(newAddresses))
+	for i, address := range newAddresses {
+		newAddressStrings[i] = address.String()
+	}
+	s.logger.Debug("routing outside the VPN: " + strings.Join(newAddressStrings, ", "))
+
+	currentAddresses := s.addresses()
+	addresses := append(slices.Clone(currentAddresses), newAddresses...)
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i].Less(addresses[j])
+	})
+
+	// Allow the traffic in the firewall before routing it
+	// through the default route.
+	err = s.firewall.SetOutboundDomainIPs(ctx, addresses)
+	if err != nil {
+		return fmt.Errorf("setting firewall: %w", err)
+	}
+	err = s.routing.SetOutboundDomainRoutes(addresses)
+	if err != nil {
+		rollbackErr := s.firewall.SetOutboundDomainIPs(ctx, currentAddresses)
+		if rollbackErr != nil {
+			s.logger.Error("restoring firewall outbound domain IP addresses: " +
+				rollbackErr.Error())
+		}
+		return fmt.Errorf("setting routes: %w", err)
+	}
+	return nil
+}
+
+// Run removes the expired IP addresses periodically,
+// until the context is canceled.
+func (s *SplitTunnel) Run(ctx context.Context, done chan<- struct{}) {
+	defer close(done)
+
+	const period = 30 * time.Second
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := s.removeExpired(ctx)
+		if err != nil {
+			s.logger.Error("removing expired outbound domain IP addresses: " + err.Error())
+		}
+	}
+}
+
+func (s *SplitTunnel) removeExpired(ctx context.Context) (err error) {
+	now := s.timeNow()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	removed := false
+	for address, expiry := range s.addressToExpiry {
+		if now.Before(expiry) {
+			continue
+		}
+		delete(s.addressToExpiry, address)
+		removed = true
+	}
+
+	if !removed {
+		return nil
+	}
+
+	// Route the traffic back through the VPN before
+	// removing the firewall rules.
+	addresses := s.addresses()
+	err = s.routing.SetOutboundDomainRoutes(addresses)
+	if err != nil {
+		return fmt.Errorf("setting routes: %w", err)
+	}
+	err = s.firewall.SetOutboundDomainIPs(ctx, addresses)
+	if err != nil {
+		return fmt.Errorf("setting firewall: %w", err)
+	}
+	return nil
+}
+
+// addresses returns the sorted IP addresses currently known.
+// It must be called with the mutex locked.
+func (s *SplitTunnel) addresses() (addresses []netip.Addr) {
+	addresses = make([]netip.Addr, 0, len(s.addressToExpiry))
+	for address := range s.addressToExpiry {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i].Less(addresses[j])
+	})
+	return addresses
+}
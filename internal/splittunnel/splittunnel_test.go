@@ -0,0 +1,136 @@
+package splittunnel
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SplitTunnel_Match(t *testing.T) {
+	t.Parallel()
+
+	splitTunnel := New([]string{"*.Example.com", "mirror.example.org."}, nil, nil, nil)
+
+	testCases := map[string]bool{
+		"example.com.":             false,
+		"cdn.example.com.":         true,
+		"a.b.example.com.":         true,
+		"CDN.EXAMPLE.COM":          true,
+		"notexample.com.":          false,
+		"mirror.example.org.":      true,
+		"sub.mirror.example.org.":  false,
+		"mirror.example.org.evil.": false,
+	}
+
+	for name, expected := range testCases {
+		name, expected := name, expected
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, expected, splitTunnel.Match(name))
+		})
+	}
+}
+
+func Test_SplitTunnel_Add_removeExpired(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	routing := NewMockRouting(ctrl)
+	firewall := NewMockFirewall(ctrl)
+	logger := NewMockLogger(ctrl)
+
+	splitTunnel := New(nil, routing, firewall, logger)
+	now := time.Unix(1000, 0)
+	splitTunnel.timeNow = func() time.Time { return now }
+
+	addressA := netip.MustParseAddr("1.1.1.1")
+	addressB := netip.MustParseAddr("2.2.2.2")
+
+	logger.EXPECT().Debug("routing outside the VPN: 2.2.2.2")
+	gomock.InOrder(
+		firewall.EXPECT().SetOutboundDomainIPs(ctx, []netip.Addr{addressB}),
+		routing.EXPECT().SetOutboundDomainRoutes([]netip.Addr{addressB}),
+	)
+	err := splitTunnel.Add(ctx, []netip.Addr{addressB}, time.Hour)
+	require.NoError(t, err)
+
+	// Known address with a smaller TTL does not change anything.
+	err = splitTunnel.Add(ctx, []netip.Addr{addressB}, time.Second)
+	require.NoError(t, err)
+
+	logger.EXPECT().Debug("routing outside the VPN: 1.1.1.1")
+	gomock.InOrder(
+		firewall.EXPECT().SetOutboundDomainIPs(ctx, []netip.Addr{addressA, addressB}),
+		routing.EXPECT().SetOutboundDomainRoutes([]netip.Addr{addressA, addressB}),
+	)
+	err = splitTunnel.Add(ctx, []netip.Addr{addressA}, time.Second)
+	require.NoError(t, err)
+
+	// Nothing expired yet since the minimum TTL is one minute.
+	now = now.Add(30 * time.Second)
+	err = splitTunnel.removeExpired(ctx)
+	require.NoError(t, err)
+
+	now = now.Add(30 * time.Second)
+	gomock.InOrder(
+		routing.EXPECT().SetOutboundDomainRoutes([]netip.Addr{addressB}),
+		firewall.EXPECT().SetOutboundDomainIPs(ctx, []netip.Addr{addressB}),
+	)
+	err = splitTunnel.removeExpired(ctx)
+	require.NoError(t, err)
+}
+
+func Test_SplitTunnel_Add_errors(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	routing := NewMockRouting(ctrl)
+	firewall := NewMockFirewall(ctrl)
+	logger := NewMockLogger(ctrl)
+
+	splitTunnel := New(nil, routing, firewall, logger)
+	now := time.Unix(1000, 0)
+	splitTunnel.timeNow = func() time.Time { return now }
+
+	addressA := netip.MustParseAddr("1.1.1.1")
+	addressB := netip.MustParseAddr("2.2.2.2")
+	errTest := errors.New("test error")
+
+	logger.EXPECT().Debug("routing outside the VPN: 2.2.2.2")
+	firewall.EXPECT().SetOutboundDomainIPs(ctx, []netip.Addr{addressB}).Return(errTest)
+	err := splitTunnel.Add(ctx, []netip.Addr{addressB}, time.Hour)
+	require.ErrorIs(t, err, errTest)
+	assert.EqualError(t, err, "setting firewall: test error")
+	assert.Empty(t, splitTunnel.addressToExpiry)
+
+	logger.EXPECT().Debug("routing outside the VPN: 2.2.2.2")
+	gomock.InOrder(
+		firewall.EXPECT().SetOutboundDomainIPs(ctx, []netip.Addr{addressB}),
+		routing.EXPECT().SetOutboundDomainRoutes([]netip.Addr{addressB}),
+	)
+	err = splitTunnel.Add(ctx, []netip.Addr{addressB}, time.Hour)
+	require.NoError(t, err)
+
+	// Routing error restores the firewall to the known addresses.
+	logger.EXPECT().Debug("routing outside the VPN: 1.1.1.1")
+	gomock.InOrder(
+		firewall.EXPECT().SetOutboundDomainIPs(ctx, []netip.Addr{addressA, addressB}),
+		routing.EXPECT().SetOutboundDomainRoutes([]netip.Addr{addressA, addressB}).Return(errTest),
+		firewall.EXPECT().SetOutboundDomainIPs(ctx, []netip.Addr{addressB}),
+	)
+	err = splitTunnel.Add(ctx, []netip.Addr{addressA}, time.Hour)
+	require.ErrorIs(t, err, errTest)
+	assert.EqualError(t, err, "setting routes: test error")
+	expected := map[netip.Addr]time.Time{addressB: now.Add(time.Hour)}
+	assert.Equal(t, expected, splitTunnel.addressToExpiry)
+}
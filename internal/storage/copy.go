@@ -9,6 +9,10 @@ import (
 func copyServer(server models.Server) (serverCopy models.Server) {
 	serverCopy = server
 	serverCopy.IPs = copyIPs(server.IPs)
+	if server.Load != nil {
+		load := *server.Load
+		serverCopy.Load = &load
+	}
 	return serverCopy
 }
 
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"fmt"
+	"net/netip"
 	"time"
 
 	"github.com/qdm12/gluetun/internal/constants/providers"
@@ -33,6 +34,30 @@ func (s *Storage) SetServers(provider string, servers []models.Server) (err erro
 	return nil
 }
 
+// SetLoads sets the load of each server of the given provider
+// having an IP address present in the ipToLoad map given.
+// Server loads are only set in memory and are not saved to file,
+// since they change often.
+func (s *Storage) SetLoads(provider string, ipToLoad map[netip.Addr]uint8) {
+	if provider == providers.Custom {
+		return
+	}
+
+	s.mergedMutex.Lock()
+	defer s.mergedMutex.Unlock()
+
+	serversObject := s.getMergedServersObject(provider)
+	for i, server := range serversObject.Servers {
+		for _, ip := range server.IPs {
+			load, ok := ipToLoad[ip]
+			if ok {
+				serversObject.Servers[i].Load = &load
+				break
+			}
+		}
+	}
+}
+
 // GetServersCount returns the number of servers for the provider given.
 func (s *Storage) GetServersCount(provider string) (count int) {
 	if provider == providers.Custom {
@@ -0,0 +1,32 @@
+package tunnel
+
+import (
+	"context"
+	"net/netip"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider"
+)
+
+type Providers interface {
+	Get(providerName string) provider.Provider
+}
+
+type Firewall interface {
+	SetTunnelConnection(ctx context.Context, connection models.Connection,
+		intf string, sourceSubnets []netip.Prefix) (err error)
+	SetAllowedPort(ctx context.Context, port uint16, intf string) (err error)
+}
+
+type Routing interface {
+	SetTunnelRules(table int, sources []netip.Prefix) (err error)
+}
+
+type Logger interface {
+	Debug(s string)
+	Debugf(format string, args ...interface{})
+	Info(s string)
+	Warn(s string)
+	Error(s string)
+	Errorf(format string, args ...interface{})
+}
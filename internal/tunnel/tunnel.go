@@ -0,0 +1,202 @@
+// Package tunnel runs additional Wireguard tunnels alongside the main
+// VPN connection, with policy rules selecting the traffic going through
+// each tunnel.
+package tunnel
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/httpproxy"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider/utils"
+	"github.com/qdm12/gluetun/internal/wireguard"
+)
+
+// Tunnel runs an additional Wireguard tunnel, using its own routing
+// table selected by policy rules for its source subnets and addresses.
+type Tunnel struct {
+	settings      settings.VPNTunnel
+	table         int
+	ipv6Supported bool
+	providers     Providers
+	netLinker     wireguard.NetLinker
+	firewall      Firewall
+	routing       Routing
+	logger        Logger
+	backoffTime   time.Duration
+}
+
+// firstTable is the routing table of the first tunnel. It is right after
+// the main Wireguard routing table, and each other tunnel uses the next
+// routing table.
+const firstTable = 51821
+
+// New creates a tunnel for the tunnel settings at the index given
+// in the list of tunnels settings.
+func New(settings settings.VPNTunnel, index int, ipv6Supported bool,
+	providers Providers, netLinker wireguard.NetLinker, firewall Firewall,
+	routing Routing, logger Logger) *Tunnel {
+	return &Tunnel{
+		settings:      settings,
+		table:         firstTable + index,
+		ipv6Supported: ipv6Supported,
+		providers:     providers,
+		netLinker:     netLinker,
+		firewall:      firewall,
+		routing:       routing,
+		logger:        logger,
+		backoffTime:   defaultBackoffTime,
+	}
+}
+
+const (
+	defaultBackoffTime = 15 * time.Second
+	maxBackoffTime     = 5 * time.Minute
+)
+
+// Run runs the tunnel until the context is canceled, and restarts
+// it on a different server with a backoff time if it fails.
+func (t *Tunnel) Run(ctx context.Context, done chan<- struct{}) {
+	defer close(done)
+
+	for {
+		err := t.runOnce(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		t.logger.Error(err.Error())
+		t.logger.Info("retrying in " + t.backoffTime.String())
+
+		timer := time.NewTimer(t.backoffTime)
+		t.backoffTime = min(2*t.backoffTime, maxBackoffTime)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+func (t *Tunnel) runOnce(ctx context.Context) (err error) {
+	providerConf := t.providers.Get(t.settings.Provider.Name)
+	connection, err := providerConf.GetConnection(t.settings.Provider.ServerSelection,
+		utils.ConnectionOptions{}, t.ipv6Supported)
+	if err != nil {
+		return fmt.Errorf("finding a VPN server: %w", err)
+	}
+
+	wireguardSettings := utils.BuildWireguardSettings(connection, t.settings.Wireguard, t.ipv6Supported)
+	wireguardSettings.RoutingTable = t.table
+	wireguardSettings.PolicyRoutingOnly = true
+
+	wireguarder, err := wireguard.New(wireguardSettings, t.netLinker, t.logger)
+	if err != nil {
+		return fmt.Errorf("creating Wireguard: %w", err)
+	}
+
+	intf := t.settings.Wireguard.Interface
+	err = t.firewall.SetTunnelConnection(ctx, connection, intf, t.settings.SourceSubnets)
+	if err != nil {
+		return fmt.Errorf("setting firewall: %w", err)
+	}
+	defer func() {
+		// use a fresh context since ctx may be canceled
+		err := t.firewall.SetTunnelConnection(context.Background(), models.Connection{}, intf, nil)
+		if err != nil {
+			t.logger.Error("removing tunnel firewall rules: " + err.Error())
+		}
+	}()
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	defer runCancel()
+	waitError := make(chan error)
+	ready := make(chan struct{})
+	go wireguarder.Run(runCtx, waitError, ready)
+
+	select {
+	case err = <-waitError:
+		return fmt.Errorf("running Wireguard: %w", err)
+	case <-ready:
+	}
+
+	err = t.setPolicies(ctx, wireguardSettings.Addresses)
+	if err == nil {
+		t.logger.Info("tunnel " + t.settings.Name + " is up through " + connection.ServerName)
+		t.backoffTime = defaultBackoffTime
+		err = <-waitError
+		err = fmt.Errorf("running Wireguard: %w", err)
+	} else {
+		runCancel()
+		<-waitError
+	}
+
+	rulesErr := t.routing.SetTunnelRules(t.table, nil)
+	if rulesErr != nil {
+		t.logger.Error("removing tunnel policy rules: " + rulesErr.Error())
+	}
+
+	return err
+}
+
+// setPolicies allows the input ports through the tunnel interface, and
+// routes the traffic from the source subnets and tunnel addresses through
+// the tunnel routing table.
+func (t *Tunnel) setPolicies(ctx context.Context, addresses []netip.Prefix) (err error) {
+	// Note the allowed input ports are not removed when the tunnel stops,
+	// since the rules are for the tunnel interface only, and the same port
+	// may also be allowed for the main VPN interface.
+	for _, port := range t.settings.InputPorts {
+		err = t.firewall.SetAllowedPort(ctx, port, t.settings.Wireguard.Interface)
+		if err != nil {
+			return fmt.Errorf("allowing input port %d: %w", port, err)
+		}
+	}
+
+	err = t.routing.SetTunnelRules(t.table, makeSources(addresses, t.settings.SourceSubnets))
+	if err != nil {
+		return fmt.Errorf("setting policy rules: %w", err)
+	}
+	return nil
+}
+
+// makeSources returns the sources to route through the tunnel, which are
+// the tunnel addresses, for replies and traffic bound to these addresses,
+// and the source subnets given.
+func makeSources(addresses, sourceSubnets []netip.Prefix) (sources []netip.Prefix) {
+	sources = make([]netip.Prefix, 0, len(addresses)+len(sourceSubnets))
+	for _, address := range addresses {
+		sources = append(sources, netip.PrefixFrom(address.Addr(), address.Addr().BitLen()))
+	}
+	return append(sources, sourceSubnets...)
+}
+
+// HTTPProxyUsers returns the HTTP proxy users of the tunnels given, each
+// dialing from the first IPv4 address of its tunnel, or from its first
+// address if it has no IPv4 address.
+func HTTPProxyUsers(tunnels []settings.VPNTunnel) (users []httpproxy.TunnelUser) {
+	for _, tunnel := range tunnels {
+		if *tunnel.HTTPProxyUser == "" || len(tunnel.Wireguard.Addresses) == 0 {
+			continue
+		}
+
+		localAddress := tunnel.Wireguard.Addresses[0].Addr()
+		for _, address := range tunnel.Wireguard.Addresses {
+			if address.Addr().Is4() {
+				localAddress = address.Addr()
+				break
+			}
+		}
+
+		users = append(users, httpproxy.TunnelUser{
+			Username:     *tunnel.HTTPProxyUser,
+			Password:     *tunnel.HTTPProxyPassword,
+			LocalAddress: localAddress,
+		})
+	}
+	return users
+}
@@ -0,0 +1,81 @@
+package tunnel
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/httpproxy"
+	"github.com/stretchr/testify/assert"
+)
+
+func ptrTo[T any](value T) *T { return &value }
+
+func Test_makeSources(t *testing.T) {
+	t.Parallel()
+
+	addresses := []netip.Prefix{
+		netip.MustParsePrefix("10.2.0.2/24"),
+		netip.MustParsePrefix("fd00::2/64"),
+	}
+	sourceSubnets := []netip.Prefix{
+		netip.MustParsePrefix("172.20.0.0/16"),
+	}
+
+	sources := makeSources(addresses, sourceSubnets)
+
+	expected := []netip.Prefix{
+		netip.MustParsePrefix("10.2.0.2/32"),
+		netip.MustParsePrefix("fd00::2/128"),
+		netip.MustParsePrefix("172.20.0.0/16"),
+	}
+	assert.Equal(t, expected, sources)
+}
+
+func Test_HTTPProxyUsers(t *testing.T) {
+	t.Parallel()
+
+	tunnels := []settings.VPNTunnel{
+		{
+			Name:          "noproxy",
+			HTTPProxyUser: ptrTo(""),
+			Wireguard: settings.Wireguard{
+				Addresses: []netip.Prefix{netip.MustParsePrefix("10.1.0.2/32")},
+			},
+		},
+		{
+			Name:              "germany",
+			HTTPProxyUser:     ptrTo("de"),
+			HTTPProxyPassword: ptrTo("password"),
+			Wireguard: settings.Wireguard{
+				Addresses: []netip.Prefix{
+					netip.MustParsePrefix("fd00::2/128"),
+					netip.MustParsePrefix("10.2.0.2/32"),
+				},
+			},
+		},
+		{
+			Name:              "ipv6",
+			HTTPProxyUser:     ptrTo("v6"),
+			HTTPProxyPassword: ptrTo(""),
+			Wireguard: settings.Wireguard{
+				Addresses: []netip.Prefix{netip.MustParsePrefix("fd00::3/128")},
+			},
+		},
+	}
+
+	users := HTTPProxyUsers(tunnels)
+
+	expected := []httpproxy.TunnelUser{
+		{
+			Username:     "de",
+			Password:     "password",
+			LocalAddress: netip.MustParseAddr("10.2.0.2"),
+		},
+		{
+			Username:     "v6",
+			LocalAddress: netip.MustParseAddr("fd00::3"),
+		},
+	}
+	assert.Equal(t, expected, users)
+}
@@ -2,6 +2,7 @@ package updater
 
 import (
 	"context"
+	"net/netip"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/models"
@@ -16,6 +17,7 @@ type Storage interface {
 	SetServers(provider string, servers []models.Server) (err error)
 	GetServersCount(provider string) (count int)
 	ServersAreEqual(provider string, servers []models.Server) (equal bool)
+	SetLoads(provider string, ipToLoad map[netip.Addr]uint8)
 	// Extra methods to match the provider.New storage interface
 	FilterServers(provider string, selection settings.ServerSelection) (filtered []models.Server, err error)
 }
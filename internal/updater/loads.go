@@ -0,0 +1,44 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+)
+
+type LoadFetcher interface {
+	FetchLoads(ctx context.Context) (ipToLoad map[netip.Addr]uint8, err error)
+}
+
+// UpdateLoads updates the server loads for each of the providers
+// given supporting it, and ignores the other providers.
+func (u *Updater) UpdateLoads(ctx context.Context, providers []string) (err error) {
+	for _, providerName := range providers {
+		fetcher, ok := u.providers.Get(providerName).(LoadFetcher)
+		if !ok {
+			continue
+		}
+
+		ipToLoad, err := fetcher.FetchLoads(ctx)
+		if err != nil {
+			err = fmt.Errorf("fetching %s server loads: %w", providerName, err)
+			// return the only error for the single provider.
+			if len(providers) == 1 {
+				return err
+			}
+
+			// stop updating the next providers if context is canceled.
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+
+			// Log the error and continue updating the next provider.
+			u.logger.Error(err.Error())
+			continue
+		}
+
+		u.storage.SetLoads(providerName, ipToLoad)
+	}
+
+	return nil
+}
@@ -14,13 +14,15 @@ import (
 
 type Updater interface {
 	UpdateServers(ctx context.Context, providers []string, minRatio float64) (err error)
+	UpdateLoads(ctx context.Context, providers []string) (err error)
 }
 
 type Loop struct {
 	state state
 	// Objects
-	updater Updater
-	logger  Logger
+	updater        Updater
+	eventPublisher EventPublisher
+	logger         Logger
 	// Internal channels and locks
 	loopLock     sync.Mutex
 	start        chan struct{}
@@ -36,6 +38,10 @@ type Loop struct {
 
 const defaultBackoffTime = 5 * time.Second
 
+type EventPublisher interface {
+	Publish(eventType string, data any)
+}
+
 type Logger interface {
 	Info(s string)
 	Warn(s string)
@@ -43,22 +49,24 @@ type Logger interface {
 }
 
 func NewLoop(settings settings.Updater, providers updater.Providers,
-	storage updater.Storage, client *http.Client, logger Logger) *Loop {
+	storage updater.Storage, client *http.Client,
+	eventPublisher EventPublisher, logger Logger) *Loop {
 	return &Loop{
 		state: state{
 			status:   constants.Stopped,
 			settings: settings,
 		},
-		updater:      updater.New(client, storage, providers, logger),
-		logger:       logger,
-		start:        make(chan struct{}),
-		running:      make(chan models.LoopStatus),
-		stop:         make(chan struct{}),
-		stopped:      make(chan struct{}),
-		updateTicker: make(chan struct{}),
-		timeNow:      time.Now,
-		timeSince:    time.Since,
-		backoffTime:  defaultBackoffTime,
+		updater:        updater.New(client, storage, providers, logger),
+		eventPublisher: eventPublisher,
+		logger:         logger,
+		start:          make(chan struct{}),
+		running:        make(chan models.LoopStatus),
+		stop:           make(chan struct{}),
+		stopped:        make(chan struct{}),
+		updateTicker:   make(chan struct{}),
+		timeNow:        time.Now,
+		timeSince:      time.Since,
+		backoffTime:    defaultBackoffTime,
 	}
 }
 
@@ -104,7 +112,7 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 				}
 				return
 			}
-			l.state.setStatusWithLock(constants.Completed)
+			l.setStatusWithLock(constants.Completed)
 		}()
 
 		if !crashed {
@@ -112,7 +120,7 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 			crashed = false
 		} else {
 			l.backoffTime = defaultBackoffTime
-			l.state.setStatusWithLock(constants.Running)
+			l.setStatusWithLock(constants.Running)
 		}
 
 		stayHere := true
@@ -135,7 +143,7 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 				l.stopped <- struct{}{}
 			case err := <-errorCh:
 				runWg.Wait()
-				l.state.setStatusWithLock(constants.Crashed)
+				l.setStatusWithLock(constants.Crashed)
 				l.logAndWait(ctx, err)
 				crashed = true
 				stayHere = false
@@ -186,3 +194,35 @@ func (l *Loop) RunRestartTicker(ctx context.Context, done chan<- struct{}) {
 		}
 	}
 }
+
+// RunLoadTicker periodically updates the server loads until the context
+// is canceled. The load period is read from the settings at each
+// iteration, such that settings changes apply from the next iteration.
+func (l *Loop) RunLoadTicker(ctx context.Context, done chan<- struct{}) {
+	defer close(done)
+	const disabledCheckPeriod = time.Minute
+	for {
+		period := *l.GetSettings().LoadPeriod
+		if period == 0 {
+			period = disabledCheckPeriod
+		}
+
+		timer := time.NewTimer(period)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		settings := l.GetSettings()
+		if *settings.LoadPeriod == 0 {
+			continue
+		}
+
+		err := l.updater.UpdateLoads(ctx, settings.Providers)
+		if err != nil && ctx.Err() == nil {
+			l.logger.Warn("updating server loads: " + err.Error())
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/events"
 	"github.com/qdm12/gluetun/internal/models"
 )
 
@@ -19,10 +20,20 @@ type state struct {
 	periodMu sync.RWMutex
 }
 
-func (s *state) setStatusWithLock(status models.LoopStatus) {
-	s.statusMu.Lock()
-	defer s.statusMu.Unlock()
-	s.status = status
+func (l *Loop) setStatusWithLock(status models.LoopStatus) {
+	l.state.statusMu.Lock()
+	defer l.state.statusMu.Unlock()
+	l.setStatus(status)
+}
+
+// setStatus sets the status and publishes a status event
+// if it changed. It must be called with the status mutex locked.
+func (l *Loop) setStatus(status models.LoopStatus) {
+	if l.state.status == status {
+		return
+	}
+	l.state.status = status
+	l.eventPublisher.Publish(events.UpdaterStatus, events.Status{Status: status})
 }
 
 func (l *Loop) GetStatus() (status models.LoopStatus) {
@@ -46,7 +57,7 @@ func (l *Loop) SetStatus(ctx context.Context, status models.LoopStatus) (outcome
 		}
 		l.loopLock.Lock()
 		defer l.loopLock.Unlock()
-		l.state.status = constants.Starting
+		l.setStatus(constants.Starting)
 		l.state.statusMu.Unlock()
 		l.start <- struct{}{}
 
@@ -56,7 +67,7 @@ func (l *Loop) SetStatus(ctx context.Context, status models.LoopStatus) (outcome
 		case newStatus = <-l.running:
 		}
 		l.state.statusMu.Lock()
-		l.state.status = newStatus
+		l.setStatus(newStatus)
 		return newStatus.String(), nil
 	case constants.Stopped:
 		switch existingStatus {
@@ -65,7 +76,7 @@ func (l *Loop) SetStatus(ctx context.Context, status models.LoopStatus) (outcome
 		}
 		l.loopLock.Lock()
 		defer l.loopLock.Unlock()
-		l.state.status = constants.Stopping
+		l.setStatus(constants.Stopping)
 		l.state.statusMu.Unlock()
 		l.stop <- struct{}{}
 
@@ -76,7 +87,7 @@ func (l *Loop) SetStatus(ctx context.Context, status models.LoopStatus) (outcome
 			newStatus = constants.Stopped
 		}
 		l.state.statusMu.Lock()
-		l.state.status = newStatus
+		l.setStatus(newStatus)
 		return status.String(), nil
 	default:
 		return "", fmt.Errorf("%w: %s: it can only be one of: %s, %s",
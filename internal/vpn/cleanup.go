@@ -3,9 +3,13 @@ package vpn
 import (
 	"context"
 	"errors"
+
+	"github.com/qdm12/gluetun/internal/models"
 )
 
 func (l *Loop) cleanup() {
+	l.setConnection(models.Connection{}, "")
+
 	for _, vpnPort := range l.vpnInputPorts {
 		err := l.fw.RemoveAllowedPort(context.Background(), vpnPort)
 		if err != nil {
@@ -0,0 +1,74 @@
+package vpn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// GetConnection returns the VPN connection currently in use,
+// and false if there is no VPN connection in use.
+func (l *Loop) GetConnection() (connection models.Connection, ok bool) {
+	l.connectionMu.RLock()
+	defer l.connectionMu.RUnlock()
+	return l.connection, l.connection.IP.IsValid()
+}
+
+// GetInterface returns the network interface name of the VPN
+// connection currently in use, and the empty string if there
+// is no VPN connection in use.
+func (l *Loop) GetInterface() (vpnInterface string) {
+	l.connectionMu.RLock()
+	defer l.connectionMu.RUnlock()
+	return l.vpnInterface
+}
+
+func (l *Loop) setConnection(connection models.Connection, vpnInterface string) {
+	l.connectionMu.Lock()
+	defer l.connectionMu.Unlock()
+	l.connection = connection
+	l.vpnInterface = vpnInterface
+	l.connectedSince = time.Time{}
+	if connection.IP.IsValid() {
+		l.connectedSince = l.timeNow()
+	}
+}
+
+// getConnectedSince returns the time at which the current
+// VPN connection was established, or the zero time if there
+// is no VPN connection in use.
+func (l *Loop) getConnectedSince() (connectedSince time.Time) {
+	l.connectionMu.RLock()
+	defer l.connectionMu.RUnlock()
+	return l.connectedSince
+}
+
+var ErrNoConnection = errors.New("no VPN connection in use")
+
+// RotateConnection restarts the VPN, picking a server from the
+// filtered servers other than the server currently in use.
+// If no other server is available, the same server may be picked.
+func (l *Loop) RotateConnection(ctx context.Context) (outcome string, err error) {
+	l.connectionMu.Lock()
+	if !l.connection.IP.IsValid() {
+		l.connectionMu.Unlock()
+		return "", fmt.Errorf("%w", ErrNoConnection)
+	}
+	l.rotationExclusion = []models.Connection{l.connection}
+	l.connectionMu.Unlock()
+
+	return l.Restart(ctx)
+}
+
+// popRotationExclusion returns the connections to exclude when
+// picking the next connection, and clears them.
+func (l *Loop) popRotationExclusion() (excluded []models.Connection) {
+	l.connectionMu.Lock()
+	defer l.connectionMu.Unlock()
+	excluded = l.rotationExclusion
+	l.rotationExclusion = nil
+	return excluded
+}
@@ -0,0 +1,159 @@
+package vpn
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// GetEscalation returns the current state of the
+// unhealthy VPN escalation ladder.
+func (l *Loop) GetEscalation() (escalation models.Escalation) {
+	l.escalationMu.RLock()
+	defer l.escalationMu.RUnlock()
+	return l.escalation
+}
+
+// EscalateUnhealthy takes the next step of the escalation ladder since
+// the VPN is unhealthy: restart the same server, switch to another server,
+// switch to a fallback location and finally stop restarting the VPN, in
+// which case terminal is returned as true. If the escalation is disabled,
+// it only restarts the VPN.
+func (l *Loop) EscalateUnhealthy(ctx context.Context) (terminal bool, err error) {
+	escalationSettings := l.state.GetSettings().Escalation
+	if !*escalationSettings.Enabled {
+		_, err = l.Restart(ctx)
+		return false, err
+	}
+
+	connection, connected := l.GetConnection()
+
+	l.escalationMu.Lock()
+	previous := l.escalation
+	next := nextEscalation(previous, escalationSettings, l.timeNow())
+	l.escalation = next
+	switch next.Step {
+	case constants.EscalationSameServer:
+		l.logger.Warn(fmt.Sprintf("unhealthy VPN escalation: restarting the same server (attempt %d of %d)",
+			next.Attempt, *escalationSettings.SameServerRestarts))
+		if connected {
+			l.escalationPinnedIPs = []netip.Addr{connection.IP}
+		}
+	case constants.EscalationSwitchServer:
+		l.logger.Warn(fmt.Sprintf("unhealthy VPN escalation: switching to another server (attempt %d of %d)",
+			next.Attempt, *escalationSettings.ServerSwitches))
+		if connected {
+			l.connectionMu.Lock()
+			l.rotationExclusion = []models.Connection{connection}
+			l.connectionMu.Unlock()
+		}
+	case constants.EscalationSwitchLocation:
+		location := escalationSettings.Locations[next.Attempt-1]
+		l.logger.Warn(fmt.Sprintf("unhealthy VPN escalation: switching to location %s (%d of %d)",
+			location, next.Attempt, len(escalationSettings.Locations)))
+		l.escalationLocation = &location
+	case constants.EscalationTerminal:
+		if previous.Step != constants.EscalationTerminal {
+			l.logger.Error("unhealthy VPN escalation: all steps failed, " +
+				"the VPN is no longer restarted until it is healthy again")
+		}
+		l.escalationMu.Unlock()
+		return true, nil
+	}
+	l.escalationMu.Unlock()
+
+	_, err = l.Restart(ctx)
+	return false, err
+}
+
+// resetEscalation resets the escalation ladder once the VPN is healthy.
+func (l *Loop) resetEscalation() {
+	l.escalationMu.Lock()
+	defer l.escalationMu.Unlock()
+	if l.escalation.Step == constants.EscalationNone {
+		return
+	}
+	l.logger.Info("VPN is healthy again after escalation step " +
+		l.escalation.Step + ", resetting escalation")
+	l.escalation = models.Escalation{Step: constants.EscalationNone}
+	l.escalationPinnedIPs = nil
+	l.escalationLocation = nil
+}
+
+// popEscalationOverrides returns the server IP addresses to restrict the
+// next connection to and the location to use for the next connection,
+// as set by the last escalation step, and clears them.
+func (l *Loop) popEscalationOverrides() (pinnedIPs []netip.Addr,
+	location *settings.EscalationLocation) {
+	l.escalationMu.Lock()
+	defer l.escalationMu.Unlock()
+	pinnedIPs, location = l.escalationPinnedIPs, l.escalationLocation
+	l.escalationPinnedIPs, l.escalationLocation = nil, nil
+	return pinnedIPs, location
+}
+
+// nextEscalation returns the escalation state following the current
+// escalation state given, using the escalation settings given. Each step
+// is repeated as many times as configured, and steps configured to
+// zero times are skipped.
+func nextEscalation(current models.Escalation, escalationSettings settings.VPNEscalation,
+	now time.Time) (next models.Escalation) {
+	if current.Step == constants.EscalationTerminal {
+		return current
+	}
+
+	steps := []struct {
+		step     string
+		attempts uint
+	}{
+		{step: constants.EscalationSameServer, attempts: *escalationSettings.SameServerRestarts},
+		{step: constants.EscalationSwitchServer, attempts: *escalationSettings.ServerSwitches},
+		{step: constants.EscalationSwitchLocation, attempts: uint(len(escalationSettings.Locations))},
+	}
+
+	nextIndex := 0
+	for i, step := range steps {
+		if step.step != current.Step {
+			continue
+		}
+		if current.Attempt < step.attempts {
+			next = current
+			next.Attempt++
+			next.Location = escalationLocation(next, escalationSettings.Locations)
+			return next
+		}
+		nextIndex = i + 1
+		break
+	}
+
+	for _, step := range steps[nextIndex:] {
+		if step.attempts == 0 {
+			continue
+		}
+		next = models.Escalation{
+			Step:    step.step,
+			Attempt: 1,
+			Since:   now,
+		}
+		next.Location = escalationLocation(next, escalationSettings.Locations)
+		return next
+	}
+
+	return models.Escalation{
+		Step:  constants.EscalationTerminal,
+		Since: now,
+	}
+}
+
+func escalationLocation(escalation models.Escalation,
+	locations []settings.EscalationLocation) string {
+	if escalation.Step != constants.EscalationSwitchLocation {
+		return ""
+	}
+	return locations[escalation.Attempt-1].String()
+}
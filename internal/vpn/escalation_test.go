@@ -0,0 +1,145 @@
+package vpn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_nextEscalation(t *testing.T) {
+	t.Parallel()
+
+	since := time.Unix(5000, 0)
+	now := time.Unix(10000, 0)
+	escalationSettings := settings.VPNEscalation{
+		Enabled:            ptrTo(true),
+		SameServerRestarts: ptrTo(uint(1)),
+		ServerSwitches:     ptrTo(uint(2)),
+		Locations: []settings.EscalationLocation{
+			{Country: "Germany"},
+			{Country: "Netherlands", City: "Amsterdam"},
+		},
+	}
+
+	testCases := map[string]struct {
+		current            models.Escalation
+		escalationSettings settings.VPNEscalation
+		next               models.Escalation
+	}{
+		"none_to_same_server": {
+			current:            models.Escalation{Step: constants.EscalationNone},
+			escalationSettings: escalationSettings,
+			next: models.Escalation{
+				Step:    constants.EscalationSameServer,
+				Attempt: 1,
+				Since:   now,
+			},
+		},
+		"same_server_to_switch_server": {
+			current: models.Escalation{
+				Step:    constants.EscalationSameServer,
+				Attempt: 1,
+				Since:   since,
+			},
+			escalationSettings: escalationSettings,
+			next: models.Escalation{
+				Step:    constants.EscalationSwitchServer,
+				Attempt: 1,
+				Since:   now,
+			},
+		},
+		"switch_server_next_attempt": {
+			current: models.Escalation{
+				Step:    constants.EscalationSwitchServer,
+				Attempt: 1,
+				Since:   since,
+			},
+			escalationSettings: escalationSettings,
+			next: models.Escalation{
+				Step:    constants.EscalationSwitchServer,
+				Attempt: 2,
+				Since:   since,
+			},
+		},
+		"switch_server_to_switch_location": {
+			current: models.Escalation{
+				Step:    constants.EscalationSwitchServer,
+				Attempt: 2,
+				Since:   since,
+			},
+			escalationSettings: escalationSettings,
+			next: models.Escalation{
+				Step:     constants.EscalationSwitchLocation,
+				Attempt:  1,
+				Location: "Germany",
+				Since:    now,
+			},
+		},
+		"switch_location_next_location": {
+			current: models.Escalation{
+				Step:     constants.EscalationSwitchLocation,
+				Attempt:  1,
+				Location: "Germany",
+				Since:    since,
+			},
+			escalationSettings: escalationSettings,
+			next: models.Escalation{
+				Step:     constants.EscalationSwitchLocation,
+				Attempt:  2,
+				Location: "Netherlands/Amsterdam",
+				Since:    since,
+			},
+		},
+		"switch_location_to_terminal": {
+			current: models.Escalation{
+				Step:     constants.EscalationSwitchLocation,
+				Attempt:  2,
+				Location: "Netherlands/Amsterdam",
+				Since:    since,
+			},
+			escalationSettings: escalationSettings,
+			next: models.Escalation{
+				Step:  constants.EscalationTerminal,
+				Since: now,
+			},
+		},
+		"terminal_stays_terminal": {
+			current: models.Escalation{
+				Step:  constants.EscalationTerminal,
+				Since: since,
+			},
+			escalationSettings: escalationSettings,
+			next: models.Escalation{
+				Step:  constants.EscalationTerminal,
+				Since: since,
+			},
+		},
+		"skip_steps_configured_to_zero": {
+			current: models.Escalation{Step: constants.EscalationNone},
+			escalationSettings: settings.VPNEscalation{
+				Enabled:            ptrTo(true),
+				SameServerRestarts: ptrTo(uint(0)),
+				ServerSwitches:     ptrTo(uint(0)),
+			},
+			next: models.Escalation{
+				Step:  constants.EscalationTerminal,
+				Since: now,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			next := nextEscalation(testCase.current, testCase.escalationSettings, now)
+
+			assert.Equal(t, testCase.next, next)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package vpn
+
+import (
+	"net/netip"
+	"sort"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// GetFailureHistory returns the failure history of each VPN server
+// endpoint which failed, sorted from the most recent failure.
+func (l *Loop) GetFailureHistory() (failures []models.ServerFailure) {
+	l.failuresMu.RLock()
+	defer l.failuresMu.RUnlock()
+
+	failures = make([]models.ServerFailure, 0, len(l.failures))
+	for _, failure := range l.failures {
+		failures = append(failures, failure)
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].LastFailure.After(failures[j].LastFailure)
+	})
+	return failures
+}
+
+// RecordConnectionFailure records a failure for the VPN connection
+// currently in use, for example if the connection is unhealthy.
+// It is a no-op if there is no VPN connection in use.
+func (l *Loop) RecordConnectionFailure(err error) {
+	connection, ok := l.GetConnection()
+	if !ok {
+		return
+	}
+	l.recordFailure(connection, err)
+}
+
+func (l *Loop) recordFailure(connection models.Connection, err error) {
+	if err == nil {
+		return
+	}
+
+	l.failuresMu.Lock()
+	defer l.failuresMu.Unlock()
+
+	l.consecutiveFailures++
+	if !connection.IP.IsValid() {
+		return
+	}
+
+	failure := l.failures[connection.IP]
+	failure.ServerName = connection.ServerName
+	failure.Hostname = connection.Hostname
+	failure.IP = connection.IP
+	failure.Count++
+	failure.LastFailure = l.timeNow()
+	failure.LastError = err.Error()
+	l.failures[connection.IP] = failure
+}
+
+// failoverCandidateIPs returns the IP addresses of the next server to
+// try from the ordered filtered servers, skipping server endpoints
+// which failed within the failover cooldown. It returns nil if the
+// failover is disabled or no server can be found. If skipCurrent is
+// true, the walk starts after the current candidate server.
+func (l *Loop) failoverCandidateIPs(vpnSettings settings.VPN,
+	skipCurrent bool) (candidateIPs []netip.Addr) {
+	if !*vpnSettings.Failover.Enabled {
+		return nil
+	}
+
+	servers, err := l.storage.FilterServers(vpnSettings.Provider.Name,
+		vpnSettings.Provider.ServerSelection)
+	if err != nil || len(servers) == 0 {
+		return nil
+	}
+
+	l.failuresMu.Lock()
+	defer l.failuresMu.Unlock()
+
+	start := l.failoverCursor
+	if skipCurrent {
+		start++
+	}
+	cooldownStart := l.timeNow().Add(-*vpnSettings.Failover.Cooldown)
+	l.failoverCursor, candidateIPs = pickFailoverCandidate(servers, start,
+		l.failures, cooldownStart, l.ipv6Supported)
+	return candidateIPs
+}
+
+// pickFailoverCandidate walks the servers starting at the start index,
+// wrapping around, and returns the index of the first server having
+// endpoints which did not fail since cooldownStart, together with these
+// endpoints IP addresses. If all endpoints failed since cooldownStart,
+// it returns the server whose last failure is the oldest, together with
+// all its endpoints IP addresses.
+func pickFailoverCandidate(servers []models.Server, start int,
+	failures map[netip.Addr]models.ServerFailure, cooldownStart time.Time,
+	ipv6Supported bool) (index int, candidateIPs []netip.Addr) {
+	oldestFailureIndex := -1
+	var oldestFailure time.Time
+	for i := 0; i < len(servers); i++ {
+		index = (start + i) % len(servers)
+		serverIPs := usableIPs(servers[index].IPs, ipv6Supported)
+		if len(serverIPs) == 0 {
+			continue
+		}
+
+		var lastFailure time.Time
+		for _, ip := range serverIPs {
+			failure, failed := failures[ip]
+			if !failed || failure.LastFailure.Before(cooldownStart) {
+				candidateIPs = append(candidateIPs, ip)
+			} else if failure.LastFailure.After(lastFailure) {
+				lastFailure = failure.LastFailure
+			}
+		}
+		if len(candidateIPs) > 0 {
+			return index, candidateIPs
+		}
+
+		if oldestFailureIndex == -1 || lastFailure.Before(oldestFailure) {
+			oldestFailureIndex = index
+			oldestFailure = lastFailure
+		}
+	}
+
+	if oldestFailureIndex == -1 { // no usable IP address
+		return start % len(servers), nil
+	}
+	return oldestFailureIndex, usableIPs(servers[oldestFailureIndex].IPs, ipv6Supported)
+}
+
+func usableIPs(ips []netip.Addr, ipv6Supported bool) (usable []netip.Addr) {
+	usable = make([]netip.Addr, 0, len(ips))
+	for _, ip := range ips {
+		if !ipv6Supported && ip.Is6() {
+			continue
+		}
+		usable = append(usable, ip)
+	}
+	return usable
+}
@@ -0,0 +1,87 @@
+package vpn
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_pickFailoverCandidate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(10000, 0)
+	cooldownStart := now.Add(-time.Minute)
+	ipA := netip.AddrFrom4([4]byte{1, 1, 1, 1})
+	ipB := netip.AddrFrom4([4]byte{2, 2, 2, 2})
+	ipC := netip.AddrFrom4([4]byte{3, 3, 3, 3})
+	ipv6 := netip.IPv6Loopback()
+	servers := []models.Server{
+		{ServerName: "a", IPs: []netip.Addr{ipA}},
+		{ServerName: "b", IPs: []netip.Addr{ipB, ipv6}},
+		{ServerName: "c", IPs: []netip.Addr{ipC}},
+	}
+
+	testCases := map[string]struct {
+		start         int
+		failures      map[netip.Addr]models.ServerFailure
+		ipv6Supported bool
+		index         int
+		candidateIPs  []netip.Addr
+	}{
+		"no_failure": {
+			index:        0,
+			candidateIPs: []netip.Addr{ipA},
+		},
+		"start_wraps_around": {
+			start:        4,
+			index:        1,
+			candidateIPs: []netip.Addr{ipB},
+		},
+		"ipv6_supported": {
+			start:         1,
+			ipv6Supported: true,
+			index:         1,
+			candidateIPs:  []netip.Addr{ipB, ipv6},
+		},
+		"skip_failed_in_cooldown": {
+			failures: map[netip.Addr]models.ServerFailure{
+				ipA: {LastFailure: now},
+				ipB: {LastFailure: now},
+			},
+			index:        2,
+			candidateIPs: []netip.Addr{ipC},
+		},
+		"failure_before_cooldown": {
+			failures: map[netip.Addr]models.ServerFailure{
+				ipA: {LastFailure: cooldownStart.Add(-time.Second)},
+			},
+			index:        0,
+			candidateIPs: []netip.Addr{ipA},
+		},
+		"all_failed_pick_oldest": {
+			failures: map[netip.Addr]models.ServerFailure{
+				ipA: {LastFailure: now},
+				ipB: {LastFailure: now.Add(-time.Second)},
+				ipC: {LastFailure: now},
+			},
+			index:        1,
+			candidateIPs: []netip.Addr{ipB},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			index, candidateIPs := pickFailoverCandidate(servers, testCase.start,
+				testCase.failures, cooldownStart, testCase.ipv6Supported)
+
+			assert.Equal(t, testCase.index, index)
+			assert.Equal(t, testCase.candidateIPs, candidateIPs)
+		})
+	}
+}
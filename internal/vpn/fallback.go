@@ -0,0 +1,113 @@
+package vpn
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+)
+
+// vpnSettingsToUse returns the VPN settings to use for the next VPN
+// connection. It switches to the fallback VPN settings after too many
+// consecutive failures, and back to the primary VPN settings once the
+// fallback switch back duration has elapsed.
+func (l *Loop) vpnSettingsToUse(vpnSettings settings.VPN) (toUse settings.VPN) {
+	l.failuresMu.Lock()
+	defer l.failuresMu.Unlock()
+
+	now := l.timeNow()
+	fallbackInUse := !l.fallbackSince.IsZero()
+	fallbackToUse := useFallback(vpnSettings.Fallback, l.fallbackSince,
+		l.consecutiveFailures, now)
+	switch {
+	case fallbackToUse && !fallbackInUse:
+		l.logger.Warn(fmt.Sprintf("switching to fallback VPN provider %s after %d consecutive failures",
+			vpnSettings.Fallback.Provider.Name, l.consecutiveFailures))
+		l.fallbackSince = now
+		l.consecutiveFailures = 0
+	case !fallbackToUse && fallbackInUse:
+		l.logger.Info("switching back to primary VPN provider " + vpnSettings.Provider.Name)
+		l.fallbackSince = time.Time{}
+		l.consecutiveFailures = 0
+	}
+
+	if !fallbackToUse {
+		return vpnSettings
+	}
+	return vpnSettings.Fallback.Apply(vpnSettings)
+}
+
+// useFallback returns true if the fallback VPN settings should be used,
+// given the time since the fallback VPN settings are in use, which is
+// the zero time if they are not in use, and the number of consecutive
+// VPN connection failures.
+func useFallback(fallback settings.VPNFallback, fallbackSince time.Time,
+	consecutiveFailures uint, now time.Time) bool {
+	switch {
+	case !*fallback.Enabled:
+		return false
+	case fallbackSince.IsZero():
+		return consecutiveFailures >= *fallback.Failures
+	default:
+		return now.Sub(fallbackSince) < *fallback.SwitchBack
+	}
+}
+
+// activeVPNSettings returns the VPN settings currently in use,
+// which are the fallback VPN settings if the fallback is in use.
+func (l *Loop) activeVPNSettings() (vpnSettings settings.VPN) {
+	vpnSettings = l.state.GetSettings()
+	l.failuresMu.RLock()
+	defer l.failuresMu.RUnlock()
+	if l.fallbackSince.IsZero() || !*vpnSettings.Fallback.Enabled {
+		return vpnSettings
+	}
+	return vpnSettings.Fallback.Apply(vpnSettings)
+}
+
+// GetActiveSettings returns the VPN settings currently in use,
+// which are the fallback VPN settings if the fallback is in use,
+// as opposed to GetSettings which returns the primary VPN settings.
+func (l *Loop) GetActiveSettings() (vpnSettings settings.VPN) {
+	return l.activeVPNSettings()
+}
+
+// runFallbackSwitchBack checks every minute if the fallback VPN
+// settings are in use for longer than the switch back duration,
+// and restarts the VPN if it is running to switch back to the
+// primary VPN settings.
+func (l *Loop) runFallbackSwitchBack(ctx context.Context) {
+	const checkPeriod = time.Minute
+	ticker := time.NewTicker(checkPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		vpnSettings := l.state.GetSettings()
+		l.failuresMu.RLock()
+		fallbackSince := l.fallbackSince
+		l.failuresMu.RUnlock()
+		if fallbackSince.IsZero() ||
+			useFallback(vpnSettings.Fallback, fallbackSince, 0, l.timeNow()) {
+			continue
+		}
+
+		// Do not start a VPN stopped by the user, the next VPN
+		// start switches back to the primary VPN settings anyway.
+		if l.GetStatus() != constants.Running {
+			continue
+		}
+
+		_, err := l.Restart(ctx)
+		if err != nil {
+			l.logger.Error("restarting VPN to switch back to primary VPN provider: " + err.Error())
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package vpn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_useFallback(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(10000, 0)
+	enabled := settings.VPNFallback{
+		Enabled:    ptrTo(true),
+		Failures:   ptrTo(uint(3)),
+		SwitchBack: ptrTo(time.Hour),
+	}
+
+	testCases := map[string]struct {
+		fallback            settings.VPNFallback
+		fallbackSince       time.Time
+		consecutiveFailures uint
+		use                 bool
+	}{
+		"disabled": {
+			fallback: settings.VPNFallback{
+				Enabled:  ptrTo(false),
+				Failures: ptrTo(uint(1)),
+			},
+			consecutiveFailures: 5,
+		},
+		"not_enough_failures": {
+			fallback:            enabled,
+			consecutiveFailures: 2,
+		},
+		"enough_failures": {
+			fallback:            enabled,
+			consecutiveFailures: 3,
+			use:                 true,
+		},
+		"in_use_before_switch_back": {
+			fallback:      enabled,
+			fallbackSince: now.Add(-59 * time.Minute),
+			use:           true,
+		},
+		"in_use_after_switch_back": {
+			fallback:            enabled,
+			fallbackSince:       now.Add(-time.Hour),
+			consecutiveFailures: 10,
+		},
+		"disabled_while_in_use": {
+			fallback: settings.VPNFallback{
+				Enabled: ptrTo(false),
+			},
+			fallbackSince: now.Add(-time.Minute),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			use := useFallback(testCase.fallback, testCase.fallbackSince,
+				testCase.consecutiveFailures, now)
+
+			assert.Equal(t, testCase.use, use)
+		})
+	}
+}
@@ -17,6 +17,7 @@ type Firewall interface {
 	SetVPNConnection(ctx context.Context, connection models.Connection, interfaceName string) error
 	SetAllowedPort(ctx context.Context, port uint16, interfaceName string) error
 	RemoveAllowedPort(ctx context.Context, port uint16) error
+	SetProbeConnections(ctx context.Context, connections []models.Connection) error
 }
 
 type Routing interface {
@@ -25,6 +26,7 @@ type Routing interface {
 
 type PortForward interface {
 	UpdateWith(settings portforward.Settings) (err error)
+	GetPortsForwarded() (ports []uint16)
 }
 
 type OpenVPN interface {
@@ -38,7 +40,8 @@ type Providers interface {
 }
 
 type Provider interface {
-	GetConnection(selection settings.ServerSelection, ipv6Supported bool) (connection models.Connection, err error)
+	GetConnection(selection settings.ServerSelection, options utils.ConnectionOptions,
+		ipv6Supported bool) (connection models.Connection, err error)
 	OpenVPNConfig(connection models.Connection, settings settings.OpenVPN, ipv6Supported bool) (lines []string)
 	Name() string
 	FetchServers(ctx context.Context, minServers int) (
@@ -99,3 +102,7 @@ type CmdStarter interface {
 		stdoutLines, stderrLines <-chan string,
 		waitError <-chan error, startErr error)
 }
+
+type EventPublisher interface {
+	Publish(eventType string, data any)
+}
@@ -0,0 +1,154 @@
+package vpn
+
+import (
+	"encoding/json"
+	"errors"
+	"net/netip"
+	"os"
+	"path/filepath"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// lastServer is the last VPN server which reached a healthy state,
+// persisted to file to try it first when the program restarts.
+type lastServer struct {
+	Provider   string     `json:"provider"`
+	VPN        string     `json:"vpn"`
+	ServerName string     `json:"server_name,omitempty"`
+	Hostname   string     `json:"hostname,omitempty"`
+	IP         netip.Addr `json:"ip"`
+}
+
+// RecordConnectionHealthy resets the unhealthy escalation ladder,
+// records the VPN connection currently in use as the last known good
+// server, to try it first on the next start, and resets the count of
+// consecutive VPN connection failures. Apart from the escalation reset,
+// it is a no-op if there is no VPN connection in use.
+func (l *Loop) RecordConnectionHealthy() {
+	l.resetEscalation()
+
+	connection, ok := l.GetConnection()
+	if !ok {
+		return
+	}
+
+	l.failuresMu.Lock()
+	l.consecutiveFailures = 0
+	l.failuresMu.Unlock()
+
+	server := lastServer{
+		Provider:   l.activeVPNSettings().Provider.Name,
+		VPN:        connection.Type,
+		ServerName: connection.ServerName,
+		Hostname:   connection.Hostname,
+		IP:         connection.IP,
+	}
+	err := writeLastServer(l.lastServerPath, server)
+	if err != nil {
+		l.logger.Warn("saving last known good server: " + err.Error())
+	}
+}
+
+// lastServerCandidateIPs returns the IP address of the last known
+// good server if it still matches the settings given, and nil otherwise.
+func (l *Loop) lastServerCandidateIPs(vpnSettings settings.VPN) (
+	candidateIPs []netip.Addr) {
+	if vpnSettings.Provider.ServerSelection.TargetIP.IsValid() {
+		return nil
+	}
+
+	server, err := readLastServer(l.lastServerPath)
+	if err != nil {
+		l.logger.Warn("reading last known good server: " + err.Error())
+		return nil
+	} else if !server.IP.IsValid() ||
+		server.Provider != vpnSettings.Provider.Name ||
+		server.VPN != vpnSettings.Type {
+		return nil
+	}
+
+	servers, err := l.storage.FilterServers(vpnSettings.Provider.Name,
+		vpnSettings.Provider.ServerSelection)
+	if err != nil || !lastServerMatches(server, servers, l.ipv6Supported) {
+		return nil
+	}
+
+	l.logger.Info("trying last known good server " +
+		server.Hostname + " (" + server.IP.String() + ") first")
+	return []netip.Addr{server.IP}
+}
+
+// lastServerMatches returns true if one of the servers given has
+// the hostname and an IP address matching the last server given.
+func lastServerMatches(last lastServer, servers []models.Server,
+	ipv6Supported bool) (ok bool) {
+	if !ipv6Supported && last.IP.Is6() {
+		return false
+	}
+
+	for _, server := range servers {
+		if last.Hostname != "" && server.Hostname != "" &&
+			server.Hostname != last.Hostname && server.OvpnX509 != last.Hostname {
+			continue
+		}
+		for _, ip := range server.IPs {
+			if ip == last.IP {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func readLastServer(path string) (server lastServer, err error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return server, nil
+	} else if err != nil {
+		return server, err
+	}
+
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&server); err != nil {
+		_ = file.Close()
+		return server, err
+	}
+
+	return server, file.Close()
+}
+
+// writeLastServer writes the server to a temporary file in the same
+// directory and renames it over the file at the path given, so the
+// file is never left partially written.
+func writeLastServer(path string, server lastServer) (err error) {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(file.Name())
+		}
+	}()
+
+	encoder := json.NewEncoder(file)
+	if err := encoder.Encode(server); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+
+	const permissions = 0644
+	err = os.Chmod(file.Name(), permissions)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
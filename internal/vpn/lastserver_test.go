@@ -0,0 +1,109 @@
+package vpn
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/constants/providers"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_lastServerMatches(t *testing.T) {
+	t.Parallel()
+
+	ipA := netip.AddrFrom4([4]byte{1, 1, 1, 1})
+	ipB := netip.AddrFrom4([4]byte{2, 2, 2, 2})
+	ipv6 := netip.IPv6Loopback()
+	servers := []models.Server{
+		{Hostname: "a.com", IPs: []netip.Addr{ipA}},
+		{Hostname: "b.com", OvpnX509: "b.x509", IPs: []netip.Addr{ipB, ipv6}},
+	}
+
+	testCases := map[string]struct {
+		last          lastServer
+		ipv6Supported bool
+		ok            bool
+	}{
+		"hostname_and_ip_match": {
+			last: lastServer{Hostname: "a.com", IP: ipA},
+			ok:   true,
+		},
+		"x509_and_ip_match": {
+			last: lastServer{Hostname: "b.x509", IP: ipB},
+			ok:   true,
+		},
+		"no_hostname_ip_match": {
+			last: lastServer{IP: ipB},
+			ok:   true,
+		},
+		"hostname_mismatch": {
+			last: lastServer{Hostname: "a.com", IP: ipB},
+		},
+		"ip_not_found": {
+			last: lastServer{Hostname: "a.com", IP: netip.AddrFrom4([4]byte{9, 9, 9, 9})},
+		},
+		"ipv6_not_supported": {
+			last: lastServer{Hostname: "b.com", IP: ipv6},
+		},
+		"ipv6_supported": {
+			last:          lastServer{Hostname: "b.com", IP: ipv6},
+			ipv6Supported: true,
+			ok:            true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ok := lastServerMatches(testCase.last, servers, testCase.ipv6Supported)
+
+			assert.Equal(t, testCase.ok, ok)
+		})
+	}
+}
+
+func Test_readWriteLastServer(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+	path := filepath.Join(directory, "lastserver.json")
+
+	server, err := readLastServer(path)
+	require.NoError(t, err)
+	assert.Equal(t, lastServer{}, server)
+
+	written := lastServer{
+		Provider:   providers.Mullvad,
+		VPN:        vpn.Wireguard,
+		ServerName: "name",
+		Hostname:   "a.com",
+		IP:         netip.AddrFrom4([4]byte{1, 1, 1, 1}),
+	}
+	err = writeLastServer(path, written)
+	require.NoError(t, err)
+
+	server, err = readLastServer(path)
+	require.NoError(t, err)
+	assert.Equal(t, written, server)
+
+	written.IP = netip.AddrFrom4([4]byte{2, 2, 2, 2})
+	err = writeLastServer(path, written)
+	require.NoError(t, err)
+
+	server, err = readLastServer(path)
+	require.NoError(t, err)
+	assert.Equal(t, written, server)
+
+	// no temporary file is left behind
+	entries, err := os.ReadDir(directory)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "lastserver.json", entries[0].Name())
+}
@@ -2,10 +2,13 @@ package vpn
 
 import (
 	"net/http"
+	"net/netip"
+	"sync"
 	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/events"
 	"github.com/qdm12/gluetun/internal/loopstate"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/vpn/state"
@@ -39,9 +42,35 @@ type Loop struct {
 	stopped     chan<- struct{}
 	start       <-chan struct{}
 	running     chan<- models.LoopStatus
+	peerSwitch  chan chan<- error
 	userTrigger bool
+	// Current connection and connections to exclude on the next run
+	connection        models.Connection
+	vpnInterface      string
+	connectedSince    time.Time
+	rotationExclusion []models.Connection
+	connectionMu      sync.RWMutex
+	// Server endpoints failures and failover position
+	failures       map[netip.Addr]models.ServerFailure
+	failoverCursor int
+	failuresMu     sync.RWMutex
+	// Consecutive failures and time since the fallback VPN is in use
+	consecutiveFailures uint
+	fallbackSince       time.Time
+	// Unhealthy escalation state and overrides for the next connection
+	escalation          models.Escalation
+	escalationPinnedIPs []netip.Addr
+	escalationLocation  *settings.EscalationLocation
+	escalationMu        sync.RWMutex
+	// Last known good server persisted to file
+	lastServerPath  string
+	lastServerTried bool
+	// Settings and options of the last failed peer switch, only
+	// accessed by the run loop goroutine.
+	failedSwitch *nextConnection
 	// Internal constant values
 	backoffTime time.Duration
+	timeNow     func() time.Time
 }
 
 const (
@@ -52,7 +81,7 @@ func NewLoop(vpnSettings settings.VPN, ipv6Supported bool, vpnInputPorts []uint1
 	providers Providers, storage Storage, openvpnConf OpenVPN,
 	netLinker NetLinker, fw Firewall, routing Routing,
 	portForward PortForward, starter CmdStarter,
-	publicip PublicIPLoop, dnsLooper DNSLoop,
+	publicip PublicIPLoop, dnsLooper DNSLoop, eventPublisher EventPublisher,
 	logger log.LoggerInterface, client *http.Client,
 	buildInfo models.BuildInformation, versionInfo bool) *Loop {
 	start := make(chan struct{})
@@ -60,33 +89,41 @@ func NewLoop(vpnSettings settings.VPN, ipv6Supported bool, vpnInputPorts []uint1
 	stop := make(chan struct{})
 	stopped := make(chan struct{})
 
-	statusManager := loopstate.New(constants.Stopped, start, running, stop, stopped)
-	state := state.New(statusManager, vpnSettings)
+	onStatusChange := func(status models.LoopStatus) {
+		eventPublisher.Publish(events.VPNStatus, events.Status{Status: status})
+	}
+	statusManager := loopstate.New(constants.Stopped, start, running, stop, stopped, onStatusChange)
 
-	return &Loop{
-		statusManager: statusManager,
-		state:         state,
-		providers:     providers,
-		storage:       storage,
-		buildInfo:     buildInfo,
-		versionInfo:   versionInfo,
-		ipv6Supported: ipv6Supported,
-		vpnInputPorts: vpnInputPorts,
-		openvpnConf:   openvpnConf,
-		netLinker:     netLinker,
-		fw:            fw,
-		routing:       routing,
-		portForward:   portForward,
-		publicip:      publicip,
-		dnsLooper:     dnsLooper,
-		starter:       starter,
-		logger:        logger,
-		client:        client,
-		start:         start,
-		running:       running,
-		stop:          stop,
-		stopped:       stopped,
-		userTrigger:   true,
-		backoffTime:   defaultBackoffTime,
+	loop := &Loop{
+		statusManager:  statusManager,
+		providers:      providers,
+		storage:        storage,
+		buildInfo:      buildInfo,
+		versionInfo:    versionInfo,
+		ipv6Supported:  ipv6Supported,
+		vpnInputPorts:  vpnInputPorts,
+		openvpnConf:    openvpnConf,
+		netLinker:      netLinker,
+		fw:             fw,
+		routing:        routing,
+		portForward:    portForward,
+		publicip:       publicip,
+		dnsLooper:      dnsLooper,
+		starter:        starter,
+		logger:         logger,
+		client:         client,
+		start:          start,
+		running:        running,
+		stop:           stop,
+		stopped:        stopped,
+		peerSwitch:     make(chan chan<- error),
+		userTrigger:    true,
+		failures:       make(map[netip.Addr]models.ServerFailure),
+		escalation:     models.Escalation{Step: constants.EscalationNone},
+		lastServerPath: constants.LastServer,
+		backoffTime:    defaultBackoffTime,
+		timeNow:        time.Now,
 	}
+	loop.state = state.New(loop, vpnSettings)
+	return loop
 }
@@ -0,0 +1,105 @@
+package vpn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/probe"
+	"github.com/qdm12/log"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+// latencyProber probes VPN server connections through the firewall,
+// before the VPN is connected, to pick the lowest latency connection.
+type latencyProber struct {
+	ctx                 context.Context //nolint:containedctx
+	fw                  Firewall
+	wireguardPrivateKey string
+	logger              log.LoggerInterface
+}
+
+func newLatencyProber(ctx context.Context, fw Firewall,
+	wireguardSettings settings.Wireguard, logger log.LoggerInterface) *latencyProber {
+	return &latencyProber{
+		ctx:                 ctx,
+		fw:                  fw,
+		wireguardPrivateKey: *wireguardSettings.PrivateKey,
+		logger:              logger,
+	}
+}
+
+// ProbeLatencies probes all the connections given in parallel and
+// returns their latencies, where a zero latency indicates the probe
+// failed.
+func (p *latencyProber) ProbeLatencies(connections []models.Connection) (
+	latencies []time.Duration) {
+	latencies = make([]time.Duration, len(connections))
+
+	err := p.fw.SetProbeConnections(p.ctx, connections)
+	if err != nil {
+		p.logger.Warn("allowing probe connections through firewall: " + err.Error())
+		return latencies
+	}
+	defer func() {
+		err := p.fw.SetProbeConnections(p.ctx, nil)
+		if err != nil {
+			p.logger.Warn("removing probe connections from firewall: " + err.Error())
+		}
+	}()
+
+	const probeTimeout = 2 * time.Second
+	ctx, cancel := context.WithTimeout(p.ctx, probeTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i, connection := range connections {
+		wg.Add(1)
+		go func(i int, connection models.Connection) {
+			defer wg.Done()
+			latency, err := p.probe(ctx, connection)
+			if err != nil {
+				p.logger.Debug("probing " + connection.IP.String() + ": " + err.Error())
+				return
+			}
+			p.logger.Debug("latency of " + connection.IP.String() + " is " + latency.String())
+			latencies[i] = latency
+		}(i, connection)
+	}
+	wg.Wait()
+
+	return latencies
+}
+
+var errProbeNotSupported = errors.New("latency probe is not supported")
+
+func (p *latencyProber) probe(ctx context.Context,
+	connection models.Connection) (latency time.Duration, err error) {
+	address := netip.AddrPortFrom(connection.IP, connection.Port).String()
+	switch {
+	case connection.Type == vpn.Wireguard:
+		privateKey, err := wgtypes.ParseKey(p.wireguardPrivateKey)
+		if err != nil {
+			return 0, err
+		}
+		publicKey, err := wgtypes.ParseKey(connection.PubKey)
+		if err != nil {
+			return 0, err
+		}
+		return probe.WireguardHandshake(ctx, address, privateKey, publicKey)
+	case connection.Protocol == constants.TCP:
+		return probe.TCP(ctx, address)
+	default:
+		// Settings validation rejects the latency strategy for
+		// OpenVPN over UDP, since most servers drop unauthenticated
+		// packets when using tls-auth or tls-crypt.
+		return 0, fmt.Errorf("%w: OpenVPN over UDP", errProbeNotSupported)
+	}
+}
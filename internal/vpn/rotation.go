@@ -0,0 +1,157 @@
+package vpn
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/cron"
+)
+
+// runRotation checks every minute if the VPN connection is due
+// for a rotation according to the rotation settings, and rotates
+// the VPN connection to another server if it is.
+func (l *Loop) runRotation(ctx context.Context) {
+	const checkPeriod = time.Minute
+	ticker := time.NewTicker(checkPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		vpnSettings := l.state.GetSettings()
+		if !rotationDue(vpnSettings.Rotation, l.getConnectedSince(), l.timeNow()) {
+			continue
+		}
+
+		if *vpnSettings.Rotation.OnlyIdlePortForward {
+			ports := l.portForward.GetPortsForwarded()
+			if *vpnSettings.Provider.PortForwarding.ListeningPort != 0 {
+				ports = append(ports, *vpnSettings.Provider.PortForwarding.ListeningPort)
+			}
+			active, err := portsHaveConnections(ports)
+			if err != nil {
+				l.logger.Warn("checking forwarded ports connections: " + err.Error())
+			}
+			if active {
+				l.logger.Debug("delaying VPN server rotation since forwarded ports have active connections")
+				continue
+			}
+		}
+
+		l.logger.Info("rotating VPN server as scheduled")
+		_, err := l.RotateConnection(ctx)
+		if err != nil {
+			l.logger.Error("rotating VPN server: " + err.Error())
+		}
+	}
+}
+
+// rotationDue returns true if the VPN connection established at
+// connectedSince should be rotated at the time now, given the
+// rotation settings.
+func rotationDue(rotation settings.VPNRotation, connectedSince, now time.Time) (due bool) {
+	if !rotation.Enabled() || connectedSince.IsZero() {
+		return false
+	}
+
+	if *rotation.Period > 0 {
+		due = now.Sub(connectedSince) >= *rotation.Period
+	} else {
+		schedule, err := cron.Parse(*rotation.Cron)
+		if err != nil { // validated in settings
+			return false
+		}
+		next := schedule.Next(connectedSince)
+		due = !next.IsZero() && !next.After(now)
+	}
+
+	if !due || *rotation.Window == "" {
+		return due
+	}
+
+	window, err := cron.ParseWindow(*rotation.Window)
+	if err != nil { // validated in settings
+		return false
+	}
+	return window.Contains(now)
+}
+
+// portsHaveConnections returns true if at least one established
+// TCP connection, over IPv4 or IPv6, uses one of the local ports given.
+func portsHaveConnections(ports []uint16) (active bool, err error) {
+	if len(ports) == 0 {
+		return false, nil
+	}
+
+	for _, path := range [...]string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		file, err := os.Open(path)
+		if err != nil {
+			if os.IsNotExist(err) { // IPv6 disabled
+				continue
+			}
+			return false, err
+		}
+
+		established, err := parseEstablishedLocalPorts(file)
+		_ = file.Close()
+		if err != nil {
+			return false, fmt.Errorf("parsing %s: %w", path, err)
+		}
+
+		for _, port := range ports {
+			if _, ok := established[port]; ok {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+var ErrLocalAddressMalformed = errors.New("local address is malformed")
+
+// parseEstablishedLocalPorts parses a procfs TCP table such as
+// /proc/net/tcp and returns the set of local ports of established
+// connections.
+func parseEstablishedLocalPorts(reader io.Reader) (
+	ports map[uint16]struct{}, err error) {
+	ports = make(map[uint16]struct{})
+	scanner := bufio.NewScanner(reader)
+	scanner.Scan() // skip header line
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		const minFields = 4
+		if len(fields) < minFields {
+			continue
+		}
+
+		const tcpEstablished = "01"
+		if fields[3] != tcpEstablished {
+			continue
+		}
+
+		_, hexPort, ok := strings.Cut(fields[1], ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrLocalAddressMalformed, fields[1])
+		}
+		const base, bitSize = 16, 16
+		port, err := strconv.ParseUint(hexPort, base, bitSize)
+		if err != nil {
+			return nil, fmt.Errorf("local port: %w", err)
+		}
+		ports[uint16(port)] = struct{}{}
+	}
+	return ports, scanner.Err()
+}
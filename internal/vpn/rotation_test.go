@@ -0,0 +1,126 @@
+package vpn
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_rotationDue(t *testing.T) {
+	t.Parallel()
+
+	connectedSince := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		rotation       settings.VPNRotation
+		connectedSince time.Time
+		now            time.Time
+		due            bool
+	}{
+		"disabled": {
+			rotation: settings.VPNRotation{
+				Period: ptrTo(time.Duration(0)),
+				Cron:   ptrTo(""),
+			},
+			connectedSince: connectedSince,
+			now:            connectedSince.Add(time.Hour),
+		},
+		"not_connected": {
+			rotation: settings.VPNRotation{
+				Period: ptrTo(time.Hour),
+				Cron:   ptrTo(""),
+			},
+			now: connectedSince.Add(time.Hour),
+		},
+		"period_not_elapsed": {
+			rotation: settings.VPNRotation{
+				Period: ptrTo(time.Hour),
+				Cron:   ptrTo(""),
+				Window: ptrTo(""),
+			},
+			connectedSince: connectedSince,
+			now:            connectedSince.Add(time.Minute),
+		},
+		"period_elapsed": {
+			rotation: settings.VPNRotation{
+				Period: ptrTo(time.Hour),
+				Cron:   ptrTo(""),
+				Window: ptrTo(""),
+			},
+			connectedSince: connectedSince,
+			now:            connectedSince.Add(time.Hour),
+			due:            true,
+		},
+		"cron_not_reached": {
+			rotation: settings.VPNRotation{
+				Period: ptrTo(time.Duration(0)),
+				Cron:   ptrTo("0 4 * * *"),
+				Window: ptrTo(""),
+			},
+			connectedSince: connectedSince,
+			now:            connectedSince.Add(17 * time.Hour),
+		},
+		"cron_reached": {
+			rotation: settings.VPNRotation{
+				Period: ptrTo(time.Duration(0)),
+				Cron:   ptrTo("0 4 * * *"),
+				Window: ptrTo(""),
+			},
+			connectedSince: connectedSince,
+			now:            connectedSince.Add(18 * time.Hour),
+			due:            true,
+		},
+		"outside_window": {
+			rotation: settings.VPNRotation{
+				Period: ptrTo(time.Hour),
+				Cron:   ptrTo(""),
+				Window: ptrTo("02:00-05:00"),
+			},
+			connectedSince: connectedSince,
+			now:            connectedSince.Add(2 * time.Hour),
+		},
+		"inside_window": {
+			rotation: settings.VPNRotation{
+				Period: ptrTo(time.Hour),
+				Cron:   ptrTo(""),
+				Window: ptrTo("02:00-05:00"),
+			},
+			connectedSince: connectedSince,
+			now:            connectedSince.Add(17 * time.Hour),
+			due:            true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			due := rotationDue(testCase.rotation, testCase.connectedSince, testCase.now)
+
+			assert.Equal(t, testCase.due, due)
+		})
+	}
+}
+
+func Test_parseEstablishedLocalPorts(t *testing.T) {
+	t.Parallel()
+
+	const table = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1 1 0 100 0 0 10 0
+   1: 0A000002:D431 0A000001:0050 01 00000000:00000000 00:00000000 00000000     0        0 2 1 0 20 4 30 10 -1
+   2: 0A000002:1F91 0A000001:C350 06 00000000:00000000 00:00000000 00000000     0        0 0 3 0
+`
+
+	ports, err := parseEstablishedLocalPorts(strings.NewReader(table))
+
+	require.NoError(t, err)
+	expected := map[uint16]struct{}{
+		54321: {},
+	}
+	assert.Equal(t, expected, ports)
+}
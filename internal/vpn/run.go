@@ -2,9 +2,12 @@ package vpn
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/wireguard"
 	"github.com/qdm12/log"
 )
 
@@ -17,8 +20,17 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 		return
 	}
 
+	backgroundCtx, backgroundCancel := context.WithCancel(ctx)
+	defer backgroundCancel()
+	go l.runRotation(backgroundCtx)
+	go l.runFallbackSwitchBack(backgroundCtx)
+
 	for ctx.Err() == nil {
-		settings := l.state.GetSettings()
+		settings, options := l.nextSettings(ctx)
+		if *settings.Failover.Enabled {
+			// walk the candidate servers without an exponential backoff
+			l.backoffTime = defaultBackoffTime
+		}
 
 		providerConf := l.providers.Get(settings.Provider.Name)
 
@@ -28,26 +40,29 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 		var vpnRunner interface {
 			Run(ctx context.Context, waitError chan<- error, tunnelReady chan<- struct{})
 		}
-		var serverName, vpnInterface string
-		var canPortForward bool
+		var vpnInterface string
+		var wireguarder *wireguard.Wireguard
+		var connection models.Connection
 		var err error
 		subLogger := l.logger.New(log.SetComponent(settings.Type))
 		if settings.Type == vpn.OpenVPN {
 			vpnInterface = settings.OpenVPN.Interface
-			vpnRunner, serverName, canPortForward, err = setupOpenVPN(ctx, l.fw,
-				l.openvpnConf, providerConf, settings, l.ipv6Supported, l.starter, subLogger)
+			vpnRunner, connection, err = setupOpenVPN(ctx, l.fw,
+				l.openvpnConf, providerConf, settings, options, l.ipv6Supported, l.starter, subLogger)
 		} else { // Wireguard
 			vpnInterface = settings.Wireguard.Interface
-			vpnRunner, serverName, canPortForward, err = setupWireguard(ctx, l.netLinker, l.fw,
-				providerConf, settings, l.ipv6Supported, subLogger)
+			wireguarder, connection, err = setupWireguard(ctx, l.netLinker, l.fw,
+				providerConf, settings, options, l.ipv6Supported, subLogger)
+			vpnRunner = wireguarder
 		}
 		if err != nil {
+			l.recordFailure(connection, err)
 			l.crashed(ctx, err)
 			continue
 		}
 		tunnelUpData := tunnelUpData{
-			serverName:     serverName,
-			canPortForward: canPortForward,
+			serverName:     connection.ServerName,
+			canPortForward: connection.PortForward,
 			portForwarder:  portForwarder,
 			vpnIntf:        vpnInterface,
 			username:       settings.Provider.PortForwarding.Username,
@@ -62,18 +77,36 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 
 		if err := l.waitForError(ctx, waitError); err != nil {
 			openvpnCancel()
+			l.recordFailure(connection, err)
 			l.crashed(ctx, err)
 			continue
 		}
 
+		l.setConnection(connection, vpnInterface)
 		l.backoffTime = defaultBackoffTime
 		l.signalOrSetStatus(constants.Running)
 
+		tunnelIsUp := false
 		stayHere := true
 		for stayHere {
 			select {
 			case <-tunnelReady:
+				tunnelIsUp = true
+				go l.onTunnelUp(openvpnCtx, tunnelUpData)
+			case result := <-l.peerSwitch:
+				if wireguarder == nil || !tunnelIsUp {
+					result <- fmt.Errorf("%w", errPeerSwitchUnavailable)
+					break
+				}
+				newConnection, newTunnelUpData, err := l.switchPeer(ctx, wireguarder)
+				if err != nil {
+					result <- err
+					break
+				}
+				connection, tunnelUpData = newConnection, newTunnelUpData
+				l.onPeerSwitched(connection, tunnelUpData.vpnIntf)
 				go l.onTunnelUp(openvpnCtx, tunnelUpData)
+				result <- nil
 			case <-ctx.Done():
 				l.cleanup()
 				openvpnCancel()
@@ -83,6 +116,7 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 			case <-l.stop:
 				l.userTrigger = true
 				l.logger.Info("stopping")
+				tunnelIsUp = false
 				l.cleanup()
 				openvpnCancel()
 				<-waitError
@@ -96,6 +130,7 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 			case err := <-waitError: // unexpected error
 				l.statusManager.Lock() // prevent SetStatus from running in parallel
 
+				l.recordFailure(connection, err)
 				l.cleanup()
 				openvpnCancel()
 				l.statusManager.SetStatus(constants.Crashed)
@@ -5,24 +5,22 @@ import (
 	"sync"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
-	"github.com/qdm12/gluetun/internal/models"
 )
 
-func New(statusApplier StatusApplier, vpn settings.VPN) *State {
+func New(restarter Restarter, vpn settings.VPN) *State {
 	return &State{
-		statusApplier: statusApplier,
-		vpn:           vpn,
+		restarter: restarter,
+		vpn:       vpn,
 	}
 }
 
 type State struct {
-	statusApplier StatusApplier
+	restarter Restarter
 
 	vpn        settings.VPN
 	settingsMu sync.RWMutex
 }
 
-type StatusApplier interface {
-	ApplyStatus(ctx context.Context, status models.LoopStatus) (
-		outcome string, err error)
+type Restarter interface {
+	Restart(ctx context.Context) (outcome string, err error)
 }
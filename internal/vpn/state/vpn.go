@@ -5,7 +5,6 @@ import (
 	"reflect"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
-	"github.com/qdm12/gluetun/internal/constants"
 )
 
 func (s *State) GetSettings() (vpn settings.VPN) {
@@ -25,7 +24,6 @@ func (s *State) SetSettings(ctx context.Context, vpn settings.VPN) (
 	}
 	s.vpn = vpn
 	s.settingsMu.Unlock()
-	_, _ = s.statusApplier.ApplyStatus(ctx, constants.Stopped)
-	outcome, _ = s.statusApplier.ApplyStatus(ctx, constants.Running)
+	outcome, _ = s.restarter.Restart(ctx)
 	return outcome
 }
@@ -0,0 +1,194 @@
+package vpn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider/utils"
+	"github.com/qdm12/gluetun/internal/wireguard"
+)
+
+// nextSettings returns the VPN settings and the connection options
+// to use to pick the next VPN connection. If the last peer switch
+// failed, it returns the settings and options computed for it.
+func (l *Loop) nextSettings(ctx context.Context) (settings settings.VPN,
+	options utils.ConnectionOptions) {
+	if l.failedSwitch != nil {
+		settings, options = l.failedSwitch.settings, l.failedSwitch.options
+		l.failedSwitch = nil
+		return settings, options
+	}
+
+	pinnedIPs, location := l.popEscalationOverrides()
+	primarySettings := l.state.GetSettings()
+	if location != nil {
+		primarySettings.Provider.ServerSelection = location.Apply(primarySettings.Provider.ServerSelection)
+	}
+	settings = l.vpnSettingsToUse(primarySettings)
+	options.ExcludedConnections = l.popRotationExclusion()
+	rotating := len(options.ExcludedConnections) > 0
+	options.CandidateIPs = l.failoverCandidateIPs(settings, rotating)
+	if !l.lastServerTried {
+		l.lastServerTried = true
+		if candidateIPs := l.lastServerCandidateIPs(settings); candidateIPs != nil {
+			options.CandidateIPs = candidateIPs
+		}
+	}
+	if pinnedIPs != nil {
+		options.CandidateIPs = pinnedIPs
+	}
+	if *settings.Provider.ServerSelection.Strategy == constants.SelectionLatency {
+		options.Prober = newLatencyProber(ctx, l.fw,
+			settings.Wireguard, l.logger)
+	}
+	return settings, options
+}
+
+// Restart restarts the VPN connection. For Wireguard, it first tries
+// to switch the running tunnel to the next server before tearing down
+// the current connection, and only falls back on stopping and starting
+// the VPN if this is not possible.
+func (l *Loop) Restart(ctx context.Context) (outcome string, err error) {
+	err = l.requestPeerSwitch(ctx)
+	switch {
+	case err == nil:
+		return constants.Running.String(), nil
+	case errors.Is(err, errPeerSwitchUnavailable):
+	case errors.Is(err, wireguard.ErrPeerSwitchNotPossible):
+		l.logger.Info(err.Error() + "; restarting VPN")
+	default:
+		l.logger.Warn("switching Wireguard server without interruption: " +
+			err.Error() + "; restarting VPN instead")
+	}
+
+	_, err = l.statusManager.ApplyStatus(ctx, constants.Stopped)
+	if err != nil {
+		return "", fmt.Errorf("stopping VPN: %w", err)
+	}
+
+	outcome, err = l.statusManager.ApplyStatus(ctx, constants.Running)
+	if err != nil {
+		return "", fmt.Errorf("starting VPN: %w", err)
+	}
+	return outcome, nil
+}
+
+var errPeerSwitchUnavailable = errors.New("no running Wireguard tunnel to switch")
+
+// nextConnection contains the VPN settings and connection
+// options to use to pick the next VPN connection.
+type nextConnection struct {
+	settings settings.VPN
+	options  utils.ConnectionOptions
+}
+
+// requestPeerSwitch requests the run loop to switch its running
+// Wireguard tunnel to the next server, and waits for the result.
+// It returns an error wrapping errPeerSwitchUnavailable if there
+// is no running Wireguard tunnel.
+func (l *Loop) requestPeerSwitch(ctx context.Context) (err error) {
+	result := make(chan error, 1)
+	select {
+	case l.peerSwitch <- result:
+	default: // run loop is not waiting on a running tunnel
+		return fmt.Errorf("%w", errPeerSwitchUnavailable)
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err = <-result:
+		return err
+	}
+}
+
+// switchPeer picks the next VPN connection and switches the running
+// Wireguard tunnel to it. The firewall allows the new connection during
+// the handshake with the new server, and the previous connection is only
+// disallowed once the traffic goes through the new connection.
+func (l *Loop) switchPeer(ctx context.Context, wireguarder *wireguard.Wireguard) (
+	connection models.Connection, data tunnelUpData, err error) {
+	settings, options := l.nextSettings(ctx)
+	defer func() {
+		if err != nil {
+			// Keep the settings and options computed, which consumed the
+			// rotation exclusion, the escalation overrides and the last
+			// server attempt, for the VPN restart following this failure.
+			l.failedSwitch = &nextConnection{settings: settings, options: options}
+		}
+	}()
+
+	if settings.Type != vpn.Wireguard {
+		return connection, data, fmt.Errorf("%w: next VPN type is %s",
+			wireguard.ErrPeerSwitchNotPossible, settings.Type)
+	}
+
+	providerConf := l.providers.Get(settings.Provider.Name)
+	connection, err = providerConf.GetConnection(settings.Provider.ServerSelection,
+		options, l.ipv6Supported)
+	if err != nil {
+		return connection, data, fmt.Errorf("finding a VPN server: %w", err)
+	}
+
+	wireguardSettings := utils.BuildWireguardSettings(connection, settings.Wireguard, l.ipv6Supported)
+
+	err = l.fw.SetProbeConnections(ctx, []models.Connection{connection})
+	if err != nil {
+		return connection, data, fmt.Errorf("allowing new VPN connection: %w", err)
+	}
+	defer func() {
+		probeErr := l.fw.SetProbeConnections(ctx, nil)
+		if probeErr != nil {
+			l.logger.Error("removing new VPN connection temporary rule: " + probeErr.Error())
+		}
+	}()
+
+	const handshakeTimeout = 5 * time.Second
+	switchCtx, switchCancel := context.WithTimeout(ctx, handshakeTimeout)
+	defer switchCancel()
+	err = wireguarder.SwitchPeer(switchCtx, wireguardSettings)
+	if err != nil {
+		if !errors.Is(err, wireguard.ErrPeerSwitchNotPossible) {
+			l.recordFailure(connection, err)
+		}
+		return connection, data, fmt.Errorf("switching peer: %w", err)
+	}
+
+	err = l.fw.SetVPNConnection(ctx, connection, settings.Wireguard.Interface)
+	if err != nil {
+		return connection, data, fmt.Errorf("setting firewall: %w", err)
+	}
+
+	data = tunnelUpData{
+		serverName:     connection.ServerName,
+		canPortForward: connection.PortForward,
+		portForwarder: getPortForwarder(providerConf, l.providers,
+			*settings.Provider.PortForwarding.Provider),
+		vpnIntf:  settings.Wireguard.Interface,
+		username: settings.Provider.PortForwarding.Username,
+		password: settings.Provider.PortForwarding.Password,
+	}
+	return connection, data, nil
+}
+
+// onPeerSwitched resets the state tied to the previous VPN connection
+// once the Wireguard tunnel switched to the connection given.
+func (l *Loop) onPeerSwitched(connection models.Connection, vpnInterface string) {
+	err := l.stopPortForwarding()
+	if err != nil && !errors.Is(err, context.Canceled) {
+		l.logger.Error("stopping port forwarding: " + err.Error())
+	}
+
+	err = l.publicip.ClearData()
+	if err != nil {
+		l.logger.Error("clearing public IP data: " + err.Error())
+	}
+
+	l.setConnection(connection, vpnInterface)
+}
@@ -0,0 +1,89 @@
+package vpn
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Loop_requestPeerSwitch(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	testCases := map[string]struct {
+		listening  bool
+		result     error
+		errWrapped error
+	}{
+		"run_loop_not_listening": {
+			errWrapped: errPeerSwitchUnavailable,
+		},
+		"switch_success": {
+			listening: true,
+		},
+		"switch_failure": {
+			listening:  true,
+			result:     errTest,
+			errWrapped: errTest,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			loop := &Loop{
+				peerSwitch: make(chan chan<- error),
+			}
+
+			if testCase.listening {
+				go func() {
+					result := <-loop.peerSwitch
+					result <- testCase.result
+				}()
+			}
+
+			var err error
+			for {
+				err = loop.requestPeerSwitch(context.Background())
+				if !testCase.listening || !errors.Is(err, errPeerSwitchUnavailable) {
+					break
+				}
+				// the goroutine is not yet receiving from the channel
+			}
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped == nil {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_Loop_nextSettings_failedSwitch(t *testing.T) {
+	t.Parallel()
+
+	failedSwitch := &nextConnection{
+		settings: settings.VPN{Type: vpn.Wireguard},
+		options: utils.ConnectionOptions{
+			ExcludedConnections: []models.Connection{{Hostname: "excluded"}},
+			CandidateIPs:        []netip.Addr{netip.AddrFrom4([4]byte{1, 2, 3, 4})},
+		},
+	}
+	loop := &Loop{failedSwitch: failedSwitch}
+
+	vpnSettings, options := loop.nextSettings(context.Background())
+
+	assert.Equal(t, failedSwitch.settings, vpnSettings)
+	assert.Equal(t, failedSwitch.options, options)
+	assert.Nil(t, loop.failedSwitch)
+}
@@ -30,6 +30,24 @@ func makeDeviceConfig(settings Settings) (config wgtypes.Config, err error) {
 		return config, ErrPrivateKeyInvalid
 	}
 
+	peerConfig, err := makePeerConfig(settings)
+	if err != nil {
+		return config, err
+	}
+
+	firewallMark := int(settings.FirewallMark)
+
+	config = wgtypes.Config{
+		PrivateKey:   &privateKey,
+		ReplacePeers: true,
+		FirewallMark: &firewallMark,
+		Peers:        []wgtypes.PeerConfig{peerConfig},
+	}
+
+	return config, nil
+}
+
+func makePeerConfig(settings Settings) (config wgtypes.PeerConfig, err error) {
 	publicKey, err := wgtypes.ParseKey(settings.PublicKey)
 	if err != nil {
 		return config, fmt.Errorf("%w: %s", ErrPublicKeyInvalid, settings.PublicKey)
@@ -50,37 +68,26 @@ func makeDeviceConfig(settings Settings) (config wgtypes.Config, err error) {
 		*persistentKeepaliveInterval = settings.PersistentKeepaliveInterval
 	}
 
-	firewallMark := int(settings.FirewallMark)
-
-	config = wgtypes.Config{
-		PrivateKey:   &privateKey,
-		ReplacePeers: true,
-		FirewallMark: &firewallMark,
-		Peers: []wgtypes.PeerConfig{
+	return wgtypes.PeerConfig{
+		PublicKey:    publicKey,
+		PresharedKey: preSharedKey,
+		AllowedIPs: []net.IPNet{
 			{
-				PublicKey:    publicKey,
-				PresharedKey: preSharedKey,
-				AllowedIPs: []net.IPNet{
-					{
-						IP:   net.IPv4(0, 0, 0, 0),
-						Mask: []byte{0, 0, 0, 0},
-					},
-					{
-						IP:   net.IPv6zero,
-						Mask: []byte(net.IPv6zero),
-					},
-				},
-				PersistentKeepaliveInterval: persistentKeepaliveInterval,
-				ReplaceAllowedIPs:           true,
-				Endpoint: &net.UDPAddr{
-					IP:   settings.Endpoint.Addr().AsSlice(),
-					Port: int(settings.Endpoint.Port()),
-				},
+				IP:   net.IPv4(0, 0, 0, 0),
+				Mask: []byte{0, 0, 0, 0},
+			},
+			{
+				IP:   net.IPv6zero,
+				Mask: []byte(net.IPv6zero),
 			},
 		},
-	}
-
-	return config, nil
+		PersistentKeepaliveInterval: persistentKeepaliveInterval,
+		ReplaceAllowedIPs:           true,
+		Endpoint: &net.UDPAddr{
+			IP:   settings.Endpoint.Addr().AsSlice(),
+			Port: int(settings.Endpoint.Port()),
+		},
+	}, nil
 }
 
 func allIPv4() (prefix netip.Prefix) {
@@ -52,6 +52,7 @@ func Test_New(t *testing.T) {
 						allIPv4(),
 					},
 					FirewallMark:   100,
+					RoutingTable:   100,
 					MTU:            device.DefaultMTU,
 					IPv6:           ptr(false),
 					Implementation: "auto",
@@ -0,0 +1,192 @@
+package wireguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"golang.zx2c4.com/wireguard/wgctrl"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+var (
+	ErrPeerSwitchNotPossible = errors.New("peer switch is not possible")
+	ErrHandshakeNotCompleted = errors.New("handshake with the new peer did not complete")
+)
+
+// SwitchPeer switches the running Wireguard device to the peer defined
+// in the settings given, without tearing down the interface.
+// The new peer is added next to the current peer and only takes over
+// the allowed IPs once a handshake with it completed, after which the
+// current peer is removed. Only the peer related settings can differ
+// from the current settings, and this must only be called once the
+// device is ready. It returns an error wrapping ErrPeerSwitchNotPossible
+// if the peer and its endpoint are the same as the current ones.
+func (w *Wireguard) SwitchPeer(ctx context.Context, settings Settings) (err error) {
+	settings.SetDefaults()
+	err = settings.Check()
+	if err != nil {
+		return err
+	}
+
+	if !w.settings.peerSwitchable(settings) {
+		return fmt.Errorf("%w: non-peer settings differ", ErrPeerSwitchNotPossible)
+	}
+
+	client, err := wgctrl.New()
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrWgctrlOpen, err)
+	}
+	defer client.Close()
+
+	newPeer, err := makePeerConfig(settings)
+	if err != nil {
+		return fmt.Errorf("making new peer configuration: %w", err)
+	}
+	// Always set the keepalive interval, since a nil value
+	// leaves the interval of an existing peer unchanged.
+	newPeer.PersistentKeepaliveInterval = &settings.PersistentKeepaliveInterval
+
+	oldPublicKey, err := wgtypes.ParseKey(w.settings.PublicKey)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrPublicKeyInvalid, w.settings.PublicKey)
+	}
+
+	samePeer := newPeer.PublicKey == oldPublicKey
+	if samePeer && settings.Endpoint == w.settings.Endpoint {
+		return fmt.Errorf("%w: same peer and endpoint %s",
+			ErrPeerSwitchNotPossible, settings.Endpoint)
+	}
+
+	w.logger.Info("Switching to " + settings.Endpoint.String())
+
+	if samePeer {
+		return w.switchPeerEndpoint(ctx, client, settings, newPeer)
+	}
+
+	// Add the new peer without allowed IPs so it does not take any
+	// traffic yet, with a short keepalive to trigger a handshake.
+	const handshakeKeepalive = time.Second
+	handshakeKeepaliveInterval := handshakeKeepalive
+	handshakePeer := newPeer
+	handshakePeer.AllowedIPs = nil
+	handshakePeer.PersistentKeepaliveInterval = &handshakeKeepaliveInterval
+	err = client.ConfigureDevice(settings.InterfaceName, wgtypes.Config{
+		Peers: []wgtypes.PeerConfig{handshakePeer},
+	})
+	if err != nil {
+		return fmt.Errorf("%w: adding new peer: %s", ErrConfigure, err)
+	}
+
+	err = waitForHandshake(ctx, client, settings.InterfaceName, newPeer.PublicKey)
+	if err != nil {
+		removeErr := client.ConfigureDevice(settings.InterfaceName, wgtypes.Config{
+			Peers: []wgtypes.PeerConfig{{PublicKey: newPeer.PublicKey, Remove: true}},
+		})
+		if removeErr != nil {
+			w.logger.Error("removing new peer: " + removeErr.Error())
+		}
+		return err
+	}
+
+	// Move the allowed IPs to the new peer and remove the old
+	// peer in a single device configuration.
+	err = client.ConfigureDevice(settings.InterfaceName, wgtypes.Config{
+		Peers: []wgtypes.PeerConfig{
+			{PublicKey: oldPublicKey, Remove: true},
+			newPeer,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("%w: switching peer: %s", ErrConfigure, err)
+	}
+	w.settings = settings
+
+	return nil
+}
+
+// switchPeerEndpoint switches the current peer to the new endpoint of the
+// new peer configuration given, which has the same public key. The peer is
+// re-created so its session is dropped, and a handshake with the new
+// endpoint must complete for the switch to succeed.
+func (w *Wireguard) switchPeerEndpoint(ctx context.Context, client *wgctrl.Client,
+	settings Settings, newPeer wgtypes.PeerConfig) (err error) {
+	// Re-create the peer with a short keepalive to trigger a handshake,
+	// since roaming to the new endpoint would otherwise keep using the
+	// current session without any handshake.
+	const handshakeKeepalive = time.Second
+	handshakeKeepaliveInterval := handshakeKeepalive
+	handshakePeer := newPeer
+	handshakePeer.PersistentKeepaliveInterval = &handshakeKeepaliveInterval
+	err = client.ConfigureDevice(settings.InterfaceName, wgtypes.Config{
+		Peers: []wgtypes.PeerConfig{
+			{PublicKey: newPeer.PublicKey, Remove: true},
+			handshakePeer,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("%w: re-creating peer: %s", ErrConfigure, err)
+	}
+	// The peer is now at the new endpoint, whether or not
+	// the handshake with it completes.
+	w.settings = settings
+
+	err = waitForHandshake(ctx, client, settings.InterfaceName, newPeer.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	err = client.ConfigureDevice(settings.InterfaceName, wgtypes.Config{
+		Peers: []wgtypes.PeerConfig{newPeer},
+	})
+	if err != nil {
+		return fmt.Errorf("%w: setting keepalive: %s", ErrConfigure, err)
+	}
+	return nil
+}
+
+// peerSwitchable returns true if the other settings given only
+// differ from the settings in their peer related fields.
+func (s Settings) peerSwitchable(other Settings) bool {
+	return s.InterfaceName == other.InterfaceName &&
+		s.PrivateKey == other.PrivateKey &&
+		slices.Equal(s.Addresses, other.Addresses) &&
+		slices.Equal(s.AllowedIPs, other.AllowedIPs) &&
+		s.FirewallMark == other.FirewallMark &&
+		s.MTU == other.MTU &&
+		s.RulePriority == other.RulePriority &&
+		s.RoutingTable == other.RoutingTable &&
+		s.PolicyRoutingOnly == other.PolicyRoutingOnly &&
+		*s.IPv6 == *other.IPv6 &&
+		s.Implementation == other.Implementation
+}
+
+func waitForHandshake(ctx context.Context, client *wgctrl.Client,
+	interfaceName string, publicKey wgtypes.Key) (err error) {
+	const pollPeriod = 100 * time.Millisecond
+	ticker := time.NewTicker(pollPeriod)
+	defer ticker.Stop()
+
+	for {
+		device, err := client.Device(interfaceName)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrDeviceInfo, err)
+		}
+
+		for _, peer := range device.Peers {
+			// The Linux kernel reports the Unix epoch if no
+			// handshake happened yet.
+			if peer.PublicKey == publicKey && peer.LastHandshakeTime.Unix() > 0 {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ErrHandshakeNotCompleted, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package wireguard
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Settings_peerSwitchable(t *testing.T) {
+	t.Parallel()
+
+	makeSettings := func() Settings {
+		return Settings{
+			InterfaceName:  "wg0",
+			PrivateKey:     "private",
+			PublicKey:      "public",
+			PreSharedKey:   "preshared",
+			Endpoint:       netip.MustParseAddrPort("1.2.3.4:51820"),
+			Addresses:      []netip.Prefix{netip.MustParsePrefix("10.0.0.2/32")},
+			AllowedIPs:     []netip.Prefix{allIPv4()},
+			FirewallMark:   51820,
+			MTU:            1420,
+			RulePriority:   101,
+			IPv6:           ptrTo(false),
+			Implementation: "auto",
+		}
+	}
+
+	testCases := map[string]struct {
+		modify     func(settings *Settings)
+		switchable bool
+	}{
+		"same settings": {
+			modify:     func(*Settings) {},
+			switchable: true,
+		},
+		"different peer": {
+			modify: func(settings *Settings) {
+				settings.PublicKey = "other public"
+				settings.PreSharedKey = ""
+				settings.Endpoint = netip.MustParseAddrPort("5.6.7.8:1234")
+				settings.PersistentKeepaliveInterval = 25
+			},
+			switchable: true,
+		},
+		"different private key": {
+			modify: func(settings *Settings) {
+				settings.PrivateKey = "other private"
+			},
+		},
+		"different addresses": {
+			modify: func(settings *Settings) {
+				settings.Addresses = []netip.Prefix{netip.MustParsePrefix("10.0.0.3/32")}
+			},
+		},
+		"different allowed IPs": {
+			modify: func(settings *Settings) {
+				settings.AllowedIPs = []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+			},
+		},
+		"different MTU": {
+			modify: func(settings *Settings) {
+				settings.MTU = 1280
+			},
+		},
+		"different IPv6": {
+			modify: func(settings *Settings) {
+				settings.IPv6 = ptrTo(true)
+			},
+		},
+		"different implementation": {
+			modify: func(settings *Settings) {
+				settings.Implementation = "userspace"
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			other := makeSettings()
+			testCase.modify(&other)
+
+			switchable := makeSettings().peerSwitchable(other)
+
+			assert.Equal(t, testCase.switchable, switchable)
+		})
+	}
+}
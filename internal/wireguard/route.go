@@ -9,9 +9,9 @@ import (
 )
 
 func (w *Wireguard) addRoutes(link netlink.Link, destinations []netip.Prefix,
-	firewallMark uint32) (err error) {
+	table int) (err error) {
 	for _, dst := range destinations {
-		err = w.addRoute(link, dst, firewallMark)
+		err = w.addRoute(link, dst, table)
 		if err == nil {
 			continue
 		}
@@ -29,18 +29,18 @@ func (w *Wireguard) addRoutes(link netlink.Link, destinations []netip.Prefix,
 }
 
 func (w *Wireguard) addRoute(link netlink.Link, dst netip.Prefix,
-	firewallMark uint32) (err error) {
+	table int) (err error) {
 	route := netlink.Route{
 		LinkIndex: link.Index,
 		Dst:       dst,
-		Table:     int(firewallMark),
+		Table:     table,
 	}
 
 	err = w.netlink.RouteAdd(route)
 	if err != nil {
 		return fmt.Errorf(
 			"adding route for link %s, destination %s and table %d: %w",
-			link.Name, dst, firewallMark, err)
+			link.Name, dst, table, err)
 	}
 
 	return err
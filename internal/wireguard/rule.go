@@ -4,15 +4,39 @@ import (
 	"fmt"
 
 	"github.com/qdm12/gluetun/internal/netlink"
+	"golang.org/x/sys/unix"
 )
 
+// addRules adds the IPv4 and, if enabled, IPv6 rules routing all the
+// traffic not marked with the firewall mark through the routing table,
+// and registers their cleanup in the closers given.
+func (w *Wireguard) addRules(closers *closers) (err error) {
+	if *w.settings.IPv6 {
+		// requires net.ipv6.conf.all.disable_ipv6=0
+		ruleCleanup6, err := w.addRule(w.settings.RulePriority,
+			w.settings.FirewallMark, w.settings.RoutingTable, unix.AF_INET6)
+		if err != nil {
+			return fmt.Errorf("adding IPv6 rule: %w", err)
+		}
+		closers.add("removing IPv6 rule", stepOne, ruleCleanup6)
+	}
+
+	ruleCleanup, err := w.addRule(w.settings.RulePriority,
+		w.settings.FirewallMark, w.settings.RoutingTable, unix.AF_INET)
+	if err != nil {
+		return fmt.Errorf("adding IPv4 rule: %w", err)
+	}
+	closers.add("removing IPv4 rule", stepOne, ruleCleanup)
+	return nil
+}
+
 func (w *Wireguard) addRule(rulePriority int, firewallMark uint32,
-	family int) (cleanup func() error, err error) {
+	table, family int) (cleanup func() error, err error) {
 	rule := netlink.NewRule()
 	rule.Invert = true
 	rule.Priority = rulePriority
 	rule.Mark = firewallMark
-	rule.Table = int(firewallMark)
+	rule.Table = table
 	rule.Family = family
 	if err := w.netlink.RuleAdd(rule); err != nil {
 		return nil, fmt.Errorf("adding rule %s: %w", rule, err)
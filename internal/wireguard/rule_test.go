@@ -16,6 +16,7 @@ func Test_Wireguard_addRule(t *testing.T) {
 
 	const rulePriority = 987
 	const firewallMark = 456
+	const table = 654
 	const family = unix.AF_INET
 
 	errDummy := errors.New("dummy")
@@ -32,7 +33,7 @@ func Test_Wireguard_addRule(t *testing.T) {
 				Invert:   true,
 				Priority: rulePriority,
 				Mark:     firewallMark,
-				Table:    firewallMark,
+				Table:    table,
 				Family:   family,
 			},
 		},
@@ -41,22 +42,22 @@ func Test_Wireguard_addRule(t *testing.T) {
 				Invert:   true,
 				Priority: rulePriority,
 				Mark:     firewallMark,
-				Table:    firewallMark,
+				Table:    table,
 				Family:   family,
 			},
 			ruleAddErr: errDummy,
-			err:        errors.New("adding rule ip rule 987: from all to all table 456: dummy"),
+			err:        errors.New("adding rule ip rule 987: from all to all table 654: dummy"),
 		},
 		"rule delete error": {
 			expectedRule: netlink.Rule{
 				Invert:   true,
 				Priority: rulePriority,
 				Mark:     firewallMark,
-				Table:    firewallMark,
+				Table:    table,
 				Family:   family,
 			},
 			ruleDelErr: errDummy,
-			cleanupErr: errors.New("deleting rule ip rule 987: from all to all table 456: dummy"),
+			cleanupErr: errors.New("deleting rule ip rule 987: from all to all table 654: dummy"),
 		},
 	}
 
@@ -73,7 +74,7 @@ func Test_Wireguard_addRule(t *testing.T) {
 
 			netLinker.EXPECT().RuleAdd(testCase.expectedRule).
 				Return(testCase.ruleAddErr)
-			cleanup, err := wg.addRule(rulePriority, firewallMark, family)
+			cleanup, err := wg.addRule(rulePriority, firewallMark, table, family)
 			if testCase.err != nil {
 				require.Error(t, err)
 				assert.Equal(t, testCase.err.Error(), err.Error())
@@ -7,7 +7,6 @@ import (
 	"net"
 
 	"github.com/qdm12/gluetun/internal/netlink"
-	"golang.org/x/sys/unix"
 	"golang.zx2c4.com/wireguard/conn"
 	"golang.zx2c4.com/wireguard/device"
 	"golang.zx2c4.com/wireguard/ipc"
@@ -102,31 +101,20 @@ func (w *Wireguard) Run(ctx context.Context, waitError chan<- error, ready chan<
 		return w.netlink.LinkSetDown(link)
 	})
 
-	err = w.addRoutes(link, w.settings.AllowedIPs, w.settings.FirewallMark)
+	err = w.addRoutes(link, w.settings.AllowedIPs, w.settings.RoutingTable)
 	if err != nil {
 		waitError <- fmt.Errorf("%w: %s", ErrRouteAdd, err)
 		return
 	}
 
-	if *w.settings.IPv6 {
-		// requires net.ipv6.conf.all.disable_ipv6=0
-		ruleCleanup6, err := w.addRule(w.settings.RulePriority,
-			w.settings.FirewallMark, unix.AF_INET6)
+	if !w.settings.PolicyRoutingOnly {
+		err = w.addRules(&closers)
 		if err != nil {
-			waitError <- fmt.Errorf("adding IPv6 rule: %w", err)
+			waitError <- err
 			return
 		}
-		closers.add("removing IPv6 rule", stepOne, ruleCleanup6)
 	}
 
-	ruleCleanup, err := w.addRule(w.settings.RulePriority,
-		w.settings.FirewallMark, unix.AF_INET)
-	if err != nil {
-		waitError <- fmt.Errorf("adding IPv4 rule: %w", err)
-		return
-	}
-
-	closers.add("removing IPv4 rule", stepOne, ruleCleanup)
 	w.logger.Info("Wireguard setup is complete. " +
 		"Note Wireguard is a silent protocol and it may or may not work, without giving any error message. " +
 		"Typically i/o timeout errors indicate the Wireguard connection is not working.")
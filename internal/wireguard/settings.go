@@ -42,6 +42,15 @@ type Settings struct {
 	// RulePriority is the priority for the rule created with the
 	// FirewallMark.
 	RulePriority int
+	// RoutingTable is the routing table the routes through
+	// the Wireguard interface are added to.
+	// It defaults to the FirewallMark if left to 0.
+	RoutingTable int
+	// PolicyRoutingOnly disables the rule routing all traffic not
+	// marked with the FirewallMark through the routing table, such
+	// that only traffic selected by rules set elsewhere goes through
+	// the Wireguard interface.
+	PolicyRoutingOnly bool
 	// IPv6 can bet set to true if IPv6 should be handled.
 	// It defaults to false if left unset.
 	IPv6 *bool
@@ -66,6 +75,10 @@ func (s *Settings) SetDefaults() {
 		s.FirewallMark = defaultFirewallMark
 	}
 
+	if s.RoutingTable == 0 {
+		s.RoutingTable = int(s.FirewallMark)
+	}
+
 	if s.MTU == 0 {
 		s.MTU = device.DefaultMTU
 	}
@@ -259,6 +272,14 @@ func (s Settings) ToLines(settings ToLinesSettings) (lines []string) {
 		lines = append(lines, fieldPrefix+"Firewall mark: "+fmt.Sprint(s.FirewallMark))
 	}
 
+	if s.RoutingTable != 0 && s.RoutingTable != int(s.FirewallMark) {
+		lines = append(lines, fieldPrefix+"Routing table: "+fmt.Sprint(s.RoutingTable))
+	}
+
+	if s.PolicyRoutingOnly {
+		lines = append(lines, fieldPrefix+"Policy routing only: yes")
+	}
+
 	if s.MTU != 0 {
 		lines = append(lines, fieldPrefix+"MTU: "+fmt.Sprint(s.MTU))
 	}
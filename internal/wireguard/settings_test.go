@@ -21,6 +21,7 @@ func Test_Settings_SetDefaults(t *testing.T) {
 			expected: Settings{
 				InterfaceName:  "wg0",
 				FirewallMark:   51820,
+				RoutingTable:   51820,
 				AllowedIPs:     []netip.Prefix{allIPv4()},
 				MTU:            device.DefaultMTU,
 				IPv6:           ptr(false),
@@ -34,6 +35,7 @@ func Test_Settings_SetDefaults(t *testing.T) {
 			expected: Settings{
 				InterfaceName:  "wg0",
 				FirewallMark:   51820,
+				RoutingTable:   51820,
 				Endpoint:       netip.AddrPortFrom(netip.AddrFrom4([4]byte{1, 2, 3, 4}), 51820),
 				AllowedIPs:     []netip.Prefix{allIPv4()},
 				MTU:            device.DefaultMTU,
@@ -54,6 +56,7 @@ func Test_Settings_SetDefaults(t *testing.T) {
 			expected: Settings{
 				InterfaceName:  "wg1",
 				FirewallMark:   999,
+				RoutingTable:   999,
 				Endpoint:       netip.AddrPortFrom(netip.AddrFrom4([4]byte{1, 2, 3, 4}), 9999),
 				AllowedIPs:     []netip.Prefix{allIPv4()},
 				MTU:            device.DefaultMTU,